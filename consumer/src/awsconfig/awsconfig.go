@@ -0,0 +1,28 @@
+package awsconfig
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// Load loads the default AWS credential chain, overriding the region and
+// service endpoint when given. The endpoint override is for LocalStack and
+// similar local stand-ins.
+func Load(ctx context.Context, region, endpoint string) (aws.Config, error) {
+	var opts []func(*config.LoadOptions) error
+	if region != "" {
+		opts = append(opts, config.WithRegion(region))
+	}
+	if endpoint != "" {
+		opts = append(opts, config.WithBaseEndpoint(endpoint))
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("loading aws config: %w", err)
+	}
+	return awsCfg, nil
+}
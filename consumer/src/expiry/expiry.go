@@ -0,0 +1,165 @@
+package expiry
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"plane-producer/src/domain"
+)
+
+// Event is written when a flight goes stale, is purged or reports again
+// after going stale. Like the producer's events it is told apart from
+// position reports by its "event" field.
+type Event struct {
+	Event     string    `json:"event"`
+	TailNum   string    `json:"plane"`
+	FlightID  string    `json:"flight"`
+	Timestamp int64     `json:"time"` // unix milliseconds of the last report
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+type entry struct {
+	record domain.FlightRecord
+	seen   time.Time
+	stale  bool
+}
+
+// Stats counts the flights an Expirer is following and what it has done
+// with them.
+type Stats struct {
+	Tracked uint64 `json:"tracked"`
+	Stale   uint64 `json:"stale"`
+	Expired uint64 `json:"expired"`
+	Purged  uint64 `json:"purged"`
+	Resumed uint64 `json:"resumed"`
+}
+
+// Expirer tracks when each flight last reported, by the time of its
+// reports, so that replayed and accelerated streams expire in step with the
+// simulation rather than with the consumer's clock. A flight is stale once
+// it has not reported for StaleAfter and is purged once it has not reported
+// for PurgeAfter; a PurgeAfter of zero never purges. It is safe for
+// concurrent use.
+type Expirer struct {
+	staleAfter, purgeAfter time.Duration
+	wall                   func() time.Time
+
+	mu      sync.Mutex
+	flights map[string]*entry
+	stats   Stats
+	// latest is the time of the newest report, and read when it was read.
+	latest, read time.Time
+}
+
+// New creates an expirer following no flights.
+func New(staleAfter, purgeAfter time.Duration) *Expirer {
+	return &Expirer{staleAfter: staleAfter, purgeAfter: purgeAfter, wall: time.Now, flights: make(map[string]*entry)}
+}
+
+// Observe notes a report as of its own time. If the flight had gone stale
+// it returns a "resumed" event.
+func (e *Expirer) Observe(r domain.FlightRecord) (Event, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	at := r.Time()
+	if at.After(e.latest) {
+		e.latest, e.read = at, e.wall()
+	}
+	f, ok := e.flights[r.FlightID]
+	if !ok {
+		e.flights[r.FlightID] = &entry{record: r, seen: at}
+		return Event{}, false
+	}
+	if r.Timestamp < f.record.Timestamp {
+		// A late report says nothing about whether the flight is still
+		// reporting.
+		return Event{}, false
+	}
+	f.record, f.seen = r, at
+	if !f.stale {
+		return Event{}, false
+	}
+	f.stale = false
+	e.stats.Resumed++
+	return event("resumed", f), true
+}
+
+// Now returns the time of the stream: that of the newest report, moved on by
+// the time since it was read, so that flights still go stale when the
+// whole stream falls silent.
+func (e *Expirer) Now() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.latest.IsZero() {
+		return e.wall()
+	}
+	return e.latest.Add(e.wall().Sub(e.read))
+}
+
+// Sweep returns an event for every flight that has gone stale or is due to
+// be purged as of now, ordered by flight ID. Purged flights are forgotten,
+// so a later report starts following them again.
+func (e *Expirer) Sweep(now time.Time) []Event {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var events []Event
+	for id, f := range e.flights {
+		idle := now.Sub(f.seen)
+		switch {
+		case e.purgeAfter > 0 && idle >= e.purgeAfter:
+			delete(e.flights, id)
+			e.stats.Expired++
+			events = append(events, event("purged", f))
+		case !f.stale && idle >= e.staleAfter:
+			f.stale = true
+			e.stats.Stale++
+			events = append(events, event("stale", f))
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].FlightID < events[j].FlightID })
+	return events
+}
+
+// Purged counts a flight removed from the store after its "purged" event.
+func (e *Expirer) Purged() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stats.Purged++
+}
+
+// Stale returns the IDs of the flights that are stale now, in order.
+func (e *Expirer) Stale() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ids := []string{}
+	for id, f := range e.flights {
+		if f.stale {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Stats returns the counts so far.
+func (e *Expirer) Stats() Stats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	s := e.stats
+	s.Tracked = uint64(len(e.flights))
+	return s
+}
+
+func event(name string, f *entry) Event {
+	return Event{
+		Event:     name,
+		TailNum:   f.record.TailNum,
+		FlightID:  f.record.FlightID,
+		Timestamp: f.record.Timestamp,
+		LastSeen:  f.seen.UTC(),
+	}
+}
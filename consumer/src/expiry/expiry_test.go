@@ -0,0 +1,78 @@
+package expiry
+
+import (
+	"testing"
+	"time"
+
+	"plane-producer/src/domain"
+)
+
+var epoch = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func report(flightID string, at time.Time) domain.FlightRecord {
+	return domain.FlightRecord{TailNum: "N" + flightID, FlightID: flightID, Timestamp: at.UnixMilli()}
+}
+
+// TestExpire follows a flight going stale, resuming and being purged, by
+// the time of its reports.
+func TestExpire(t *testing.T) {
+	e := New(5*time.Minute, 30*time.Minute)
+	e.Observe(report("UT1", epoch))
+	e.Observe(report("UT2", epoch))
+
+	if events := e.Sweep(epoch.Add(4 * time.Minute)); len(events) != 0 {
+		t.Fatalf("events before going stale: %+v", events)
+	}
+	e.Observe(report("UT2", epoch.Add(4*time.Minute)))
+	events := e.Sweep(epoch.Add(5 * time.Minute))
+	if len(events) != 1 || events[0].Event != "stale" || events[0].FlightID != "UT1" {
+		t.Fatalf("got %+v, want UT1 stale", events)
+	}
+	if got := e.Stale(); len(got) != 1 || got[0] != "UT1" {
+		t.Errorf("stale flights %v, want [UT1]", got)
+	}
+	if events := e.Sweep(epoch.Add(6 * time.Minute)); len(events) != 0 {
+		t.Errorf("stale flight reported again: %+v", events)
+	}
+
+	// A late report is no sign of life.
+	if ev, ok := e.Observe(report("UT1", epoch.Add(-time.Minute))); ok {
+		t.Errorf("late report resumed the flight: %+v", ev)
+	}
+	ev, ok := e.Observe(report("UT1", epoch.Add(7*time.Minute)))
+	if !ok || ev.Event != "resumed" || ev.Timestamp != epoch.Add(7*time.Minute).UnixMilli() {
+		t.Errorf("got %+v, %v, want UT1 resumed at its report", ev, ok)
+	}
+
+	events = e.Sweep(epoch.Add(34 * time.Minute))
+	if len(events) != 2 || events[0].Event != "stale" || events[1].Event != "purged" || events[1].FlightID != "UT2" {
+		t.Fatalf("got %+v, want UT1 stale again and UT2 purged", events)
+	}
+	e.Purged()
+	want := Stats{Tracked: 1, Stale: 2, Expired: 1, Purged: 1, Resumed: 1}
+	if got := e.Stats(); got != want {
+		t.Errorf("stats %+v, want %+v", got, want)
+	}
+}
+
+// TestNow checks the stream's time follows its newest report, moving on
+// with the consumer's clock while nothing is read.
+func TestNow(t *testing.T) {
+	wall := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	e := New(5*time.Minute, 0)
+	e.wall = func() time.Time { return wall }
+	if got := e.Now(); !got.Equal(wall) {
+		t.Errorf("before any report: %v, want the wall clock %v", got, wall)
+	}
+
+	e.Observe(report("UT1", epoch))
+	e.Observe(report("UT2", epoch.Add(-time.Hour)))
+	wall = wall.Add(2 * time.Minute)
+	if got, want := e.Now(), epoch.Add(2*time.Minute); !got.Equal(want) {
+		t.Errorf("now %v, want %v", got, want)
+	}
+	wall = wall.Add(3 * time.Minute)
+	if events := e.Sweep(e.Now()); len(events) != 2 {
+		t.Errorf("got %+v, want both flights stale", events)
+	}
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"plane-consumer/src/expiry"
+	"plane-consumer/src/sequence"
+	"plane-consumer/src/store"
+	"plane-consumer/src/stream"
+	"plane-producer/src/api"
+	"plane-producer/src/domain"
+)
+
+func main() {
+	streamName := flag.String("stream", os.Getenv("CONSUMER_STREAM"), "Kinesis stream to read")
+	backend := flag.String("store", "dynamodb", "where to store reports: dynamodb (current positions) or postgres (full history)")
+	table := flag.String("table", os.Getenv("CONSUMER_TABLE"), "DynamoDB table for current positions")
+	geohashIndex := flag.String("geohash-index", os.Getenv("CONSUMER_GEOHASH_INDEX"), "DynamoDB index on geohash for area queries (empty scans instead)")
+	databaseURL := flag.String("database-url", os.Getenv("DATABASE_URL"), "PostgreSQL connection string")
+	region := flag.String("region", os.Getenv("AWS_REGION"), "AWS region")
+	endpoint := flag.String("endpoint", os.Getenv("CONSUMER_ENDPOINT"), "AWS endpoint override, e.g. for LocalStack")
+	from := flag.String("from", string(stream.Latest), "where to start reading: latest or trim-horizon")
+	grpcAddr := flag.String("grpc", os.Getenv("CONSUMER_GRPC_ADDR"), "address to serve the gRPC flight tracker on, e.g. :9090 (empty disables it)")
+	httpAddr := flag.String("http", os.Getenv("CONSUMER_HTTP_ADDR"), "address to serve the flight API on, e.g. :8080 (empty disables it)")
+	staleAfter := flag.Duration("stale-after", envDuration("CONSUMER_STALE_AFTER", 5*time.Minute), "mark a flight stale after this long without reports (0 disables expiry)")
+	purgeAfter := flag.Duration("purge-after", envDuration("CONSUMER_PURGE_AFTER", 30*time.Minute), "remove a flight from the current-position store after this long without reports (0 never purges)")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	reader, err := stream.NewReader(ctx, stream.Config{
+		StreamName: *streamName,
+		Region:     *region,
+		Endpoint:   *endpoint,
+		Start:      stream.Start(*from),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	var reports store.Store
+	switch *backend {
+	case "dynamodb":
+		reports, err = store.NewDynamoDB(ctx, store.DynamoDBConfig{Table: *table, Region: *region, Endpoint: *endpoint, GeohashIndex: *geohashIndex})
+	case "postgres":
+		reports, err = store.NewPostgres(ctx, *databaseURL)
+	default:
+		err = fmt.Errorf("unknown store %q", *backend)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer reports.Close()
+
+	// Sequence gaps, repeats and resets are published with the other
+	// expvar metrics on the flight API's /debug/vars.
+	sequences := sequence.NewMonitor()
+	expvar.Publish("sequence", expvar.Func(func() any { return sequences.Stats() }))
+
+	var expirer *expiry.Expirer
+	if *staleAfter > 0 {
+		expirer = expiry.New(*staleAfter, *purgeAfter)
+		expvar.Publish("expiry", expvar.Func(func() any { return expirer.Stats() }))
+		expvar.Publish("stale", expvar.Func(func() any { return expirer.Stale() }))
+		go expire(ctx, expirer, reports, *staleAfter)
+	}
+
+	feed := api.NewFeed()
+	if *httpAddr != "" {
+		go serveAPI(ctx, *httpAddr, reports, feed)
+	}
+	if *grpcAddr != "" {
+		go func() {
+			log.Printf("serving gRPC flight tracker on %s", *grpcAddr)
+			if err := api.NewGRPCServer(reports, feed, nil).Serve(ctx, *grpcAddr); err != nil {
+				log.Printf("gRPC flight tracker: %v", err)
+			}
+		}()
+	}
+
+	log.Printf("reading %s into %s", *streamName, *backend)
+	err = reader.Run(ctx, func(ctx context.Context, data []byte) error {
+		if !json.Valid(data) {
+			// Producers writing to a stream the consumer reads are held to
+			// plain JSON by sink.kinesis.consumer.
+			log.Printf("skipping a record that is not JSON; the consumer reads only the json sink format")
+			return nil
+		}
+		if isEvent(data) {
+			// Events such as geofence alerts share the stream but are not
+			// positions.
+			return nil
+		}
+		record, err := domain.ParseFlightRecord(data)
+		if err != nil {
+			// Bad records are skipped rather than stopping the stream.
+			log.Print(err)
+			return nil
+		}
+		sequences.Observe(record)
+		if expirer != nil {
+			if ev, ok := expirer.Observe(record); ok {
+				emit(ev)
+			}
+		}
+		if err := reports.Save(ctx, record); err != nil {
+			return err
+		}
+		feed.Publish(record)
+		return nil
+	})
+	if err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatal(err)
+	}
+}
+
+// expire sweeps for flights that have stopped reporting, emitting an event
+// for each that goes stale and purging from the store those that have been
+// silent long enough. Stores that keep the full history are left as they
+// are.
+func expire(ctx context.Context, expirer *expiry.Expirer, reports store.Store, staleAfter time.Duration) {
+	purger, _ := reports.(store.Purger)
+	ticker := time.NewTicker(min(staleAfter/4, time.Minute))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, ev := range expirer.Sweep(expirer.Now()) {
+				if ev.Event == "purged" && purger != nil {
+					purged, err := purger.Purge(ctx, ev.FlightID, ev.Timestamp)
+					if err != nil {
+						log.Print(err)
+						continue
+					}
+					if purged {
+						expirer.Purged()
+					}
+				}
+				emit(ev)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// emit logs an expiry event as JSON.
+func emit(ev expiry.Event) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	log.Printf("expiry: %s", data)
+}
+
+// envDuration reads a duration from the environment, falling back to def
+// when the variable is unset or malformed.
+func envDuration(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("%s: %v, using %s", name, err, def)
+		return def
+	}
+	return d
+}
+
+// serveAPI serves current positions from the store, and live updates as
+// they are read from the stream, until ctx is cancelled.
+func serveAPI(ctx context.Context, addr string, source api.Source, feed *api.Feed) {
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.Handle("/", api.NewHandler(source, feed, nil))
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("serving flight API on %s", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("flight API: %v", err)
+	}
+}
+
+// isEvent reports whether a stream record is an event rather than a
+// position report.
+func isEvent(data []byte) bool {
+	var probe struct {
+		Event string `json:"event"`
+	}
+	return json.Unmarshal(data, &probe) == nil && probe.Event != ""
+}
@@ -0,0 +1,70 @@
+package sequence
+
+import (
+	"sync"
+
+	"plane-producer/src/domain"
+)
+
+// reorderWindow is how far behind the latest sequence number a report may
+// arrive and still be taken as late rather than as a restarted sequence.
+const reorderWindow = 64
+
+// Stats counts what a Monitor has seen. Missing counts reports skipped
+// over, some of which may yet arrive late.
+type Stats struct {
+	Reports    uint64 `json:"reports"`
+	Missing    uint64 `json:"missing"`
+	Duplicates uint64 `json:"duplicates"`
+	Late       uint64 `json:"late"`
+	Resets     uint64 `json:"resets"`
+}
+
+// Monitor follows the sequence numbers of each aircraft's reports to count
+// those missing, repeated and out of order, and the times an aircraft's
+// numbering started again, as it does when a producer restarts or hands
+// the aircraft to another. Reports without a sequence number are ignored.
+// It is safe for concurrent use.
+type Monitor struct {
+	mu    sync.Mutex
+	last  map[string]uint64
+	stats Stats
+}
+
+// NewMonitor creates a monitor that has seen nothing.
+func NewMonitor() *Monitor {
+	return &Monitor{last: make(map[string]uint64)}
+}
+
+// Observe accounts for a report.
+func (m *Monitor) Observe(r domain.FlightRecord) {
+	if r.Sequence == 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stats.Reports++
+	last, ok := m.last[r.TailNum]
+	switch {
+	case !ok:
+		m.last[r.TailNum] = r.Sequence
+	case r.Sequence > last:
+		m.stats.Missing += r.Sequence - last - 1
+		m.last[r.TailNum] = r.Sequence
+	case r.Sequence == last:
+		m.stats.Duplicates++
+	case r.Sequence == 1 || last-r.Sequence > reorderWindow:
+		m.stats.Resets++
+		m.last[r.TailNum] = r.Sequence
+	default:
+		m.stats.Late++
+	}
+}
+
+// Stats returns the counts so far.
+func (m *Monitor) Stats() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stats
+}
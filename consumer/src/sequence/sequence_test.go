@@ -0,0 +1,35 @@
+package sequence
+
+import (
+	"testing"
+
+	"plane-producer/src/domain"
+)
+
+// TestMonitor counts the gaps, repeats, late arrivals and restarts in a
+// sequence of reports.
+func TestMonitor(t *testing.T) {
+	m := NewMonitor()
+	for _, r := range []struct {
+		tail string
+		seq  uint64
+	}{
+		{"N1", 1},
+		{"N1", 2},
+		{"N1", 5}, // 3 and 4 missing
+		{"N1", 5}, // repeated
+		{"N1", 3}, // late
+		{"N2", 0}, // unnumbered, ignored
+		{"N2", 7}, // first seen part way
+		{"N2", 8},
+		{"N2", 1},   // producer restarted
+		{"N1", 200}, // 194 missing
+		{"N1", 100}, // too far back to be late
+	} {
+		m.Observe(domain.FlightRecord{TailNum: r.tail, FlightID: "UT" + r.tail, Sequence: r.seq})
+	}
+	want := Stats{Reports: 10, Missing: 196, Duplicates: 1, Late: 1, Resets: 2}
+	if got := m.Stats(); got != want {
+		t.Errorf("stats %+v, want %+v", got, want)
+	}
+}
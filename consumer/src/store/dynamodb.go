@@ -0,0 +1,340 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"plane-consumer/src/awsconfig"
+	"plane-producer/src/api"
+	"plane-producer/src/domain"
+	"plane-producer/src/geo"
+)
+
+// DynamoDBConfig holds the settings for a DynamoDB position store. The
+// table's partition key must be the string attribute "flightId".
+//
+// GeohashIndex optionally names a global secondary index partitioned on the
+// string attribute "geohash", which area queries use instead of scanning.
+type DynamoDBConfig struct {
+	Table        string
+	Region       string
+	Endpoint     string
+	GeohashIndex string
+}
+
+// geohashPrecision is the length of the geohash stored with each item:
+// cells of about 1.4 by 1.4 degrees.
+const geohashPrecision = 3
+
+// maxIndexQueries is the most index partitions an area query reads before
+// falling back to a scan.
+const maxIndexQueries = 64
+
+type dynamoAPI interface {
+	PutItem(ctx context.Context, in *dynamodb.PutItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	GetItem(ctx context.Context, in *dynamodb.GetItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	Scan(ctx context.Context, in *dynamodb.ScanInput, opts ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	Query(ctx context.Context, in *dynamodb.QueryInput, opts ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+	DeleteItem(ctx context.Context, in *dynamodb.DeleteItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+}
+
+// DynamoDB keeps the latest position of every flight in a DynamoDB table,
+// one item per flight ID. Reports older than the stored one are ignored, so
+// redelivered or out-of-order records never move a flight backwards.
+type DynamoDB struct {
+	client       dynamoAPI
+	table        string
+	geohashIndex string
+}
+
+// NewDynamoDB creates a store using the default AWS credential chain.
+func NewDynamoDB(ctx context.Context, cfg DynamoDBConfig) (*DynamoDB, error) {
+	if cfg.Table == "" {
+		return nil, errors.New("dynamodb: table is required")
+	}
+
+	awsCfg, err := awsconfig.Load(ctx, cfg.Region, cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("dynamodb: %w", err)
+	}
+	return &DynamoDB{client: dynamodb.NewFromConfig(awsCfg), table: cfg.Table, geohashIndex: cfg.GeohashIndex}, nil
+}
+
+// Upsert stores a report as its flight's current position unless a newer
+// one is already stored. It reports whether the item was written.
+func (d *DynamoDB) Upsert(ctx context.Context, r domain.FlightRecord) (bool, error) {
+	_, err := d.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                 aws.String(d.table),
+		Item:                      item(r),
+		ConditionExpression:       aws.String("attribute_not_exists(flightId) OR #time < :time"),
+		ExpressionAttributeNames:  map[string]string{"#time": "time"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{":time": number(float64(r.Timestamp))},
+	})
+
+	var stale *types.ConditionalCheckFailedException
+	switch {
+	case errors.As(err, &stale):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("dynamodb: storing %s: %w", r.FlightID, err)
+	}
+	return true, nil
+}
+
+// Save stores a report as its flight's current position, ignoring it if it
+// is stale.
+func (d *DynamoDB) Save(ctx context.Context, r domain.FlightRecord) error {
+	_, err := d.Upsert(ctx, r)
+	return err
+}
+
+// Flights returns the current position of every flight in the table, ordered
+// by flight ID.
+func (d *DynamoDB) Flights(ctx context.Context) ([]domain.FlightRecord, error) {
+	return d.scan(ctx, &dynamodb.ScanInput{TableName: aws.String(d.table)})
+}
+
+// FlightsIn returns the current position of every flight inside box,
+// ordered by flight ID. With a geohash index it reads the index partitions
+// covering the box, which may include flights just outside it; otherwise it
+// scans the table with a filter.
+func (d *DynamoDB) FlightsIn(ctx context.Context, box api.BBox) ([]domain.FlightRecord, error) {
+	if d.geohashIndex != "" {
+		cells, ok := geo.GeohashesCovering(geo.Degrees(box.MinLat), geo.Degrees(box.MinLon), geo.Degrees(box.MaxLat), geo.Degrees(box.MaxLon), geohashPrecision, maxIndexQueries)
+		if ok {
+			return d.queryCells(ctx, cells)
+		}
+	}
+
+	lon := "#lon BETWEEN :minLon AND :maxLon"
+	if box.MinLon > box.MaxLon {
+		lon = "(#lon >= :minLon OR #lon <= :maxLon)"
+	}
+	return d.scan(ctx, &dynamodb.ScanInput{
+		TableName:                aws.String(d.table),
+		FilterExpression:         aws.String("#lat BETWEEN :minLat AND :maxLat AND " + lon),
+		ExpressionAttributeNames: map[string]string{"#lat": "latitude", "#lon": "longitude"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":minLat": number(box.MinLat),
+			":maxLat": number(box.MaxLat),
+			":minLon": number(box.MinLon),
+			":maxLon": number(box.MaxLon),
+		},
+	})
+}
+
+func (d *DynamoDB) scan(ctx context.Context, in *dynamodb.ScanInput) ([]domain.FlightRecord, error) {
+	var records []domain.FlightRecord
+	paginator := dynamodb.NewScanPaginator(d.client, in)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("dynamodb: scanning %s: %w", d.table, err)
+		}
+		for _, it := range page.Items {
+			r, err := record(it)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, r)
+		}
+	}
+
+	sortRecords(records)
+	return records, nil
+}
+
+func (d *DynamoDB) queryCells(ctx context.Context, cells []string) ([]domain.FlightRecord, error) {
+	var records []domain.FlightRecord
+	for _, cell := range cells {
+		paginator := dynamodb.NewQueryPaginator(d.client, &dynamodb.QueryInput{
+			TableName:                 aws.String(d.table),
+			IndexName:                 aws.String(d.geohashIndex),
+			KeyConditionExpression:    aws.String("geohash = :cell"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{":cell": str(cell)},
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("dynamodb: querying %s: %w", d.geohashIndex, err)
+			}
+			for _, it := range page.Items {
+				r, err := record(it)
+				if err != nil {
+					return nil, err
+				}
+				records = append(records, r)
+			}
+		}
+	}
+
+	sortRecords(records)
+	return records, nil
+}
+
+func sortRecords(records []domain.FlightRecord) {
+	sort.Slice(records, func(i, j int) bool { return records[i].FlightID < records[j].FlightID })
+}
+
+// Flight returns the current position of one flight.
+func (d *DynamoDB) Flight(ctx context.Context, flightID string) (domain.FlightRecord, bool, error) {
+	out, err := d.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(d.table),
+		Key:       map[string]types.AttributeValue{"flightId": str(flightID)},
+	})
+	if err != nil {
+		return domain.FlightRecord{}, false, fmt.Errorf("dynamodb: reading %s: %w", flightID, err)
+	}
+	if out.Item == nil {
+		return domain.FlightRecord{}, false, nil
+	}
+	r, err := record(out.Item)
+	return r, err == nil, err
+}
+
+// Purge deletes a flight's item unless a report newer than before has been
+// stored since.
+func (d *DynamoDB) Purge(ctx context.Context, flightID string, before int64) (bool, error) {
+	_, err := d.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:                 aws.String(d.table),
+		Key:                       map[string]types.AttributeValue{"flightId": str(flightID)},
+		ConditionExpression:       aws.String("#time <= :time"),
+		ExpressionAttributeNames:  map[string]string{"#time": "time"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{":time": number(float64(before))},
+	})
+
+	var newer *types.ConditionalCheckFailedException
+	switch {
+	case errors.As(err, &newer):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("dynamodb: purging %s: %w", flightID, err)
+	}
+	return true, nil
+}
+
+// Close is a no-op; the DynamoDB client holds no resources that need
+// releasing.
+func (d *DynamoDB) Close() error {
+	return nil
+}
+
+func item(r domain.FlightRecord) map[string]types.AttributeValue {
+	it := map[string]types.AttributeValue{
+		"flightId":  str(r.FlightID),
+		"tailNum":   str(r.TailNum),
+		"time":      number(float64(r.Timestamp)),
+		"updatedAt": str(r.Time().Format(time.RFC3339Nano)),
+
+		"latitude":  number(r.Latitude),
+		"longitude": number(r.Longitude),
+		"altitude":  number(r.Altitude),
+
+		"airspeed":          number(r.Airspeed),
+		"indicatedAirspeed": number(r.IndicatedAirspeed),
+		"groundSpeed":       number(r.GroundSpeed),
+		"verticalSpeed":     number(r.VerticalSpeed),
+		"heading":           number(r.Heading),
+
+		"status":    str(r.Status.String()),
+		"emergency": str(r.Emergency.String()),
+
+		"geohash": str(geo.Geohash(geo.Position{Latitude: geo.Degrees(r.Latitude), Longitude: geo.Degrees(r.Longitude)}, geohashPrecision)),
+	}
+	if r.Origin != "" {
+		it["origin"] = str(r.Origin)
+	}
+	if r.Destination != "" {
+		it["destination"] = str(r.Destination)
+	}
+	if r.ETA != 0 {
+		it["eta"] = number(float64(r.ETA))
+	}
+	if r.Squawk != 0 {
+		it["squawk"] = str(r.Squawk.String())
+	}
+	return it
+}
+
+// record is the inverse of item.
+func record(it map[string]types.AttributeValue) (domain.FlightRecord, error) {
+	r := domain.FlightRecord{
+		FlightID:    getStr(it, "flightId"),
+		TailNum:     getStr(it, "tailNum"),
+		Origin:      getStr(it, "origin"),
+		Destination: getStr(it, "destination"),
+	}
+
+	var err error
+	nums := []struct {
+		name string
+		dst  *float64
+	}{
+		{"latitude", &r.Latitude},
+		{"longitude", &r.Longitude},
+		{"altitude", &r.Altitude},
+		{"airspeed", &r.Airspeed},
+		{"groundSpeed", &r.GroundSpeed},
+		{"verticalSpeed", &r.VerticalSpeed},
+		{"heading", &r.Heading},
+	}
+	for _, n := range nums {
+		if *n.dst, err = getNumber(it, n.name); err != nil {
+			return r, fmt.Errorf("dynamodb: item %s: %w", r.FlightID, err)
+		}
+	}
+
+	ts, err := getNumber(it, "time")
+	if err != nil {
+		return r, fmt.Errorf("dynamodb: item %s: %w", r.FlightID, err)
+	}
+	r.Timestamp = int64(ts)
+	if v, err := getNumber(it, "eta"); err == nil {
+		r.ETA = int64(v)
+	}
+	if v, err := getNumber(it, "indicatedAirspeed"); err == nil {
+		r.IndicatedAirspeed = v
+	}
+
+	if err := r.Status.UnmarshalText([]byte(getStr(it, "status"))); err != nil {
+		return r, fmt.Errorf("dynamodb: item %s: %w", r.FlightID, err)
+	}
+	if err := r.Emergency.UnmarshalText([]byte(getStr(it, "emergency"))); err != nil {
+		return r, fmt.Errorf("dynamodb: item %s: %w", r.FlightID, err)
+	}
+	if err := r.Squawk.UnmarshalText([]byte(getStr(it, "squawk"))); err != nil {
+		return r, fmt.Errorf("dynamodb: item %s: %w", r.FlightID, err)
+	}
+	return r, nil
+}
+
+func getStr(it map[string]types.AttributeValue, name string) string {
+	if v, ok := it[name].(*types.AttributeValueMemberS); ok {
+		return v.Value
+	}
+	return ""
+}
+
+func getNumber(it map[string]types.AttributeValue, name string) (float64, error) {
+	v, ok := it[name].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, fmt.Errorf("missing number attribute %q", name)
+	}
+	return strconv.ParseFloat(v.Value, 64)
+}
+
+func str(s string) types.AttributeValue {
+	return &types.AttributeValueMemberS{Value: s}
+}
+
+func number(f float64) types.AttributeValue {
+	return &types.AttributeValueMemberN{Value: strconv.FormatFloat(f, 'f', -1, 64)}
+}
@@ -0,0 +1,266 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"plane-producer/src/api"
+	"plane-producer/src/domain"
+)
+
+// schema creates the report table. Reports are keyed by flight and time so
+// redelivered records are stored once.
+const schema = `
+CREATE TABLE IF NOT EXISTS flight_reports (
+	flight_id      TEXT             NOT NULL,
+	time           TIMESTAMPTZ      NOT NULL,
+	tail_num       TEXT             NOT NULL,
+	origin         TEXT,
+	destination    TEXT,
+	latitude       DOUBLE PRECISION NOT NULL,
+	longitude      DOUBLE PRECISION NOT NULL,
+	altitude       DOUBLE PRECISION NOT NULL,
+	airspeed       DOUBLE PRECISION NOT NULL,
+	ground_speed   DOUBLE PRECISION NOT NULL,
+	vertical_speed DOUBLE PRECISION NOT NULL,
+	heading        DOUBLE PRECISION NOT NULL,
+	status         TEXT             NOT NULL,
+	emergency      TEXT             NOT NULL,
+	eta            TIMESTAMPTZ,
+	indicated_airspeed DOUBLE PRECISION,
+	squawk         TEXT,
+	PRIMARY KEY (flight_id, time)
+)`
+
+// addColumns adds the columns introduced since the table was first created.
+const addColumns = `
+ALTER TABLE flight_reports
+	ADD COLUMN IF NOT EXISTS eta TIMESTAMPTZ,
+	ADD COLUMN IF NOT EXISTS indicated_airspeed DOUBLE PRECISION,
+	ADD COLUMN IF NOT EXISTS squawk TEXT`
+
+// hypertable turns the table into a TimescaleDB hypertable partitioned on
+// time, when the extension is installed.
+const hypertable = `
+DO $$
+BEGIN
+	IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'timescaledb') THEN
+		PERFORM create_hypertable('flight_reports', 'time', if_not_exists => TRUE);
+	END IF;
+END
+$$`
+
+const insertReport = `
+INSERT INTO flight_reports (
+	flight_id, time, tail_num, origin, destination,
+	latitude, longitude, altitude,
+	airspeed, ground_speed, vertical_speed, heading,
+	status, emergency, eta, indicated_airspeed, squawk
+) VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NULLIF($17, ''))
+ON CONFLICT (flight_id, time) DO NOTHING`
+
+// selectLatest picks each flight's most recent report.
+const selectLatest = `
+SELECT DISTINCT ON (flight_id)
+	flight_id, time, tail_num, COALESCE(origin, ''), COALESCE(destination, ''),
+	latitude, longitude, altitude,
+	airspeed, ground_speed, vertical_speed, heading,
+	status, emergency, eta, COALESCE(indicated_airspeed, 0), COALESCE(squawk, '')
+FROM flight_reports`
+
+// selectLatestIn narrows selectLatest to a box, which crosses the
+// antimeridian when its min longitude ($3) is greater than its max ($4).
+const selectLatestIn = `
+SELECT * FROM (` + selectLatest + ` ORDER BY flight_id, time DESC) latest
+WHERE latitude BETWEEN $1 AND $2
+AND CASE WHEN $3::double precision <= $4::double precision
+	THEN longitude BETWEEN $3 AND $4
+	ELSE longitude >= $3 OR longitude <= $4
+END
+ORDER BY flight_id`
+
+const selectTrack = `
+SELECT
+	flight_id, time, tail_num, COALESCE(origin, ''), COALESCE(destination, ''),
+	latitude, longitude, altitude,
+	airspeed, ground_speed, vertical_speed, heading,
+	status, emergency, eta, COALESCE(indicated_airspeed, 0), COALESCE(squawk, '')
+FROM flight_reports
+WHERE flight_id = $1
+ORDER BY time`
+
+// Postgres appends every report to a flight_reports table in PostgreSQL,
+// building up each flight's full track for history queries and analytics.
+// On TimescaleDB the table is created as a hypertable.
+type Postgres struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgres connects to the database at url and creates the report table
+// if it does not exist.
+func NewPostgres(ctx context.Context, url string) (*Postgres, error) {
+	if url == "" {
+		return nil, errors.New("postgres: database url is required")
+	}
+
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: %w", err)
+	}
+	for _, stmt := range []string{schema, addColumns, hypertable} {
+		if _, err := pool.Exec(ctx, stmt); err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("postgres: creating schema: %w", err)
+		}
+	}
+	return &Postgres{pool: pool}, nil
+}
+
+// Save appends a report. A report already stored for the same flight and
+// time is ignored.
+func (p *Postgres) Save(ctx context.Context, r domain.FlightRecord) error {
+	_, err := p.pool.Exec(ctx, insertReport,
+		r.FlightID, r.Time(), r.TailNum, r.Origin, r.Destination,
+		r.Latitude, r.Longitude, r.Altitude,
+		r.Airspeed, r.GroundSpeed, r.VerticalSpeed, r.Heading,
+		r.Status.String(), r.Emergency.String(), eta(r), r.IndicatedAirspeed, r.Squawk.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("postgres: storing %s: %w", r.FlightID, err)
+	}
+	return nil
+}
+
+// Flights returns the latest report of every flight, ordered by flight ID.
+func (p *Postgres) Flights(ctx context.Context) ([]domain.FlightRecord, error) {
+	rows, err := p.pool.Query(ctx, selectLatest+" ORDER BY flight_id, time DESC")
+	if err != nil {
+		return nil, fmt.Errorf("postgres: querying flights: %w", err)
+	}
+	defer rows.Close()
+
+	var records []domain.FlightRecord
+	for rows.Next() {
+		r, err := scanReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres: querying flights: %w", err)
+	}
+	return records, nil
+}
+
+// Flight returns the latest report of one flight.
+func (p *Postgres) Flight(ctx context.Context, flightID string) (domain.FlightRecord, bool, error) {
+	row := p.pool.QueryRow(ctx, selectLatest+" WHERE flight_id = $1 ORDER BY flight_id, time DESC", flightID)
+	r, err := scanReport(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.FlightRecord{}, false, nil
+	}
+	if err != nil {
+		return domain.FlightRecord{}, false, err
+	}
+	return r, true, nil
+}
+
+// Track returns every stored report for a flight, oldest first.
+func (p *Postgres) Track(ctx context.Context, flightID string) ([]domain.FlightRecord, error) {
+	rows, err := p.pool.Query(ctx, selectTrack, flightID)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: querying track of %s: %w", flightID, err)
+	}
+	defer rows.Close()
+
+	var records []domain.FlightRecord
+	for rows.Next() {
+		r, err := scanReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres: querying track of %s: %w", flightID, err)
+	}
+	return records, nil
+}
+
+// FlightsIn returns the latest report of every flight whose last position is
+// inside box, ordered by flight ID.
+func (p *Postgres) FlightsIn(ctx context.Context, box api.BBox) ([]domain.FlightRecord, error) {
+	rows, err := p.pool.Query(ctx, selectLatestIn, box.MinLat, box.MaxLat, box.MinLon, box.MaxLon)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: querying flights: %w", err)
+	}
+	defer rows.Close()
+
+	var records []domain.FlightRecord
+	for rows.Next() {
+		r, err := scanReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("postgres: querying flights: %w", err)
+	}
+	return records, nil
+}
+
+func scanReport(row pgx.Row) (domain.FlightRecord, error) {
+	var (
+		r                 domain.FlightRecord
+		at                time.Time
+		status, emergency string
+		squawk            string
+		arrival           *time.Time
+	)
+	err := row.Scan(
+		&r.FlightID, &at, &r.TailNum, &r.Origin, &r.Destination,
+		&r.Latitude, &r.Longitude, &r.Altitude,
+		&r.Airspeed, &r.GroundSpeed, &r.VerticalSpeed, &r.Heading,
+		&status, &emergency, &arrival, &r.IndicatedAirspeed, &squawk,
+	)
+	if err != nil {
+		return r, fmt.Errorf("postgres: reading report: %w", err)
+	}
+	r.Timestamp = at.UnixMilli()
+	if arrival != nil {
+		r.ETA = arrival.UnixMilli()
+	}
+
+	if err := r.Status.UnmarshalText([]byte(status)); err != nil {
+		return r, fmt.Errorf("postgres: report for %s: %w", r.FlightID, err)
+	}
+	if err := r.Emergency.UnmarshalText([]byte(emergency)); err != nil {
+		return r, fmt.Errorf("postgres: report for %s: %w", r.FlightID, err)
+	}
+	if err := r.Squawk.UnmarshalText([]byte(squawk)); err != nil {
+		return r, fmt.Errorf("postgres: report for %s: %w", r.FlightID, err)
+	}
+	return r, nil
+}
+
+// eta is the report's ETA as a nullable timestamp.
+func eta(r domain.FlightRecord) *time.Time {
+	if r.ETA == 0 {
+		return nil
+	}
+	t := time.UnixMilli(r.ETA).UTC()
+	return &t
+}
+
+// Close closes the connection pool.
+func (p *Postgres) Close() error {
+	p.pool.Close()
+	return nil
+}
@@ -0,0 +1,26 @@
+package store
+
+import (
+	"context"
+
+	"plane-producer/src/domain"
+)
+
+// Store persists flight reports read from the stream.
+type Store interface {
+	Save(ctx context.Context, record domain.FlightRecord) error
+	Close() error
+
+	// Flights and Flight return the latest stored report of every flight or
+	// of one flight, for the position API.
+	Flights(ctx context.Context) ([]domain.FlightRecord, error)
+	Flight(ctx context.Context, flightID string) (domain.FlightRecord, bool, error)
+}
+
+// Purger is implemented by stores holding only the current position of each
+// flight, which can drop flights that have stopped reporting. Purge removes
+// a flight unless it has a report newer than before (unix milliseconds),
+// and reports whether it did.
+type Purger interface {
+	Purge(ctx context.Context, flightID string, before int64) (bool, error)
+}
@@ -0,0 +1,92 @@
+package stream
+
+import (
+	"bytes"
+	"crypto/md5"
+	"errors"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// kplMagic prefixes every KPL aggregated record.
+var kplMagic = []byte{0xF3, 0x89, 0x9A, 0xC2}
+
+// Deaggregate splits a KPL aggregated record into its user records. Records
+// that are not aggregated are returned as they are.
+func Deaggregate(data []byte) ([][]byte, error) {
+	if !bytes.HasPrefix(data, kplMagic) || len(data) < len(kplMagic)+md5.Size {
+		return [][]byte{data}, nil
+	}
+
+	msg := data[len(kplMagic) : len(data)-md5.Size]
+	sum := md5.Sum(msg)
+	if !bytes.Equal(sum[:], data[len(data)-md5.Size:]) {
+		// Not a valid aggregate; KCL treats it as a plain record too.
+		return [][]byte{data}, nil
+	}
+
+	// AggregatedRecord { repeated string partition_key_table = 1;
+	//                    repeated string explicit_hash_key_table = 2;
+	//                    repeated Record records = 3; }
+	var records [][]byte
+	for len(msg) > 0 {
+		num, typ, n := protowire.ConsumeTag(msg)
+		if n < 0 {
+			return nil, fmt.Errorf("deaggregating: %w", protowire.ParseError(n))
+		}
+		msg = msg[n:]
+
+		if num == 3 && typ == protowire.BytesType {
+			rec, n := protowire.ConsumeBytes(msg)
+			if n < 0 {
+				return nil, fmt.Errorf("deaggregating: %w", protowire.ParseError(n))
+			}
+			msg = msg[n:]
+
+			userData, err := recordData(rec)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, userData)
+			continue
+		}
+
+		n = protowire.ConsumeFieldValue(num, typ, msg)
+		if n < 0 {
+			return nil, fmt.Errorf("deaggregating: %w", protowire.ParseError(n))
+		}
+		msg = msg[n:]
+	}
+	return records, nil
+}
+
+// recordData returns the data field of an aggregated Record
+// { uint64 partition_key_index = 1; uint64 explicit_hash_key_index = 2;
+// bytes data = 3; repeated Tag tags = 4; }.
+func recordData(rec []byte) ([]byte, error) {
+	var data []byte
+	found := false
+	for len(rec) > 0 {
+		num, typ, n := protowire.ConsumeTag(rec)
+		if n < 0 {
+			return nil, fmt.Errorf("deaggregating: %w", protowire.ParseError(n))
+		}
+		rec = rec[n:]
+
+		if num == 3 && typ == protowire.BytesType {
+			data, n = protowire.ConsumeBytes(rec)
+			found = true
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, rec)
+		}
+		if n < 0 {
+			return nil, fmt.Errorf("deaggregating: %w", protowire.ParseError(n))
+		}
+		rec = rec[n:]
+	}
+	if !found {
+		return nil, errors.New("deaggregating: record has no data")
+	}
+	return data, nil
+}
@@ -0,0 +1,257 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+
+	"plane-consumer/src/awsconfig"
+)
+
+// Start is where a reader begins reading each shard.
+type Start string
+
+const (
+	// Latest reads only records written after the reader starts.
+	Latest Start = "latest"
+	// TrimHorizon reads every record still retained by the stream.
+	TrimHorizon Start = "trim-horizon"
+)
+
+// Config holds the settings for a Reader.
+type Config struct {
+	StreamName   string
+	Region       string
+	Endpoint     string
+	Start        Start
+	PollInterval time.Duration
+}
+
+// Handler processes one user record. Returning an error stops the reader.
+type Handler func(ctx context.Context, data []byte) error
+
+type kinesisAPI interface {
+	ListShards(ctx context.Context, in *kinesis.ListShardsInput, opts ...func(*kinesis.Options)) (*kinesis.ListShardsOutput, error)
+	GetShardIterator(ctx context.Context, in *kinesis.GetShardIteratorInput, opts ...func(*kinesis.Options)) (*kinesis.GetShardIteratorOutput, error)
+	GetRecords(ctx context.Context, in *kinesis.GetRecordsInput, opts ...func(*kinesis.Options)) (*kinesis.GetRecordsOutput, error)
+}
+
+// Reader reads every shard of a Kinesis stream, deaggregating KPL records
+// and passing each user record to a handler. Shards created by resharding
+// are picked up when their parents close.
+//
+// Positions are not checkpointed, so a restarted reader begins again from
+// its configured Start.
+type Reader struct {
+	client kinesisAPI
+	cfg    Config
+
+	mu      sync.Mutex
+	started map[string]bool
+}
+
+// NewReader creates a reader using the default AWS credential chain.
+func NewReader(ctx context.Context, cfg Config) (*Reader, error) {
+	if cfg.StreamName == "" {
+		return nil, errors.New("kinesis: stream name is required")
+	}
+	if cfg.Start == "" {
+		cfg.Start = Latest
+	}
+	if cfg.Start != Latest && cfg.Start != TrimHorizon {
+		return nil, fmt.Errorf("kinesis: unknown start position %q", cfg.Start)
+	}
+	if cfg.PollInterval <= 0 {
+		// GetRecords is limited to five calls per second per shard.
+		cfg.PollInterval = time.Second
+	}
+
+	awsCfg, err := awsconfig.Load(ctx, cfg.Region, cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("kinesis: %w", err)
+	}
+	return &Reader{client: kinesis.NewFromConfig(awsCfg), cfg: cfg, started: make(map[string]bool)}, nil
+}
+
+// Run reads the stream until ctx is cancelled or handle returns an error.
+func (r *Reader) Run(ctx context.Context, handle Handler) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var wg sync.WaitGroup
+	if err := r.startShards(ctx, &wg, handle, cancel); err != nil {
+		return err
+	}
+	wg.Wait()
+
+	if err := context.Cause(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return ctx.Err()
+}
+
+// startShards starts a goroutine for every open shard not already being
+// read whose parents, if any, have been read.
+func (r *Reader) startShards(ctx context.Context, wg *sync.WaitGroup, handle Handler, fail context.CancelCauseFunc) error {
+	shards, err := r.listShards(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, shard := range shards {
+		id := aws.ToString(shard.ShardId)
+		if r.started[id] {
+			continue
+		}
+		if shard.SequenceNumberRange != nil && shard.SequenceNumberRange.EndingSequenceNumber != nil && r.cfg.Start == Latest {
+			// Closed shards hold nothing new.
+			continue
+		}
+		if parent := aws.ToString(shard.ParentShardId); parent != "" && r.cfg.Start == TrimHorizon && !r.started[parent] && containsShard(shards, parent) {
+			// Read parents first so per-aircraft ordering holds across a
+			// reshard; the child starts when the parent closes.
+			continue
+		}
+
+		r.started[id] = true
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := r.readShard(ctx, id, handle); err != nil {
+				fail(err)
+				return
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			// The shard closed: pick up its children.
+			if err := r.startShards(ctx, wg, handle, fail); err != nil {
+				fail(err)
+			}
+		}()
+	}
+	return nil
+}
+
+func containsShard(shards []types.Shard, id string) bool {
+	for _, s := range shards {
+		if aws.ToString(s.ShardId) == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Reader) listShards(ctx context.Context) ([]types.Shard, error) {
+	var shards []types.Shard
+	in := &kinesis.ListShardsInput{StreamName: aws.String(r.cfg.StreamName)}
+	for {
+		out, err := r.client.ListShards(ctx, in)
+		if err != nil {
+			return nil, fmt.Errorf("kinesis: listing shards: %w", err)
+		}
+		shards = append(shards, out.Shards...)
+		if out.NextToken == nil {
+			return shards, nil
+		}
+		in = &kinesis.ListShardsInput{NextToken: out.NextToken}
+	}
+}
+
+func (r *Reader) iterator(ctx context.Context, shardID, after string) (*string, error) {
+	in := &kinesis.GetShardIteratorInput{
+		StreamName: aws.String(r.cfg.StreamName),
+		ShardId:    aws.String(shardID),
+	}
+	switch {
+	case after != "":
+		in.ShardIteratorType = types.ShardIteratorTypeAfterSequenceNumber
+		in.StartingSequenceNumber = aws.String(after)
+	case r.cfg.Start == TrimHorizon:
+		in.ShardIteratorType = types.ShardIteratorTypeTrimHorizon
+	default:
+		in.ShardIteratorType = types.ShardIteratorTypeLatest
+	}
+
+	out, err := r.client.GetShardIterator(ctx, in)
+	if err != nil {
+		return nil, fmt.Errorf("kinesis: shard %s: getting iterator: %w", shardID, err)
+	}
+	return out.ShardIterator, nil
+}
+
+// readShard reads a shard until it closes or ctx is cancelled.
+func (r *Reader) readShard(ctx context.Context, shardID string, handle Handler) error {
+	var last string
+	it, err := r.iterator(ctx, shardID, "")
+	if err != nil {
+		return err
+	}
+
+	for it != nil {
+		out, err := r.client.GetRecords(ctx, &kinesis.GetRecordsInput{ShardIterator: it})
+		var expired *types.ExpiredIteratorException
+		var throttled *types.ProvisionedThroughputExceededException
+		switch {
+		case errors.As(err, &expired):
+			if it, err = r.iterator(ctx, shardID, last); err != nil {
+				return err
+			}
+			continue
+		case errors.As(err, &throttled):
+			log.Printf("kinesis: shard %s: throttled", shardID)
+			if err := sleep(ctx, r.cfg.PollInterval); err != nil {
+				return nil
+			}
+			continue
+		case err != nil:
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("kinesis: shard %s: getting records: %w", shardID, err)
+		}
+
+		for _, record := range out.Records {
+			userRecords, err := Deaggregate(record.Data)
+			if err != nil {
+				log.Printf("kinesis: shard %s: record %s: %v", shardID, aws.ToString(record.SequenceNumber), err)
+				continue
+			}
+			for _, data := range userRecords {
+				if err := handle(ctx, data); err != nil {
+					return err
+				}
+			}
+			last = aws.ToString(record.SequenceNumber)
+		}
+
+		it = out.NextShardIterator
+		if len(out.Records) == 0 || aws.ToInt64(out.MillisBehindLatest) == 0 {
+			if err := sleep(ctx, r.cfg.PollInterval); err != nil {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,117 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Server is a small HTTP server for orchestration probes. /healthz answers
+// as long as the process is serving; /readyz answers 200 only once every
+// component named at construction, such as "sink" and "schedule", has been
+// marked ready. /debug/vars serves the process's expvar metrics, such as
+// the sink's retry counts.
+type Server struct {
+	srv *http.Server
+
+	mu      sync.Mutex
+	pending map[string]bool
+}
+
+// NewServer creates an admin server listening on addr that waits for the
+// named components before reporting ready.
+func NewServer(addr string, components ...string) *Server {
+	s := &Server{pending: make(map[string]bool)}
+	for _, c := range components {
+		s.pending[c] = true
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.healthz)
+	mux.HandleFunc("/readyz", s.readyz)
+	mux.Handle("/debug/vars", expvar.Handler())
+	s.srv = &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	return s
+}
+
+// Handle registers an extra handler on the admin server.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.srv.Handler.(*http.ServeMux).Handle(pattern, handler)
+}
+
+// MarkReady records that a component is ready.
+func (s *Server) MarkReady(component string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.pending, component)
+}
+
+// MarkNotReady records that a component is no longer ready, e.g. after
+// losing its connection.
+func (s *Server) MarkNotReady(component string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending[component] = true
+}
+
+// Ready reports whether every component is ready, and if not which are
+// still pending.
+func (s *Server) Ready() (bool, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := make([]string, 0, len(s.pending))
+	for c := range s.pending {
+		pending = append(pending, c)
+	}
+	sort.Strings(pending)
+	return len(pending) == 0, pending
+}
+
+func (s *Server) healthz(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"status": "ok"})
+}
+
+func (s *Server) readyz(w http.ResponseWriter, _ *http.Request) {
+	ready, pending := s.Ready()
+	if !ready {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]any{"status": "not ready", "pending": pending})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"status": "ready"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// Run serves until ctx is cancelled, then shuts down gracefully.
+func (s *Server) Run(ctx context.Context) error {
+	errc := make(chan error, 1)
+	go func() {
+		errc <- s.srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
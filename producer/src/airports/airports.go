@@ -0,0 +1,202 @@
+package airports
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// bundled is a subset of the OpenFlights airports.dat covering the major
+// airports the simulator is normally run against. Use Load to read the full
+// file from https://openflights.org/data.html.
+//
+//go:embed airports.dat
+var bundled []byte
+
+// Airport is a single airport from the OpenFlights database.
+type Airport struct {
+	ID        int
+	Name      string
+	City      string
+	Country   string
+	IATA      string
+	ICAO      string
+	Latitude  float64
+	Longitude float64
+	Elevation float64 // feet
+	Timezone  string  // tz database name
+}
+
+// Database indexes airports by IATA and ICAO code.
+type Database struct {
+	airports []Airport
+	byIATA   map[string]int
+	byICAO   map[string]int
+}
+
+var (
+	defaultOnce sync.Once
+	defaultDB   *Database
+)
+
+// Default returns the database of bundled airports.
+func Default() *Database {
+	defaultOnce.Do(func() {
+		airports, err := Parse(bytes.NewReader(bundled))
+		if err != nil {
+			panic(fmt.Sprintf("airports: bundled data is invalid: %v", err))
+		}
+		defaultDB = NewDatabase(airports)
+	})
+	return defaultDB
+}
+
+// Load reads an OpenFlights airports.dat file.
+func Load(path string) (*Database, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	airports, err := Parse(f)
+	if err != nil {
+		return nil, err
+	}
+	return NewDatabase(airports), nil
+}
+
+// NewDatabase indexes the given airports. Airports without an IATA or ICAO
+// code are kept but can only be reached through All.
+func NewDatabase(airports []Airport) *Database {
+	db := &Database{
+		airports: airports,
+		byIATA:   make(map[string]int),
+		byICAO:   make(map[string]int),
+	}
+	for i, a := range airports {
+		if a.IATA != "" {
+			db.byIATA[a.IATA] = i
+		}
+		if a.ICAO != "" {
+			db.byICAO[a.ICAO] = i
+		}
+	}
+	return db
+}
+
+// ByIATA looks up an airport by its three-letter IATA code.
+func (db *Database) ByIATA(code string) (Airport, bool) {
+	i, ok := db.byIATA[strings.ToUpper(code)]
+	if !ok {
+		return Airport{}, false
+	}
+	return db.airports[i], true
+}
+
+// ByICAO looks up an airport by its four-letter ICAO code.
+func (db *Database) ByICAO(code string) (Airport, bool) {
+	i, ok := db.byICAO[strings.ToUpper(code)]
+	if !ok {
+		return Airport{}, false
+	}
+	return db.airports[i], true
+}
+
+// Lookup finds an airport by IATA code, falling back to ICAO.
+func (db *Database) Lookup(code string) (Airport, bool) {
+	if a, ok := db.ByIATA(code); ok {
+		return a, true
+	}
+	return db.ByICAO(code)
+}
+
+// All returns every airport sorted by IATA code, then ICAO code.
+func (db *Database) All() []Airport {
+	all := make([]Airport, len(db.airports))
+	copy(all, db.airports)
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].IATA != all[j].IATA {
+			return all[i].IATA < all[j].IATA
+		}
+		return all[i].ICAO < all[j].ICAO
+	})
+	return all
+}
+
+// Parse reads airports in the OpenFlights airports.dat format: headerless
+// CSV with "\N" marking missing values.
+func Parse(r io.Reader) ([]Airport, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	var airports []Airport
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("airports: %w", err)
+		}
+
+		line, _ := cr.FieldPos(0)
+		a, err := parseRow(row)
+		if err != nil {
+			return nil, fmt.Errorf("airports: line %d: %w", line, err)
+		}
+		airports = append(airports, a)
+	}
+	return airports, nil
+}
+
+func parseRow(row []string) (Airport, error) {
+	if len(row) < 12 {
+		return Airport{}, fmt.Errorf("expected at least 12 fields, got %d", len(row))
+	}
+	for i, v := range row {
+		if v == `\N` {
+			row[i] = ""
+		}
+	}
+
+	id, err := strconv.Atoi(row[0])
+	if err != nil {
+		return Airport{}, fmt.Errorf("invalid id %q", row[0])
+	}
+	lat, err := strconv.ParseFloat(row[6], 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return Airport{}, fmt.Errorf("invalid latitude %q", row[6])
+	}
+	long, err := strconv.ParseFloat(row[7], 64)
+	if err != nil || long < -180 || long > 180 {
+		return Airport{}, fmt.Errorf("invalid longitude %q", row[7])
+	}
+	var elevation float64
+	if row[8] != "" {
+		if elevation, err = strconv.ParseFloat(row[8], 64); err != nil {
+			return Airport{}, fmt.Errorf("invalid elevation %q", row[8])
+		}
+	}
+
+	return Airport{
+		ID:        id,
+		Name:      row[1],
+		City:      row[2],
+		Country:   row[3],
+		IATA:      row[4],
+		ICAO:      row[5],
+		Latitude:  lat,
+		Longitude: long,
+		Elevation: elevation,
+		Timezone:  row[11],
+	}, nil
+}
@@ -0,0 +1,45 @@
+package airports
+
+import (
+	"sort"
+
+	"plane-producer/src/geo"
+)
+
+// Position returns the airport's location.
+func (a Airport) Position() geo.Position {
+	return geo.Position{Latitude: geo.Degrees(a.Latitude), Longitude: geo.Degrees(a.Longitude)}
+}
+
+// Nearby is an airport with its great-circle distance and initial bearing
+// from some position.
+type Nearby struct {
+	Airport
+	DistanceNm float64
+	Bearing    geo.Degrees
+}
+
+// Nearest returns the n airports closest to p, nearest first. Airports
+// without an IATA or ICAO code are skipped, since nothing can route to them.
+func (db *Database) Nearest(p geo.Position, n int) []Nearby {
+	if n <= 0 {
+		return nil
+	}
+
+	all := make([]Nearby, 0, len(db.airports))
+	for _, a := range db.airports {
+		if a.IATA == "" && a.ICAO == "" {
+			continue
+		}
+		all = append(all, Nearby{Airport: a, DistanceNm: geo.Distance(p, a.Position())})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].DistanceNm < all[j].DistanceNm })
+
+	if len(all) > n {
+		all = all[:n]
+	}
+	for i := range all {
+		all[i].Bearing = geo.InitialBearing(p, all[i].Position())
+	}
+	return all
+}
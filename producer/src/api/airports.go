@@ -0,0 +1,64 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"plane-producer/src/geo"
+)
+
+// defaultNearest is how many airports /airports/nearest returns when n is
+// not given.
+const defaultNearest = 5
+
+// NearbyAirport is an airport with its distance and bearing from the
+// queried position.
+type NearbyAirport struct {
+	IATA       string  `json:"iata,omitempty"`
+	ICAO       string  `json:"icao,omitempty"`
+	Name       string  `json:"name"`
+	City       string  `json:"city"`
+	Country    string  `json:"country"`
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	DistanceNm float64 `json:"distanceNm"`
+	BearingDeg float64 `json:"bearingDeg"`
+}
+
+// nearestAirports serves the n airports closest to lat/long.
+func (h *Handler) nearestAirports(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	lat, errLat := strconv.ParseFloat(q.Get("lat"), 64)
+	long, errLong := strconv.ParseFloat(q.Get("long"), 64)
+	if errLat != nil || errLong != nil || lat < -90 || lat > 90 || long < -180 || long > 180 {
+		writeError(w, http.StatusBadRequest, errors.New("lat and long must be valid coordinates in degrees"))
+		return
+	}
+
+	n := defaultNearest
+	if s := q.Get("n"); s != "" {
+		var err error
+		if n, err = strconv.Atoi(s); err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, errors.New("n must be a positive integer"))
+			return
+		}
+	}
+
+	nearby := h.airports.Nearest(geo.Position{Latitude: geo.Degrees(lat), Longitude: geo.Degrees(long)}, n)
+	result := make([]NearbyAirport, len(nearby))
+	for i, a := range nearby {
+		result[i] = NearbyAirport{
+			IATA:       a.IATA,
+			ICAO:       a.ICAO,
+			Name:       a.Name,
+			City:       a.City,
+			Country:    a.Country,
+			Latitude:   a.Latitude,
+			Longitude:  a.Longitude,
+			DistanceNm: a.DistanceNm,
+			BearingDeg: float64(a.Bearing),
+		}
+	}
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,357 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"plane-producer/src/airports"
+	"plane-producer/src/domain"
+	"plane-producer/src/geo"
+)
+
+// Source provides the latest report for each flight.
+type Source interface {
+	Flights(ctx context.Context) ([]domain.FlightRecord, error)
+	Flight(ctx context.Context, flightID string) (domain.FlightRecord, bool, error)
+}
+
+// AreaSource is implemented by sources that can look flights up by
+// location more cheaply than listing them all.
+type AreaSource interface {
+	// FlightsIn returns the latest report of every flight inside box. It may
+	// also return flights just outside it.
+	FlightsIn(ctx context.Context, box BBox) ([]domain.FlightRecord, error)
+}
+
+// indexPrecision is the geohash length the tracker indexes flights by:
+// cells of about 1.4 by 1.4 degrees.
+const indexPrecision = 3
+
+// maxIndexCells is the most cells an area query looks up before it is
+// quicker to go through every flight.
+const maxIndexCells = 1024
+
+// Tracker is an in-memory Source holding the latest report of every flight
+// it has been given until it is removed, indexed by geohash cell for area
+// queries. It counts each airport's traffic from the reports as they come.
+type Tracker struct {
+	mu      sync.RWMutex
+	latest  map[string]domain.FlightRecord
+	cell    map[string]string
+	index   map[string]map[string]struct{}
+	traffic *Traffic
+}
+
+// NewTracker creates an empty tracker.
+func NewTracker() *Tracker {
+	return &Tracker{
+		latest:  make(map[string]domain.FlightRecord),
+		cell:    make(map[string]string),
+		index:   make(map[string]map[string]struct{}),
+		traffic: NewTraffic(),
+	}
+}
+
+// Update records a report unless a newer one for the flight is already
+// held.
+func (t *Tracker) Update(record domain.FlightRecord) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	id := record.FlightID
+	prev, ok := t.latest[id]
+	if ok && prev.Timestamp > record.Timestamp {
+		return
+	}
+	t.traffic.Observe(prev, ok, record)
+	t.latest[id] = record
+
+	cell := geo.Geohash(geo.Position{Latitude: geo.Degrees(record.Latitude), Longitude: geo.Degrees(record.Longitude)}, indexPrecision)
+	if old, ok := t.cell[id]; ok {
+		if old == cell {
+			return
+		}
+		delete(t.index[old], id)
+		if len(t.index[old]) == 0 {
+			delete(t.index, old)
+		}
+	}
+	t.cell[id] = cell
+	if t.index[cell] == nil {
+		t.index[cell] = make(map[string]struct{})
+	}
+	t.index[cell][id] = struct{}{}
+}
+
+// Remove forgets a flight, once it has arrived or been taken out of the
+// simulation, so that the tracker only holds flights still flying.
+func (t *Tracker) Remove(flightID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.latest, flightID)
+	if cell, ok := t.cell[flightID]; ok {
+		delete(t.cell, flightID)
+		delete(t.index[cell], flightID)
+		if len(t.index[cell]) == 0 {
+			delete(t.index, cell)
+		}
+	}
+}
+
+// Flights returns the latest report of every flight, ordered by flight ID.
+func (t *Tracker) Flights(context.Context) ([]domain.FlightRecord, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	records := make([]domain.FlightRecord, 0, len(t.latest))
+	for _, r := range t.latest {
+		records = append(records, r)
+	}
+	sortRecords(records)
+	return records, nil
+}
+
+// FlightsIn returns the latest report of every flight in the geohash cells
+// covering box, ordered by flight ID.
+func (t *Tracker) FlightsIn(ctx context.Context, box BBox) ([]domain.FlightRecord, error) {
+	cells, ok := geo.GeohashesCovering(geo.Degrees(box.MinLat), geo.Degrees(box.MinLon), geo.Degrees(box.MaxLat), geo.Degrees(box.MaxLon), indexPrecision, maxIndexCells)
+	if !ok {
+		return t.Flights(ctx)
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	var records []domain.FlightRecord
+	for _, cell := range cells {
+		for id := range t.index[cell] {
+			records = append(records, t.latest[id])
+		}
+	}
+	sortRecords(records)
+	return records, nil
+}
+
+// Flight returns the latest report of one flight.
+func (t *Tracker) Flight(_ context.Context, flightID string) (domain.FlightRecord, bool, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	r, ok := t.latest[flightID]
+	return r, ok, nil
+}
+
+// Traffic returns each airport's traffic over window.
+func (t *Tracker) Traffic(_ context.Context, window time.Duration) (TrafficReport, error) {
+	return t.traffic.Report(window), nil
+}
+
+func sortRecords(records []domain.FlightRecord) {
+	sort.Slice(records, func(i, j int) bool { return records[i].FlightID < records[j].FlightID })
+}
+
+// Flight is the API view of a flight, with full field names and units
+// rather than the abbreviated stream record.
+type Flight struct {
+	FlightID    string           `json:"flightId"`
+	TailNum     string           `json:"tailNum"`
+	Origin      string           `json:"origin,omitempty"`
+	Destination string           `json:"destination,omitempty"`
+	Status      domain.Status    `json:"status"`
+	Emergency   domain.Emergency `json:"emergency"`
+	Squawk      domain.Squawk    `json:"squawk,omitempty"`
+	UpdatedAt   time.Time        `json:"updatedAt"`
+
+	Position Position `json:"position"`
+	Speed    Speed    `json:"speed"`
+	Heading  float64  `json:"headingDeg"`
+
+	RemainingNm *float64   `json:"remainingNm,omitempty"`
+	ETA         *time.Time `json:"eta,omitempty"`
+}
+
+// Position is a location in degrees with altitude in feet.
+type Position struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Altitude  float64 `json:"altitudeFt"`
+}
+
+// Speed holds true and indicated airspeed and ground speed in knots and
+// vertical speed in feet per minute.
+type Speed struct {
+	Airspeed          float64 `json:"airspeedKt"`
+	IndicatedAirspeed float64 `json:"indicatedAirspeedKt"`
+	GroundSpeed       float64 `json:"groundSpeedKt"`
+	VerticalSpeed     float64 `json:"verticalSpeedFpm"`
+}
+
+// Handler serves the flight API:
+//
+//	GET /flights             every flight's latest position, filtered as
+//	                         ParseFilter describes
+//	GET /flights/{id}        one flight
+//	GET /flights/{id}/track  recorded track, if the source keeps one
+//	GET /flights/live        WebSocket stream of updates
+//	GET /flights/stream      Server-Sent Events stream of updates
+//	GET /airports/nearest    closest airports to lat/long
+//	GET /airports/traffic    departures, arrivals and airborne flights per
+//	                         airport, if the source counts them
+//	GET /airports/{code}/traffic  the same for one airport
+//	GET /routes/preview      planned route between two airports
+type Handler struct {
+	source   Source
+	feed     *Feed
+	airports *airports.Database
+	mux      *http.ServeMux
+}
+
+// NewHandler creates the API handler. Live updates are served from feed,
+// and are unavailable if it is nil. Airports are used to work out distance
+// remaining and ETA; nil uses the bundled database.
+func NewHandler(source Source, feed *Feed, db *airports.Database) *Handler {
+	if db == nil {
+		db = airports.Default()
+	}
+	h := &Handler{source: source, feed: feed, airports: db, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /flights", h.list)
+	h.mux.HandleFunc("GET /flights/{id}", h.get)
+	h.mux.HandleFunc("GET /flights/{id}/track", h.track)
+	h.mux.HandleFunc("GET /airports/nearest", h.nearestAirports)
+	h.mux.HandleFunc("GET /airports/traffic", h.traffic)
+	h.mux.HandleFunc("GET /airports/{code}/traffic", h.traffic)
+	h.mux.HandleFunc("GET /routes/preview", h.routePreview)
+	if feed != nil {
+		h.mux.HandleFunc("GET /flights/live", h.live)
+		h.mux.HandleFunc("GET /flights/stream", h.stream)
+	}
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// list serves every flight's latest position, narrowed by the same query
+// parameters as ParseFilter.
+func (h *Handler) list(w http.ResponseWriter, r *http.Request) {
+	filter, err := ParseFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	records, err := h.flights(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	flights := make([]Flight, 0, len(records))
+	for _, record := range records {
+		flights = append(flights, h.View(record))
+	}
+	writeJSON(w, http.StatusOK, flights)
+}
+
+// flights returns the latest reports matching filter, using the source's
+// area lookup when the filter has a spatial part.
+func (h *Handler) flights(ctx context.Context, filter Filter) ([]domain.FlightRecord, error) {
+	var records []domain.FlightRecord
+	var err error
+	area, indexed := h.source.(AreaSource)
+	if box, spatial := filter.Bounds(); spatial && indexed {
+		records, err = area.FlightsIn(ctx, box)
+	} else {
+		records, err = h.source.Flights(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	matched := records[:0]
+	for _, r := range records {
+		if filter.Match(r) {
+			matched = append(matched, r)
+		}
+	}
+	return matched, nil
+}
+
+func (h *Handler) get(w http.ResponseWriter, r *http.Request) {
+	record, ok, err := h.source.Flight(r.Context(), r.PathValue("id"))
+	switch {
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	case !ok:
+		writeError(w, http.StatusNotFound, errors.New("flight not found"))
+	default:
+		writeJSON(w, http.StatusOK, h.View(record))
+	}
+}
+
+// View converts a stream record to its API form. ETA is the record's own
+// estimate; for records without one it is estimated from the great-circle
+// distance left at the current ground speed.
+func (h *Handler) View(r domain.FlightRecord) Flight {
+	f := Flight{
+		FlightID:    r.FlightID,
+		TailNum:     r.TailNum,
+		Origin:      r.Origin,
+		Destination: r.Destination,
+		Status:      r.Status,
+		Emergency:   r.Emergency,
+		Squawk:      r.Squawk,
+		UpdatedAt:   r.Time(),
+		Position:    Position{Latitude: r.Latitude, Longitude: r.Longitude, Altitude: r.Altitude},
+		Speed: Speed{
+			Airspeed:          r.Airspeed,
+			IndicatedAirspeed: r.IndicatedAirspeed,
+			GroundSpeed:       r.GroundSpeed,
+			VerticalSpeed:     r.VerticalSpeed,
+		},
+		Heading: r.Heading,
+	}
+	if r.ETA != 0 {
+		eta := time.UnixMilli(r.ETA).UTC()
+		f.ETA = &eta
+	}
+
+	dest, ok := h.airports.Lookup(r.Destination)
+	if !ok {
+		return f
+	}
+	here := geo.Position{Latitude: geo.Degrees(r.Latitude), Longitude: geo.Degrees(r.Longitude)}
+	remaining := geo.Distance(here, dest.Position())
+	f.RemainingNm = &remaining
+
+	// On the ground the speed says nothing about the trip, so only estimate
+	// arrival once airborne.
+	if f.ETA == nil && airborne(r.Status) && r.GroundSpeed > 0 {
+		eta := f.UpdatedAt.Add(time.Duration(remaining / r.GroundSpeed * float64(time.Hour)))
+		f.ETA = &eta
+	}
+	return f
+}
+
+func airborne(s domain.Status) bool {
+	return s != domain.Idle && s != domain.Taxi
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("api: writing response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
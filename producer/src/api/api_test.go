@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"plane-producer/src/domain"
+)
+
+// TestTrackerRemove checks a removed flight is gone from the tracker and
+// its area index, and the others are kept.
+func TestTrackerRemove(t *testing.T) {
+	tr := NewTracker()
+	tr.Update(domain.FlightRecord{TailNum: "N1UT", FlightID: "UT1", Timestamp: 1, Latitude: 40.6, Longitude: -73.8})
+	tr.Update(domain.FlightRecord{TailNum: "N2UT", FlightID: "UT2", Timestamp: 1, Latitude: 42.4, Longitude: -71.0})
+	tr.Remove("UT1")
+	tr.Remove("UT3")
+
+	ctx := context.Background()
+	if _, ok, _ := tr.Flight(ctx, "UT1"); ok {
+		t.Error("removed flight still tracked")
+	}
+	if got, _ := tr.Flights(ctx); len(got) != 1 || got[0].FlightID != "UT2" {
+		t.Errorf("flights %+v, want only UT2", got)
+	}
+	if got, _ := tr.FlightsIn(ctx, BBox{MinLat: 40, MinLon: -75, MaxLat: 41, MaxLon: -73}); len(got) != 0 {
+		t.Errorf("removed flight found in its area: %+v", got)
+	}
+	if len(tr.cell) != 1 || len(tr.index) != 1 {
+		t.Errorf("%d cells and %d index entries left, want 1 each", len(tr.cell), len(tr.index))
+	}
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"sync"
+
+	"plane-producer/src/domain"
+)
+
+// feedBuffer is how many updates a subscriber may fall behind before new
+// ones are dropped for it.
+const feedBuffer = 256
+
+// Feed fans flight reports out to live subscribers.
+type Feed struct {
+	mu   sync.Mutex
+	subs map[*subscription]struct{}
+}
+
+type subscription struct {
+	filter  Filter
+	updates chan domain.FlightRecord
+}
+
+// NewFeed creates a feed with no subscribers.
+func NewFeed() *Feed {
+	return &Feed{subs: make(map[*subscription]struct{})}
+}
+
+// Publish sends a report to every subscriber whose filter it matches. It
+// never blocks: a subscriber that is too far behind misses the update.
+func (f *Feed) Publish(r domain.FlightRecord) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for s := range f.subs {
+		if !s.filter.Match(r) {
+			continue
+		}
+		select {
+		case s.updates <- r:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel of reports matching filter and a function
+// that ends the subscription and closes the channel.
+func (f *Feed) Subscribe(filter Filter) (<-chan domain.FlightRecord, func()) {
+	s := &subscription{filter: filter, updates: make(chan domain.FlightRecord, feedBuffer)}
+
+	f.mu.Lock()
+	f.subs[s] = struct{}{}
+	f.mu.Unlock()
+
+	var once sync.Once
+	return s.updates, func() {
+		once.Do(func() {
+			f.mu.Lock()
+			delete(f.subs, s)
+			f.mu.Unlock()
+			close(s.updates)
+		})
+	}
+}
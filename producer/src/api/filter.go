@@ -0,0 +1,188 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"plane-producer/src/domain"
+	"plane-producer/src/geo"
+)
+
+// Filter selects which flights a client is interested in. The zero Filter
+// matches everything.
+type Filter struct {
+	FlightID string
+	// Airline matches flight IDs starting with this prefix, e.g. "UTP".
+	Airline string
+	BBox    *BBox
+	Radius  *Circle
+}
+
+// BBox is a latitude/longitude box in degrees. A box whose MinLon is greater
+// than its MaxLon crosses the antimeridian.
+type BBox struct {
+	MinLat, MinLon, MaxLat, MaxLon float64
+}
+
+// Contains reports whether a point lies within the box.
+func (b BBox) Contains(lat, lon float64) bool {
+	if lat < b.MinLat || lat > b.MaxLat {
+		return false
+	}
+	if b.MinLon <= b.MaxLon {
+		return lon >= b.MinLon && lon <= b.MaxLon
+	}
+	return lon >= b.MinLon || lon <= b.MaxLon
+}
+
+// Circle is the area within RadiusNm nautical miles of a point.
+type Circle struct {
+	Latitude, Longitude float64
+	RadiusNm            float64
+}
+
+// Contains reports whether a point lies within the circle.
+func (c Circle) Contains(lat, lon float64) bool {
+	return geo.Distance(c.center(), geo.Position{Latitude: geo.Degrees(lat), Longitude: geo.Degrees(lon)}) <= c.RadiusNm
+}
+
+func (c Circle) center() geo.Position {
+	return geo.Position{Latitude: geo.Degrees(c.Latitude), Longitude: geo.Degrees(c.Longitude)}
+}
+
+// Bounds returns a box enclosing the circle.
+func (c Circle) Bounds() BBox {
+	dLat := c.RadiusNm / 60
+	box := BBox{MinLat: c.Latitude - dLat, MaxLat: c.Latitude + dLat, MinLon: -180, MaxLon: 180}
+	if box.MinLat <= -90 || box.MaxLat >= 90 {
+		// The circle covers a pole, so every longitude.
+		box.MinLat, box.MaxLat = math.Max(box.MinLat, -90), math.Min(box.MaxLat, 90)
+		return box
+	}
+
+	// Widest at whichever edge is nearer a pole.
+	widest := math.Max(math.Abs(box.MinLat), math.Abs(box.MaxLat))
+	dLon := dLat / math.Cos(widest*math.Pi/180)
+	if dLon >= 180 {
+		return box
+	}
+	box.MinLon = float64(geo.Degrees(c.Longitude-dLon+180).Normalized()) - 180
+	box.MaxLon = float64(geo.Degrees(c.Longitude+dLon+180).Normalized()) - 180
+	return box
+}
+
+// Bounds returns a box that every flight the filter matches lies within, or
+// false if the filter has no spatial part.
+func (f Filter) Bounds() (BBox, bool) {
+	switch {
+	case f.BBox != nil:
+		return *f.BBox, true
+	case f.Radius != nil:
+		return f.Radius.Bounds(), true
+	}
+	return BBox{}, false
+}
+
+// ParseFilter reads a filter from the query parameters flightId, airline,
+// bbox (minLat,minLon,maxLat,maxLon), and near (lat,long) with radiusNm.
+func ParseFilter(q url.Values) (Filter, error) {
+	f := Filter{FlightID: q.Get("flightId"), Airline: q.Get("airline")}
+	if s := q.Get("bbox"); s != "" {
+		box, err := parseBBox(s)
+		if err != nil {
+			return Filter{}, err
+		}
+		f.BBox = &box
+	}
+	if s := q.Get("near"); s != "" {
+		circle, err := parseCircle(s, q.Get("radiusNm"))
+		if err != nil {
+			return Filter{}, err
+		}
+		f.Radius = &circle
+	}
+	return f, nil
+}
+
+func parseFloats(s string, n int) ([]float64, bool) {
+	parts := strings.Split(s, ",")
+	if len(parts) != n {
+		return nil, false
+	}
+	v := make([]float64, n)
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return nil, false
+		}
+		v[i] = f
+	}
+	return v, true
+}
+
+func parseBBox(s string) (BBox, error) {
+	v, ok := parseFloats(s, 4)
+	if !ok {
+		return BBox{}, errors.New("bbox must be minLat,minLon,maxLat,maxLon")
+	}
+	box := BBox{MinLat: v[0], MinLon: v[1], MaxLat: v[2], MaxLon: v[3]}
+	if err := box.validate(); err != nil {
+		return BBox{}, err
+	}
+	return box, nil
+}
+
+func (b BBox) validate() error {
+	switch {
+	case b.MinLat < -90 || b.MaxLat > 90 || b.MinLat > b.MaxLat:
+		return fmt.Errorf("bbox: latitudes must be within -90..90 with min below max")
+	case b.MinLon < -180 || b.MaxLon > 180:
+		return fmt.Errorf("bbox: longitudes must be within -180..180")
+	}
+	return nil
+}
+
+func parseCircle(near, radius string) (Circle, error) {
+	v, ok := parseFloats(near, 2)
+	if !ok {
+		return Circle{}, errors.New("near must be lat,long")
+	}
+	r, err := strconv.ParseFloat(radius, 64)
+	if err != nil {
+		return Circle{}, errors.New("near needs a radiusNm")
+	}
+	c := Circle{Latitude: v[0], Longitude: v[1], RadiusNm: r}
+	if err := c.validate(); err != nil {
+		return Circle{}, err
+	}
+	return c, nil
+}
+
+func (c Circle) validate() error {
+	switch {
+	case c.Latitude < -90 || c.Latitude > 90 || c.Longitude < -180 || c.Longitude > 180:
+		return errors.New("near: coordinates out of range")
+	case c.RadiusNm <= 0:
+		return errors.New("near: radiusNm must be positive")
+	}
+	return nil
+}
+
+// Match reports whether a record passes the filter.
+func (f Filter) Match(r domain.FlightRecord) bool {
+	switch {
+	case f.FlightID != "" && r.FlightID != f.FlightID:
+		return false
+	case f.Airline != "" && !strings.HasPrefix(r.FlightID, f.Airline):
+		return false
+	case f.BBox != nil && !f.BBox.Contains(r.Latitude, r.Longitude):
+		return false
+	case f.Radius != nil && !f.Radius.Contains(r.Latitude, r.Longitude):
+		return false
+	}
+	return true
+}
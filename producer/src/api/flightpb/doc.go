@@ -0,0 +1,5 @@
+// Package flightpb holds the generated protobuf and gRPC code for the flight
+// tracker API.
+package flightpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative flights.proto
@@ -0,0 +1,207 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: flights.proto
+
+package flightpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FlightTracker_GetFlight_FullMethodName    = "/flighttracker.v1.FlightTracker/GetFlight"
+	FlightTracker_ListFlights_FullMethodName  = "/flighttracker.v1.FlightTracker/ListFlights"
+	FlightTracker_WatchFlights_FullMethodName = "/flighttracker.v1.FlightTracker/WatchFlights"
+)
+
+// FlightTrackerClient is the client API for FlightTracker service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FlightTracker serves the latest position of every tracked flight and a
+// live stream of updates.
+type FlightTrackerClient interface {
+	GetFlight(ctx context.Context, in *GetFlightRequest, opts ...grpc.CallOption) (*Flight, error)
+	ListFlights(ctx context.Context, in *ListFlightsRequest, opts ...grpc.CallOption) (*ListFlightsResponse, error)
+	WatchFlights(ctx context.Context, in *WatchFlightsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Flight], error)
+}
+
+type flightTrackerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFlightTrackerClient(cc grpc.ClientConnInterface) FlightTrackerClient {
+	return &flightTrackerClient{cc}
+}
+
+func (c *flightTrackerClient) GetFlight(ctx context.Context, in *GetFlightRequest, opts ...grpc.CallOption) (*Flight, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Flight)
+	err := c.cc.Invoke(ctx, FlightTracker_GetFlight_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flightTrackerClient) ListFlights(ctx context.Context, in *ListFlightsRequest, opts ...grpc.CallOption) (*ListFlightsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFlightsResponse)
+	err := c.cc.Invoke(ctx, FlightTracker_ListFlights_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flightTrackerClient) WatchFlights(ctx context.Context, in *WatchFlightsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Flight], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FlightTracker_ServiceDesc.Streams[0], FlightTracker_WatchFlights_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchFlightsRequest, Flight]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FlightTracker_WatchFlightsClient = grpc.ServerStreamingClient[Flight]
+
+// FlightTrackerServer is the server API for FlightTracker service.
+// All implementations must embed UnimplementedFlightTrackerServer
+// for forward compatibility.
+//
+// FlightTracker serves the latest position of every tracked flight and a
+// live stream of updates.
+type FlightTrackerServer interface {
+	GetFlight(context.Context, *GetFlightRequest) (*Flight, error)
+	ListFlights(context.Context, *ListFlightsRequest) (*ListFlightsResponse, error)
+	WatchFlights(*WatchFlightsRequest, grpc.ServerStreamingServer[Flight]) error
+	mustEmbedUnimplementedFlightTrackerServer()
+}
+
+// UnimplementedFlightTrackerServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFlightTrackerServer struct{}
+
+func (UnimplementedFlightTrackerServer) GetFlight(context.Context, *GetFlightRequest) (*Flight, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFlight not implemented")
+}
+func (UnimplementedFlightTrackerServer) ListFlights(context.Context, *ListFlightsRequest) (*ListFlightsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFlights not implemented")
+}
+func (UnimplementedFlightTrackerServer) WatchFlights(*WatchFlightsRequest, grpc.ServerStreamingServer[Flight]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchFlights not implemented")
+}
+func (UnimplementedFlightTrackerServer) mustEmbedUnimplementedFlightTrackerServer() {}
+func (UnimplementedFlightTrackerServer) testEmbeddedByValue()                       {}
+
+// UnsafeFlightTrackerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FlightTrackerServer will
+// result in compilation errors.
+type UnsafeFlightTrackerServer interface {
+	mustEmbedUnimplementedFlightTrackerServer()
+}
+
+func RegisterFlightTrackerServer(s grpc.ServiceRegistrar, srv FlightTrackerServer) {
+	// If the following call pancis, it indicates UnimplementedFlightTrackerServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FlightTracker_ServiceDesc, srv)
+}
+
+func _FlightTracker_GetFlight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFlightRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlightTrackerServer).GetFlight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlightTracker_GetFlight_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlightTrackerServer).GetFlight(ctx, req.(*GetFlightRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlightTracker_ListFlights_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFlightsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlightTrackerServer).ListFlights(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlightTracker_ListFlights_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlightTrackerServer).ListFlights(ctx, req.(*ListFlightsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlightTracker_WatchFlights_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchFlightsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FlightTrackerServer).WatchFlights(m, &grpc.GenericServerStream[WatchFlightsRequest, Flight]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FlightTracker_WatchFlightsServer = grpc.ServerStreamingServer[Flight]
+
+// FlightTracker_ServiceDesc is the grpc.ServiceDesc for FlightTracker service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FlightTracker_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "flighttracker.v1.FlightTracker",
+	HandlerType: (*FlightTrackerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetFlight",
+			Handler:    _FlightTracker_GetFlight_Handler,
+		},
+		{
+			MethodName: "ListFlights",
+			Handler:    _FlightTracker_ListFlights_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchFlights",
+			Handler:       _FlightTracker_WatchFlights_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "flights.proto",
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"plane-producer/src/airports"
+	"plane-producer/src/api/flightpb"
+	"plane-producer/src/domain"
+)
+
+// GRPCServer implements the FlightTracker gRPC service over the same source
+// and feed as the HTTP API.
+type GRPCServer struct {
+	flightpb.UnimplementedFlightTrackerServer
+	views *Handler
+}
+
+// NewGRPCServer creates the gRPC service. WatchFlights is unavailable if
+// feed is nil.
+func NewGRPCServer(source Source, feed *Feed, db *airports.Database) *GRPCServer {
+	return &GRPCServer{views: NewHandler(source, feed, db)}
+}
+
+// Serve registers the service on a new gRPC server and serves on addr until
+// ctx is cancelled.
+func (s *GRPCServer) Serve(ctx context.Context, addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	srv := grpc.NewServer()
+	flightpb.RegisterFlightTrackerServer(srv, s)
+	go func() {
+		<-ctx.Done()
+		srv.GracefulStop()
+	}()
+
+	if err := srv.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+func (s *GRPCServer) GetFlight(ctx context.Context, req *flightpb.GetFlightRequest) (*flightpb.Flight, error) {
+	record, ok, err := s.views.source.Flight(ctx, req.GetFlightId())
+	switch {
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	case !ok:
+		return nil, status.Errorf(codes.NotFound, "flight %q not found", req.GetFlightId())
+	}
+	return s.message(record), nil
+}
+
+func (s *GRPCServer) ListFlights(ctx context.Context, req *flightpb.ListFlightsRequest) (*flightpb.ListFlightsResponse, error) {
+	filter, err := protoFilter(req.GetFilter())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	records, err := s.views.flights(ctx, filter)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &flightpb.ListFlightsResponse{}
+	for _, record := range records {
+		resp.Flights = append(resp.Flights, s.message(record))
+	}
+	return resp, nil
+}
+
+func (s *GRPCServer) WatchFlights(req *flightpb.WatchFlightsRequest, stream grpc.ServerStreamingServer[flightpb.Flight]) error {
+	if s.views.feed == nil {
+		return status.Error(codes.Unimplemented, "live updates are not available")
+	}
+	filter, err := protoFilter(req.GetFilter())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	updates, unsubscribe := s.views.feed.Subscribe(filter)
+	defer unsubscribe()
+
+	for {
+		select {
+		case record := <-updates:
+			if err := stream.Send(s.message(record)); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+func (s *GRPCServer) message(r domain.FlightRecord) *flightpb.Flight {
+	f := s.views.View(r)
+	m := &flightpb.Flight{
+		FlightId:            f.FlightID,
+		TailNum:             f.TailNum,
+		Origin:              f.Origin,
+		Destination:         f.Destination,
+		Status:              flightpb.Status(f.Status + 1),
+		Emergency:           flightpb.Emergency(f.Emergency),
+		Squawk:              f.Squawk.String(),
+		UpdatedAt:           timestamppb.New(f.UpdatedAt),
+		Latitude:            f.Position.Latitude,
+		Longitude:           f.Position.Longitude,
+		AltitudeFt:          f.Position.Altitude,
+		AirspeedKt:          f.Speed.Airspeed,
+		IndicatedAirspeedKt: f.Speed.IndicatedAirspeed,
+		GroundSpeedKt:       f.Speed.GroundSpeed,
+		VerticalSpeedFpm:    f.Speed.VerticalSpeed,
+		HeadingDeg:          f.Heading,
+	}
+	if f.RemainingNm != nil {
+		m.RemainingNm = proto.Float64(*f.RemainingNm)
+	}
+	if f.ETA != nil {
+		m.Eta = timestamppb.New(*f.ETA)
+	}
+	return m
+}
+
+func protoFilter(f *flightpb.Filter) (Filter, error) {
+	filter := Filter{FlightID: f.GetFlightId(), Airline: f.GetAirline()}
+	if b := f.GetBbox(); b != nil {
+		box := BBox{MinLat: b.GetMinLat(), MinLon: b.GetMinLon(), MaxLat: b.GetMaxLat(), MaxLon: b.GetMaxLon()}
+		if err := box.validate(); err != nil {
+			return Filter{}, err
+		}
+		filter.BBox = &box
+	}
+	if c := f.GetRadius(); c != nil {
+		circle := Circle{Latitude: c.GetLatitude(), Longitude: c.GetLongitude(), RadiusNm: c.GetRadiusNm()}
+		if err := circle.validate(); err != nil {
+			return Filter{}, err
+		}
+		filter.Radius = &circle
+	}
+	return filter, nil
+}
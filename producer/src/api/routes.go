@@ -0,0 +1,83 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"plane-producer/src/geo"
+)
+
+const (
+	// defaultRoutePoints is how many points /routes/preview returns when
+	// points is not given.
+	defaultRoutePoints = 64
+	maxRoutePoints     = 4096
+)
+
+// RoutePreview is a planned route between two airports, sampled at evenly
+// spaced points for drawing on a map.
+type RoutePreview struct {
+	Origin      string         `json:"origin"`
+	Destination string         `json:"destination"`
+	Navigation  geo.Navigation `json:"navigation"`
+	EarthModel  geo.EarthModel `json:"earthModel"`
+	LengthNm    float64        `json:"lengthNm"`
+	CourseDeg   float64        `json:"courseDeg"`
+	Points      []geo.Position `json:"points"`
+}
+
+// routePreview serves the route a flight between from and to would plan.
+// navigation and earthModel choose how, as in the simulation config, and
+// points how many positions to sample along it.
+func (h *Handler) routePreview(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	origin, ok := h.airports.Lookup(q.Get("from"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unknown airport %q", q.Get("from")))
+		return
+	}
+	destination, ok := h.airports.Lookup(q.Get("to"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unknown airport %q", q.Get("to")))
+		return
+	}
+	nav, err := geo.ParseNavigation(q.Get("navigation"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	model, err := geo.ParseEarthModel(q.Get("earthModel"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	n := defaultRoutePoints
+	if s := q.Get("points"); s != "" {
+		if n, err = strconv.Atoi(s); err != nil || n < 2 || n > maxRoutePoints {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("points must be an integer from 2 to %d", maxRoutePoints))
+			return
+		}
+	}
+	if origin.Position() == destination.Position() {
+		writeError(w, http.StatusBadRequest, errors.New("origin and destination are the same"))
+		return
+	}
+
+	route := nav.NewRoute(model, origin.Position(), destination.Position())
+	if nav == geo.Rhumb {
+		model = geo.Sphere
+	}
+	writeJSON(w, http.StatusOK, RoutePreview{
+		Origin:      strings.ToUpper(q.Get("from")),
+		Destination: strings.ToUpper(q.Get("to")),
+		Navigation:  nav,
+		EarthModel:  model,
+		LengthNm:    route.Length(),
+		CourseDeg:   float64(route.CourseAt(0)),
+		Points:      geo.Sample(route, n),
+	})
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"plane-producer/src/domain"
+	"plane-producer/src/geo"
+)
+
+// TrackSource is implemented by sources that keep every report, not just
+// the latest, and can return a flight's recorded track.
+type TrackSource interface {
+	// Track returns every report for a flight in time order, or none if
+	// the flight is unknown.
+	Track(ctx context.Context, flightID string) ([]domain.FlightRecord, error)
+}
+
+// Track is a flight's recorded path.
+type Track struct {
+	FlightID    string       `json:"flightId"`
+	TailNum     string       `json:"tailNum"`
+	Origin      string       `json:"origin,omitempty"`
+	Destination string       `json:"destination,omitempty"`
+	Points      []TrackPoint `json:"points"`
+}
+
+// TrackPoint is one timestamped position along a track.
+type TrackPoint struct {
+	Time        time.Time     `json:"time"`
+	Latitude    float64       `json:"latitude"`
+	Longitude   float64       `json:"longitude"`
+	Altitude    float64       `json:"altitudeFt"`
+	GroundSpeed float64       `json:"groundSpeedKt"`
+	Heading     float64       `json:"headingDeg"`
+	Status      domain.Status `json:"status"`
+}
+
+// track serves a flight's recorded track. It can be thinned with either
+// every=N, keeping every Nth point, or tolerance=NM, a Douglas–Peucker
+// simplification keeping the path within NM nautical miles of the original.
+// The first and last points are always kept.
+func (h *Handler) track(w http.ResponseWriter, r *http.Request) {
+	tracks, ok := h.source.(TrackSource)
+	if !ok {
+		writeError(w, http.StatusNotImplemented, errors.New("track history is not kept by this server"))
+		return
+	}
+
+	every, tolerance, err := parseDownsampling(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	records, err := tracks.Track(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if len(records) == 0 {
+		writeError(w, http.StatusNotFound, errors.New("flight not found"))
+		return
+	}
+
+	switch {
+	case every > 1:
+		records = keepEvery(records, every)
+	case tolerance > 0:
+		records = simplify(records, tolerance)
+	}
+
+	last := records[len(records)-1]
+	t := Track{
+		FlightID:    last.FlightID,
+		TailNum:     last.TailNum,
+		Origin:      last.Origin,
+		Destination: last.Destination,
+		Points:      make([]TrackPoint, len(records)),
+	}
+	for i, rec := range records {
+		t.Points[i] = TrackPoint{
+			Time:        rec.Time(),
+			Latitude:    rec.Latitude,
+			Longitude:   rec.Longitude,
+			Altitude:    rec.Altitude,
+			GroundSpeed: rec.GroundSpeed,
+			Heading:     rec.Heading,
+			Status:      rec.Status,
+		}
+	}
+	writeJSON(w, http.StatusOK, t)
+}
+
+func parseDownsampling(r *http.Request) (every int, tolerance float64, err error) {
+	q := r.URL.Query()
+	if s := q.Get("every"); s != "" {
+		if every, err = strconv.Atoi(s); err != nil || every < 1 {
+			return 0, 0, fmt.Errorf("every must be a positive integer")
+		}
+	}
+	if s := q.Get("tolerance"); s != "" {
+		if tolerance, err = strconv.ParseFloat(s, 64); err != nil || tolerance < 0 {
+			return 0, 0, fmt.Errorf("tolerance must be a non-negative number of nautical miles")
+		}
+	}
+	if every > 1 && tolerance > 0 {
+		return 0, 0, fmt.Errorf("use either every or tolerance, not both")
+	}
+	return every, tolerance, nil
+}
+
+func keepEvery(records []domain.FlightRecord, n int) []domain.FlightRecord {
+	var kept []domain.FlightRecord
+	for i := 0; i < len(records); i += n {
+		kept = append(kept, records[i])
+	}
+	if (len(records)-1)%n != 0 {
+		kept = append(kept, records[len(records)-1])
+	}
+	return kept
+}
+
+func simplify(records []domain.FlightRecord, toleranceNm float64) []domain.FlightRecord {
+	points := make([]geo.Position, len(records))
+	for i, r := range records {
+		points[i] = geo.Position{Latitude: geo.Degrees(r.Latitude), Longitude: geo.Degrees(r.Longitude)}
+	}
+
+	indices := geo.Simplify(points, toleranceNm)
+	kept := make([]domain.FlightRecord, len(indices))
+	for i, idx := range indices {
+		kept[i] = records[idx]
+	}
+	return kept
+}
@@ -0,0 +1,183 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"plane-producer/src/domain"
+)
+
+// defaultTrafficWindow is the window traffic counts cover when none is
+// asked for, and maxTrafficWindow the longest kept.
+const (
+	defaultTrafficWindow = time.Hour
+	maxTrafficWindow     = 24 * time.Hour
+)
+
+// TrafficSource is implemented by sources that count departures and
+// arrivals as they see flights take off and land.
+type TrafficSource interface {
+	// Traffic returns the counts for every airport with traffic in the
+	// window ending at the latest report seen, ordered by airport.
+	Traffic(ctx context.Context, window time.Duration) (TrafficReport, error)
+}
+
+// TrafficReport is the traffic at each airport over a window.
+type TrafficReport struct {
+	From     time.Time        `json:"from"`
+	To       time.Time        `json:"to"`
+	Airports []AirportTraffic `json:"airports"`
+}
+
+// AirportTraffic counts the flights that took off from or landed at an
+// airport in a window, and those in the air now to or from it.
+type AirportTraffic struct {
+	Airport          string `json:"airport"`
+	Departures       int    `json:"departures"`
+	Arrivals         int    `json:"arrivals"`
+	AirborneOutbound int    `json:"airborneOutbound"`
+	AirborneInbound  int    `json:"airborneInbound"`
+}
+
+type movement struct {
+	airport string
+	arrival bool
+	at      int64 // unix milliseconds
+}
+
+// Traffic counts departures and arrivals from consecutive reports of each
+// flight: a departure when it starts its take-off roll and an arrival when
+// it turns off the runway after landing. Movements older than
+// maxTrafficWindow are dropped. It is safe for concurrent use.
+type Traffic struct {
+	mu        sync.Mutex
+	movements []movement
+	airborne  map[string]domain.FlightRecord
+	latest    int64
+}
+
+// NewTraffic creates a counter that has seen no traffic.
+func NewTraffic() *Traffic {
+	return &Traffic{airborne: make(map[string]domain.FlightRecord)}
+}
+
+// Observe accounts for a report r following prev, the flight's previous
+// report, if known.
+func (t *Traffic) Observe(prev domain.FlightRecord, known bool, r domain.FlightRecord) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if r.Timestamp > t.latest {
+		t.latest = r.Timestamp
+	}
+	switch {
+	case known && !airborne(prev.Status) && r.Status == domain.TakeOff:
+		t.movements = append(t.movements, movement{airport: r.Origin, at: r.Timestamp})
+	case known && airborne(prev.Status) && !airborne(r.Status):
+		t.movements = append(t.movements, movement{airport: r.Destination, arrival: true, at: r.Timestamp})
+	}
+	if airborne(r.Status) {
+		t.airborne[r.FlightID] = r
+	} else {
+		delete(t.airborne, r.FlightID)
+	}
+
+	cutoff := t.latest - maxTrafficWindow.Milliseconds()
+	i := 0
+	for i < len(t.movements) && t.movements[i].at < cutoff {
+		i++
+	}
+	t.movements = t.movements[i:]
+}
+
+// Report returns the traffic in the window ending at the latest report.
+func (t *Traffic) Report(window time.Duration) TrafficReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	from := t.latest - window.Milliseconds()
+	counts := make(map[string]*AirportTraffic)
+	at := func(airport string) *AirportTraffic {
+		c, ok := counts[airport]
+		if !ok {
+			c = &AirportTraffic{Airport: airport}
+			counts[airport] = c
+		}
+		return c
+	}
+	for _, m := range t.movements {
+		if m.at < from || m.airport == "" {
+			continue
+		}
+		if m.arrival {
+			at(m.airport).Arrivals++
+		} else {
+			at(m.airport).Departures++
+		}
+	}
+	for _, r := range t.airborne {
+		if r.Origin != "" {
+			at(r.Origin).AirborneOutbound++
+		}
+		if r.Destination != "" {
+			at(r.Destination).AirborneInbound++
+		}
+	}
+
+	report := TrafficReport{
+		From:     time.UnixMilli(from).UTC(),
+		To:       time.UnixMilli(t.latest).UTC(),
+		Airports: make([]AirportTraffic, 0, len(counts)),
+	}
+	for _, c := range counts {
+		report.Airports = append(report.Airports, *c)
+	}
+	sort.Slice(report.Airports, func(i, j int) bool { return report.Airports[i].Airport < report.Airports[j].Airport })
+	return report
+}
+
+// traffic serves departure, arrival and airborne counts for every airport,
+// or for the one named in the path, over window (default one hour).
+func (h *Handler) traffic(w http.ResponseWriter, r *http.Request) {
+	source, ok := h.source.(TrafficSource)
+	if !ok {
+		writeError(w, http.StatusNotImplemented, errors.New("traffic is not counted by this server"))
+		return
+	}
+
+	window := defaultTrafficWindow
+	if s := r.URL.Query().Get("window"); s != "" {
+		var err error
+		if window, err = time.ParseDuration(s); err != nil || window <= 0 || window > maxTrafficWindow {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("window must be a duration up to %s", maxTrafficWindow))
+			return
+		}
+	}
+
+	report, err := source.Traffic(r.Context(), window)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	code := strings.ToUpper(r.PathValue("code"))
+	if code == "" {
+		writeJSON(w, http.StatusOK, report)
+		return
+	}
+
+	// An airport without traffic is still a valid answer, with zero counts.
+	one := AirportTraffic{Airport: code}
+	for _, a := range report.Airports {
+		if a.Airport == code {
+			one = a
+		}
+	}
+	report.Airports = []AirportTraffic{one}
+	writeJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"testing"
+	"text/tabwriter"
+	"time"
+
+	"plane-producer/src/config"
+	"plane-producer/src/domain"
+	"plane-producer/src/encoder"
+	"plane-producer/src/geo"
+	"plane-producer/src/sim"
+)
+
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	configPath := fs.String("config", "", "YAML configuration file")
+	fleetSize := fs.Int("fleet", 10000, "number of aircraft in the load scenario")
+	ticks := fs.Int("ticks", 60, "number of report intervals the load scenario is timed over")
+	warmup := fs.Duration("warmup", 20*time.Minute, "simulated time flown before timing, so most of the fleet is airborne")
+	fs.Parse(args)
+	if *fleetSize < 1 || *ticks < 1 {
+		return errors.New("-fleet and -ticks must be at least 1")
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return err
+	}
+	world, err := loadWorld(cfg)
+	if err != nil {
+		return err
+	}
+	enc, err := encoder.New(cfg.Sink.Format)
+	if err != nil {
+		return err
+	}
+
+	random := sim.NewRandom(cfg.Simulation.Seed)
+	start := time.Now()
+	step := cfg.Simulation.ReportInterval
+
+	flights, err := generateFleet(*fleetSize, 1, world.airports, random.For("fleet"), start)
+	if err != nil {
+		return err
+	}
+	scheduler := sim.NewScheduler(sim.NewClock(start, 1), step)
+	scheduler.SetWorkers(cfg.Simulation.Workers)
+	for i, f := range flights {
+		f.FlightID = fmt.Sprintf("UTP%d", i+1)
+		fl, err := world.newFlight(f, start, random)
+		if err != nil {
+			return err
+		}
+		scheduler.Add(fl)
+	}
+
+	now := start
+	discard := func(domain.FlightRecord) {}
+	for ; now.Sub(start) < *warmup; now = now.Add(step) {
+		scheduler.Tick(now, discard, nil)
+	}
+	if scheduler.Len() == 0 {
+		return fmt.Errorf("every flight arrived within the %v warmup", *warmup)
+	}
+
+	// The micro benchmarks work on an aircraft of their own, flown to
+	// cruise first so its step and report are the common case. It is
+	// stepped a millisecond at a time so that it is still cruising however
+	// many iterations they take.
+	f := flights[0]
+	f.FlightID = "BENCH1"
+	sample, err := world.newFlight(f, start, random)
+	if err != nil {
+		return err
+	}
+	t := start
+	for sample.Plane().Status() != domain.Cruising && t.Sub(start) < 3*time.Hour {
+		t = t.Add(step)
+		sample.Step(t, step)
+	}
+	record := sample.Report()
+	from, to := geo.Position{Latitude: 40.6398, Longitude: -73.7789}, geo.Position{Latitude: 51.4706, Longitude: -0.4619}
+
+	type benchmark struct {
+		name string
+		fn   func(b *testing.B)
+	}
+	benchmarks := []benchmark{
+		{"flight step", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				t = t.Add(time.Millisecond)
+				sample.Step(t, time.Millisecond)
+			}
+		}},
+		{"flight report", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				sample.Report()
+			}
+		}},
+		{"encode " + cfg.Sink.Format, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				enc.Encode(record)
+			}
+		}},
+		{"geo distance", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				geo.Distance(from, to)
+			}
+		}},
+		{"geo destination", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				geo.Destination(from, 51, 100)
+			}
+		}},
+		{"geo track deviation", func(b *testing.B) {
+			track := geo.NewTrack(from, to)
+			p := track.PositionAt(1000)
+			for i := 0; i < b.N; i++ {
+				track.Deviation(p, 60)
+			}
+		}},
+		// The baseline the encoders are measured against.
+		{"encoding/json", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				json.Marshal(record)
+			}
+		}},
+	}
+	if appender, ok := enc.(encoder.Appender); ok {
+		benchmarks = append(benchmarks, benchmark{"append " + cfg.Sink.Format, func(b *testing.B) {
+			var buf []byte
+			for i := 0; i < b.N; i++ {
+				buf, _ = appender.Append(buf[:0], record)
+			}
+		}})
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "BENCHMARK\tN\tNS/OP\tB/OP\tALLOCS/OP\t")
+	for _, b := range benchmarks {
+		r := testing.Benchmark(func(tb *testing.B) {
+			tb.ReportAllocs()
+			b.fn(tb)
+		})
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t\n", b.name, r.N, r.NsPerOp(), r.AllocedBytesPerOp(), r.AllocsPerOp())
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	// The load scenario times whole ticks of the fleet, encoding every
+	// report as the simulate command would, and compares them with the
+	// report interval they must fit in to keep up in real time.
+	var reports, bytes int
+	emit := func(r domain.FlightRecord) {
+		data, err := enc.Encode(r)
+		if err != nil {
+			return
+		}
+		reports++
+		bytes += len(data)
+	}
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	durations := make([]time.Duration, 0, *ticks)
+	for i := 0; i < *ticks; i++ {
+		now = now.Add(step)
+		began := time.Now()
+		scheduler.Tick(now, emit, nil)
+		durations = append(durations, time.Since(began))
+	}
+	runtime.ReadMemStats(&after)
+
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	p99 := durations[len(durations)*99/100]
+	if len(durations) < 100 {
+		p99 = durations[len(durations)-1]
+	}
+	mean := total / time.Duration(len(durations))
+
+	fmt.Printf("\nload: %d aircraft (%d still flying), %d ticks of %v, %d reports of %d bytes on average\n",
+		*fleetSize, scheduler.Len(), len(durations), step, reports, bytes/max(reports, 1))
+	fmt.Printf("tick: mean %v, p99 %v, max %v (%.1f%% of the interval at p99)\n",
+		mean.Round(time.Microsecond), p99.Round(time.Microsecond), durations[len(durations)-1].Round(time.Microsecond),
+		100*float64(p99)/float64(step))
+	fmt.Printf("heap: %d allocations, %.1f MB allocated per tick, %d GC cycles, %d goroutines\n",
+		(after.Mallocs-before.Mallocs)/uint64(len(durations)),
+		float64(after.TotalAlloc-before.TotalAlloc)/float64(len(durations))/1e6,
+		after.NumGC-before.NumGC, runtime.NumGoroutine())
+	if p99 > step {
+		return fmt.Errorf("ticks took up to %v, longer than the %v report interval", p99, step)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package capture
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Metadata describes the run a capture was taken from, enough to tell
+// whether another run can reproduce it.
+type Metadata struct {
+	Seed       int64     `json:"seed"`
+	ConfigHash string    `json:"configHash"`
+	Start      time.Time `json:"start"`
+	Speed      float64   `json:"speed"`
+}
+
+// header is the first line of a capture file, told apart from the records
+// after it by its "capture" field.
+type header struct {
+	Capture Metadata `json:"capture"`
+}
+
+// Writer records every report and event a run emits, as JSON lines after a
+// metadata header, for the replay command to send again. It is safe for
+// concurrent use.
+type Writer struct {
+	mu   sync.Mutex
+	file *os.File
+	w    *bufio.Writer
+}
+
+// Create starts a capture at path, replacing any file already there.
+func Create(path string, meta Metadata) (*Writer, error) {
+	line, err := json.Marshal(header{Capture: meta})
+	if err != nil {
+		return nil, fmt.Errorf("capture: %w", err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("capture: %w", err)
+	}
+	c := &Writer{file: file, w: bufio.NewWriter(file)}
+	if err := c.Write(line); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Write appends one JSON record as a line.
+func (c *Writer) Write(data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.w.Write(data); err != nil {
+		return fmt.Errorf("capture: %w", err)
+	}
+	if err := c.w.WriteByte('\n'); err != nil {
+		return fmt.Errorf("capture: %w", err)
+	}
+	return nil
+}
+
+// Close flushes the capture and closes its file.
+func (c *Writer) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.w.Flush(); err != nil {
+		c.file.Close()
+		return fmt.Errorf("capture: %w", err)
+	}
+	return c.file.Close()
+}
+
+// ParseHeader reads the metadata from a capture's first line, reporting
+// false if the line is an ordinary record instead.
+func ParseHeader(line []byte) (Metadata, bool) {
+	var h struct {
+		Capture *Metadata `json:"capture"`
+	}
+	if err := json.Unmarshal(line, &h); err != nil || h.Capture == nil {
+		return Metadata{}, false
+	}
+	return *h.Capture, true
+}
@@ -0,0 +1,616 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"plane-producer/src/encoder"
+	"plane-producer/src/geo"
+	"plane-producer/src/schedule"
+	"plane-producer/src/sink"
+	"plane-producer/src/units"
+)
+
+// EnvPrefix prefixes every environment variable override. Nested settings
+// join their env names with underscores, e.g. PRODUCER_SINK_KINESIS_STREAM.
+const EnvPrefix = "PRODUCER_"
+
+// Config is the producer's configuration. Settings are read from a YAML file
+// and then overridden by environment variables.
+type Config struct {
+	Schedule    string `yaml:"schedule" env:"SCHEDULE"`
+	Airports    string `yaml:"airports" env:"AIRPORTS"`
+	Performance string `yaml:"performance" env:"PERFORMANCE"`
+	TaxiTimes   string `yaml:"taxiTimes" env:"TAXI_TIMES"`
+	Weather     string `yaml:"weather" env:"WEATHER"`
+	Geofences   string `yaml:"geofences" env:"GEOFENCES"`
+
+	// Capture, when set, is a file every emitted record is also written
+	// to, headed by the seed and configuration, for the replay command.
+	Capture string `yaml:"capture" env:"CAPTURE"`
+
+	// ShutdownGrace bounds how long the final reports and sink flush may
+	// take after a shutdown signal.
+	ShutdownGrace time.Duration `yaml:"shutdownGrace" env:"SHUTDOWN_GRACE"`
+
+	Simulation Simulation `yaml:"simulation" env:"SIM"`
+	Reporting  Reporting  `yaml:"reporting" env:"REPORTING"`
+	Sink       Sink       `yaml:"sink" env:"SINK"`
+	Admin      Admin      `yaml:"admin" env:"ADMIN"`
+	GRPC       GRPC       `yaml:"grpc" env:"GRPC"`
+	Shard      Shard      `yaml:"shard" env:"SHARD"`
+}
+
+// Simulation controls how flights are simulated. Speed is the time
+// acceleration factor and Seed fixes the random source; zero picks one.
+// Navigation is how routes are planned (greatCircle or rhumb) for flights
+// whose schedule entry does not say, and EarthModel is the shape they are
+// planned on (sphere or wgs84). Flights without a flight ID are given a
+// callsign from one of Airlines, e.g. UTP123. Turnaround is how long an
+// aircraft stays at the gate between the legs of a rotation. Workers is how
+// many aircraft are stepped at once; zero uses one per CPU. Noise adds
+// sensor error to reports. GoAroundRate is the chance of each approach
+// ending in a go-around. Epoch is the simulated time the run starts at,
+// in RFC 3339, and the present if empty; Timezone is the IANA zone
+// simulated times are given in.
+type Simulation struct {
+	Speed          float64       `yaml:"speed" env:"SPEED"`
+	Seed           int64         `yaml:"seed" env:"SEED"`
+	ReportInterval time.Duration `yaml:"reportInterval" env:"REPORT_INTERVAL"`
+	Turnaround     time.Duration `yaml:"turnaround" env:"TURNAROUND"`
+	Navigation     string        `yaml:"navigation" env:"NAVIGATION"`
+	EarthModel     string        `yaml:"earthModel" env:"EARTH_MODEL"`
+	Airlines       []string      `yaml:"airlines" env:"AIRLINES"`
+	Workers        int           `yaml:"workers" env:"WORKERS"`
+	Noise          Noise         `yaml:"noise" env:"NOISE"`
+	GoAroundRate   float64       `yaml:"goAroundRate" env:"GO_AROUND_RATE"`
+	Epoch          string        `yaml:"epoch" env:"EPOCH"`
+	Timezone       string        `yaml:"timezone" env:"TIMEZONE"`
+}
+
+// Location returns the time zone simulated times are given in.
+func (s Simulation) Location() (*time.Location, error) {
+	return time.LoadLocation(s.Timezone)
+}
+
+// Start returns the simulated time a run starting at now begins at, in the
+// configured time zone.
+func (s Simulation) Start(now time.Time) (time.Time, error) {
+	loc, err := s.Location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if s.Epoch != "" {
+		if now, err = time.Parse(time.RFC3339, s.Epoch); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return now.In(loc), nil
+}
+
+// Reporting thins out the reports written to the sink, which otherwise
+// gets one per aircraft every simulation.reportInterval. Interval is the
+// simulated time between an aircraft's reports, overridden for the
+// airlines and tail numbers listed in Airlines and Aircraft. Adaptive
+// writes every report during take-off, approach and landing whatever the
+// interval. Changes of status are always written. Heartbeat is how often
+// aircraft parked between flights send a heartbeat; zero turns them off.
+type Reporting struct {
+	Interval  time.Duration            `yaml:"interval" env:"INTERVAL"`
+	Adaptive  bool                     `yaml:"adaptive" env:"ADAPTIVE"`
+	Heartbeat time.Duration            `yaml:"heartbeat" env:"HEARTBEAT"`
+	Airlines  map[string]time.Duration `yaml:"airlines"`
+	Aircraft  map[string]time.Duration `yaml:"aircraft"`
+}
+
+// Noise is the standard deviation of the Gaussian error added to reported
+// positions, in metres across the ground and feet of altitude. Zero, the
+// default, reports the true position.
+type Noise struct {
+	Position float64 `yaml:"position" env:"POSITION"`
+	Altitude float64 `yaml:"altitude" env:"ALTITUDE"`
+}
+
+// Sink selects where reports go and how they are encoded. Units is the unit
+// system reports are written in: imperial (knots, feet and nautical miles),
+// metric or statute. VerticalSpeedUnit is fpm, fps or mps, whatever the
+// system.
+type Sink struct {
+	Type      string `yaml:"type" env:"TYPE"`
+	Format    string `yaml:"format" env:"FORMAT"`
+	Partition string `yaml:"partition" env:"PARTITION"`
+	Units     string `yaml:"units" env:"UNITS"`
+
+	VerticalSpeedUnit string `yaml:"verticalSpeedUnit" env:"VERTICAL_SPEED_UNIT"`
+
+	// MaxRecordBytes is the budget each encoded report is trimmed to fit.
+	MaxRecordBytes int `yaml:"maxRecordBytes" env:"MAX_RECORD_BYTES"`
+
+	BatchSize     int           `yaml:"batchSize" env:"BATCH_SIZE"`
+	FlushInterval time.Duration `yaml:"flushInterval" env:"FLUSH_INTERVAL"`
+	Gzip          bool          `yaml:"gzip" env:"GZIP"`
+	Retry         Retry         `yaml:"retry" env:"RETRY"`
+	Queue         Queue         `yaml:"queue" env:"QUEUE"`
+	Chaos         Chaos         `yaml:"chaos" env:"CHAOS"`
+
+	Kinesis Kinesis `yaml:"kinesis" env:"KINESIS"`
+	SQS     SQS     `yaml:"sqs" env:"SQS"`
+	Kafka   Kafka   `yaml:"kafka" env:"KAFKA"`
+	MQTT    MQTT    `yaml:"mqtt" env:"MQTT"`
+	File    File    `yaml:"file" env:"FILE"`
+	Webhook Webhook `yaml:"webhook" env:"WEBHOOK"`
+	TCP     TCP     `yaml:"tcp" env:"TCP"`
+}
+
+// Retry controls retries of failed sink writes. A MaxAttempts of 1
+// disables them.
+type Retry struct {
+	MaxAttempts    int           `yaml:"maxAttempts" env:"MAX_ATTEMPTS"`
+	InitialBackoff time.Duration `yaml:"initialBackoff" env:"INITIAL_BACKOFF"`
+	MaxBackoff     time.Duration `yaml:"maxBackoff" env:"MAX_BACKOFF"`
+	Jitter         float64       `yaml:"jitter" env:"JITTER"`
+}
+
+// Queue bounds the reports waiting for a slow sink. Overflow is "block",
+// "drop-oldest" or "drop-newest". A Size of zero writes synchronously.
+// Reports are handed over in batches of up to Batch, or whatever has built
+// up by the end of each tick.
+type Queue struct {
+	Size     int    `yaml:"size" env:"SIZE"`
+	Batch    int    `yaml:"batch" env:"BATCH"`
+	Overflow string `yaml:"overflow" env:"OVERFLOW"`
+}
+
+// Chaos sets the fraction of reports, between 0 and 1, written twice,
+// written after the aircraft's next report and dropped, to test how
+// consumers cope with a dirty feed. All zero, the default, leaves the feed
+// alone.
+type Chaos struct {
+	Duplicate float64 `yaml:"duplicate" env:"DUPLICATE"`
+	Reorder   float64 `yaml:"reorder" env:"REORDER"`
+	Drop      float64 `yaml:"drop" env:"DROP"`
+}
+
+// AWS holds the settings shared by the AWS sinks. Endpoint overrides the
+// service endpoint, e.g. for LocalStack. RoleARN assumes a role, possibly
+// in another account, with the credentials from the standard chain.
+type AWS struct {
+	Region             string `yaml:"region" env:"REGION"`
+	Endpoint           string `yaml:"endpoint" env:"ENDPOINT"`
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify" env:"INSECURE_SKIP_VERIFY"`
+
+	Profile     string `yaml:"profile" env:"PROFILE"`
+	RoleARN     string `yaml:"roleArn" env:"ROLE_ARN"`
+	ExternalID  string `yaml:"externalId" env:"EXTERNAL_ID"`
+	SessionName string `yaml:"sessionName" env:"SESSION_NAME"`
+}
+
+// Kinesis configures the Kinesis sink. Consumer marks a stream read by
+// plane-consumer, which only decodes uncompressed JSON reports.
+type Kinesis struct {
+	Stream       string `yaml:"stream" env:"STREAM"`
+	AWS          `yaml:",inline"`
+	PartitionKey string `yaml:"partitionKey" env:"PARTITION_KEY"`
+	Aggregate    bool   `yaml:"aggregate" env:"AGGREGATE"`
+	MaxRetries   int    `yaml:"maxRetries" env:"MAX_RETRIES"`
+	Consumer     bool   `yaml:"consumer" env:"CONSUMER"`
+}
+
+type SQS struct {
+	QueueURL string `yaml:"queueUrl" env:"QUEUE_URL"`
+	AWS      `yaml:",inline"`
+}
+
+type Kafka struct {
+	Brokers []string `yaml:"brokers" env:"BROKERS"`
+	Topic   string   `yaml:"topic" env:"TOPIC"`
+}
+
+type MQTT struct {
+	Broker   string `yaml:"broker" env:"BROKER"`
+	ClientID string `yaml:"clientId" env:"CLIENT_ID"`
+	Username string `yaml:"username" env:"USERNAME"`
+	Password string `yaml:"password" env:"PASSWORD"`
+	Topic    string `yaml:"topic" env:"TOPIC"`
+	QoS      int    `yaml:"qos" env:"QOS"`
+	Retained bool   `yaml:"retained" env:"RETAINED"`
+}
+
+type File struct {
+	Path     string        `yaml:"path" env:"PATH"`
+	MaxBytes int64         `yaml:"maxBytes" env:"MAX_BYTES"`
+	MaxAge   time.Duration `yaml:"maxAge" env:"MAX_AGE"`
+}
+
+type Webhook struct {
+	URL          string            `yaml:"url" env:"URL"`
+	Headers      map[string]string `yaml:"headers"`
+	BearerToken  string            `yaml:"bearerToken" env:"BEARER_TOKEN"`
+	SigV4Service string            `yaml:"sigv4Service" env:"SIGV4_SERVICE"`
+	SigV4Region  string            `yaml:"sigv4Region" env:"SIGV4_REGION"`
+}
+
+type TCP struct {
+	Addr string `yaml:"addr" env:"ADDR"`
+}
+
+// Admin configures the health, readiness and flight API server. An empty
+// Addr disables it. ControlToken is the bearer token the control API
+// requires; without one the control API is off.
+type Admin struct {
+	Addr         string `yaml:"addr" env:"ADDR"`
+	ControlToken string `yaml:"controlToken" env:"CONTROL_TOKEN"`
+}
+
+// GRPC configures the gRPC flight tracker service. An empty Addr disables
+// it.
+type GRPC struct {
+	Addr string `yaml:"addr" env:"ADDR"`
+}
+
+// Shard splits the schedule between several producer instances, which
+// lease slices of it from a shared DynamoDB table. An empty Table runs the
+// whole schedule in this instance. Tail numbers are hashed into Count
+// shards, and each lease lasts LeaseTTL unless renewed. Instance names this
+// producer in the table and defaults to the host name and process ID.
+type Shard struct {
+	Table    string        `yaml:"table" env:"TABLE"`
+	Count    int           `yaml:"count" env:"COUNT"`
+	LeaseTTL time.Duration `yaml:"leaseTtl" env:"LEASE_TTL"`
+	Instance string        `yaml:"instance" env:"INSTANCE"`
+	AWS      `yaml:",inline"`
+}
+
+// SinkTypes are the accepted values of sink.type.
+var SinkTypes = []string{"file", "kinesis", "sqs", "kafka", "mqtt", "webhook", "tcp"}
+
+// Default returns the configuration used when nothing is set: real-time
+// simulation written as newline-delimited JSON to a local file.
+func Default() Config {
+	return Config{
+		ShutdownGrace: 10 * time.Second,
+		Simulation: Simulation{
+			Speed:          1,
+			ReportInterval: time.Second,
+			Turnaround:     45 * time.Minute,
+			Navigation:     string(geo.GreatCircle),
+			EarthModel:     string(geo.Sphere),
+			Airlines:       []string{"UTP"},
+			Timezone:       "UTC",
+		},
+		Reporting: Reporting{Heartbeat: time.Minute},
+		Sink: Sink{
+			Type:      "file",
+			Format:    "json",
+			Partition: string(sink.ByTailNum),
+			Units:     string(units.Imperial),
+			Queue:     Queue{Size: 10000, Batch: 100, Overflow: string(sink.Block)},
+
+			VerticalSpeedUnit: string(units.FPM),
+			MaxRecordBytes:    encoder.MaxRecordBytes,
+			Retry: Retry{
+				MaxAttempts:    sink.DefaultRetryPolicy.MaxAttempts,
+				InitialBackoff: sink.DefaultRetryPolicy.InitialBackoff,
+				MaxBackoff:     sink.DefaultRetryPolicy.MaxBackoff,
+				Jitter:         sink.DefaultRetryPolicy.Jitter,
+			},
+			File: File{Path: "out/flights.ndjson"},
+		},
+		Shard: Shard{Count: 16, LeaseTTL: 30 * time.Second},
+	}
+}
+
+// Load reads the YAML file at path over the defaults, applies environment
+// overrides and validates the result. An empty path uses the defaults and
+// environment only.
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("config: %w", err)
+		}
+		defer f.Close()
+
+		dec := yaml.NewDecoder(f)
+		dec.KnownFields(true)
+		if err := dec.Decode(&cfg); err != nil {
+			return Config{}, fmt.Errorf("config: %s: %w", path, err)
+		}
+	}
+
+	if err := applyEnv(reflect.ValueOf(&cfg).Elem(), strings.TrimSuffix(EnvPrefix, "_"), os.LookupEnv); err != nil {
+		return Config{}, fmt.Errorf("config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// applyEnv sets each tagged field of v from the environment variable named
+// by joining prefix and its env tag.
+func applyEnv(v reflect.Value, prefix string, lookup func(string) (string, bool)) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("env")
+		if tag == "" {
+			// Embedded settings share their parent's prefix.
+			if t.Field(i).Anonymous && t.Field(i).Type.Kind() == reflect.Struct {
+				if err := applyEnv(v.Field(i), prefix, lookup); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		name := prefix + "_" + tag
+		field := v.Field(i)
+
+		if field.Kind() == reflect.Struct {
+			if err := applyEnv(field, name, lookup); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		if err := setField(field, value); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func setField(field reflect.Value, value string) error {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", field.Type())
+	}
+	return nil
+}
+
+// Hash fingerprints the settings that shape a run, so a capture can be
+// matched with the configuration it came from. The capture path itself is
+// left out.
+func (c Config) Hash() (string, error) {
+	c.Capture = ""
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("config: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Validate checks the configuration, reporting every problem found.
+func (c Config) Validate() error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.ShutdownGrace <= 0 {
+		add("shutdownGrace must be positive, got %v", c.ShutdownGrace)
+	}
+	if c.Simulation.Speed <= 0 {
+		add("simulation.speed must be positive, got %v", c.Simulation.Speed)
+	}
+	if c.Simulation.ReportInterval <= 0 {
+		add("simulation.reportInterval must be positive, got %v", c.Simulation.ReportInterval)
+	}
+	if c.Simulation.Turnaround < 0 {
+		add("simulation.turnaround must not be negative, got %v", c.Simulation.Turnaround)
+	}
+	if c.Simulation.Workers < 0 {
+		add("simulation.workers must not be negative, got %d", c.Simulation.Workers)
+	}
+	if _, err := c.Simulation.Location(); err != nil {
+		add("simulation.timezone: %v", err)
+	}
+	if c.Simulation.Epoch != "" {
+		if _, err := time.Parse(time.RFC3339, c.Simulation.Epoch); err != nil {
+			add("simulation.epoch must be an RFC 3339 time: %v", err)
+		}
+	}
+	if c.Reporting.Interval < 0 {
+		add("reporting.interval must not be negative, got %v", c.Reporting.Interval)
+	}
+	if c.Reporting.Heartbeat < 0 {
+		add("reporting.heartbeat must not be negative, got %v", c.Reporting.Heartbeat)
+	}
+	for _, intervals := range []struct {
+		name string
+		m    map[string]time.Duration
+	}{{"airlines", c.Reporting.Airlines}, {"aircraft", c.Reporting.Aircraft}} {
+		for key, d := range intervals.m {
+			if d < 0 {
+				add("reporting.%s.%s must not be negative, got %v", intervals.name, key, d)
+			}
+		}
+	}
+	if n := c.Simulation.Noise; n.Position < 0 || n.Altitude < 0 {
+		add("simulation.noise must not be negative, got position %v and altitude %v", n.Position, n.Altitude)
+	}
+	if r := c.Simulation.GoAroundRate; r < 0 || r > 1 {
+		add("simulation.goAroundRate must be between 0 and 1, got %v", r)
+	}
+	if _, err := geo.ParseNavigation(c.Simulation.Navigation); err != nil {
+		add("simulation.navigation: %v", err)
+	}
+	if _, err := geo.ParseEarthModel(c.Simulation.EarthModel); err != nil {
+		add("simulation.earthModel: %v", err)
+	}
+	if err := schedule.ValidateAirlines(c.Simulation.Airlines); err != nil {
+		add("simulation.airlines: %v", err)
+	}
+
+	if c.Shard.Table != "" {
+		if c.Shard.Count < 1 {
+			add("shard.count must be at least 1, got %d", c.Shard.Count)
+		}
+		if c.Shard.LeaseTTL < 3*time.Second {
+			add("shard.leaseTtl must be at least 3s, got %v", c.Shard.LeaseTTL)
+		}
+		if c.Schedule == "" {
+			add("schedule is required when sharding, so every instance splits the same flights")
+		}
+	}
+
+	s := c.Sink
+	for _, sec := range []struct {
+		name string
+		aws  AWS
+	}{{"sink.kinesis", s.Kinesis.AWS}, {"sink.sqs", s.SQS.AWS}, {"shard", c.Shard.AWS}} {
+		name, aws := sec.name, sec.aws
+		if aws.RoleARN == "" && (aws.ExternalID != "" || aws.SessionName != "") {
+			add("%s.roleArn is required with externalId or sessionName", name)
+		}
+		if aws.RoleARN != "" && !strings.HasPrefix(aws.RoleARN, "arn:") {
+			add("%s.roleArn %q is not an ARN", name, aws.RoleARN)
+		}
+	}
+	if _, err := encoder.New(s.Format); err != nil {
+		add("sink.format: %v", err)
+	}
+	if system, err := units.ParseSystem(s.Units); err != nil {
+		add("sink.units: %v", err)
+	} else if system != units.Imperial && s.Format == "sbs" {
+		add("sink.units must be imperial for the sbs format, got %q", s.Units)
+	}
+	if unit, err := units.ParseVerticalSpeedUnit(s.VerticalSpeedUnit); err != nil {
+		add("sink.verticalSpeedUnit: %v", err)
+	} else if unit != units.FPM && s.Format == "sbs" {
+		add("sink.verticalSpeedUnit must be fpm for the sbs format, got %q", s.VerticalSpeedUnit)
+	}
+	if s.MaxRecordBytes <= 0 {
+		add("sink.maxRecordBytes must be positive, got %d", s.MaxRecordBytes)
+	}
+	if _, err := sink.ParsePartitionStrategy(s.Partition); err != nil {
+		add("sink.partition: %v", err)
+	}
+	if s.Retry.MaxAttempts < 1 {
+		add("sink.retry.maxAttempts must be at least 1, got %d", s.Retry.MaxAttempts)
+	}
+	if s.Retry.Jitter < 0 || s.Retry.Jitter > 1 {
+		add("sink.retry.jitter must be between 0 and 1, got %v", s.Retry.Jitter)
+	}
+	if s.Queue.Size < 0 {
+		add("sink.queue.size must not be negative, got %d", s.Queue.Size)
+	}
+	if s.Queue.Batch < 0 {
+		add("sink.queue.batch must not be negative, got %d", s.Queue.Batch)
+	}
+	for _, rate := range []struct {
+		name string
+		v    float64
+	}{{"duplicate", s.Chaos.Duplicate}, {"reorder", s.Chaos.Reorder}, {"drop", s.Chaos.Drop}} {
+		if rate.v < 0 || rate.v > 1 {
+			add("sink.chaos.%s must be between 0 and 1, got %v", rate.name, rate.v)
+		}
+	}
+	if _, err := sink.ParseOverflowPolicy(s.Queue.Overflow); err != nil {
+		add("sink.queue.overflow: %v", err)
+	}
+	if s.BatchSize < 0 {
+		add("sink.batchSize must not be negative, got %d", s.BatchSize)
+	}
+
+	switch s.Type {
+	case "file":
+		if s.File.Path == "" {
+			add("sink.file.path is required for the file sink")
+		}
+	case "kinesis":
+		if s.Kinesis.Stream == "" {
+			add("sink.kinesis.stream is required for the kinesis sink")
+		}
+		if s.Kinesis.Consumer && s.Format != "" && s.Format != "json" {
+			add("sink.format must be json for a stream read by plane-consumer, got %q", s.Format)
+		}
+		if s.Kinesis.Consumer && s.Gzip {
+			add("sink.gzip must be off for a stream read by plane-consumer")
+		}
+	case "sqs":
+		if s.SQS.QueueURL == "" {
+			add("sink.sqs.queueUrl is required for the sqs sink")
+		}
+	case "kafka":
+		if len(s.Kafka.Brokers) == 0 || s.Kafka.Topic == "" {
+			add("sink.kafka.brokers and sink.kafka.topic are required for the kafka sink")
+		}
+	case "mqtt":
+		if s.MQTT.Broker == "" {
+			add("sink.mqtt.broker is required for the mqtt sink")
+		}
+		if s.MQTT.QoS < 0 || s.MQTT.QoS > 2 {
+			add("sink.mqtt.qos must be 0, 1 or 2, got %d", s.MQTT.QoS)
+		}
+	case "webhook":
+		if s.Webhook.URL == "" {
+			add("sink.webhook.url is required for the webhook sink")
+		}
+	case "tcp":
+		if s.TCP.Addr == "" {
+			add("sink.tcp.addr is required for the tcp sink")
+		}
+	default:
+		add("sink.type must be one of %s, got %q", strings.Join(SinkTypes, ", "), s.Type)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("config: invalid settings:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
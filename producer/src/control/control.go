@@ -0,0 +1,317 @@
+package control
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"plane-producer/src/airports"
+	"plane-producer/src/domain"
+	"plane-producer/src/fleet"
+	"plane-producer/src/flight"
+	"plane-producer/src/schedule"
+)
+
+// Simulation is the part of a running simulation the control API changes.
+type Simulation interface {
+	// Add schedules a new flight, departing straight away if it has no
+	// departure time.
+	Add(f schedule.Flight) error
+	// Remove takes a flight out of the simulation at once, wherever it
+	// is, reporting false if there is no such flight.
+	Remove(flightID string) bool
+	// CancelScheduled cancels a flight waiting to depart, reporting false if
+	// there is no such flight.
+	CancelScheduled(flightID string) bool
+	// HoldScheduled withholds or grants the departure clearance of a
+	// flight waiting to depart, reporting false if there is no such flight.
+	HoldScheduled(flightID string, held bool) bool
+	// SetPaused pauses or resumes the whole simulation.
+	SetPaused(paused bool)
+	// SetFlightPaused pauses or resumes a single running flight, reporting
+	// false if there is no such flight.
+	SetFlightPaused(flightID string, paused bool) bool
+}
+
+// Handler serves the control API of a running simulation. Every request
+// must carry the configured token as a bearer token.
+//
+//	POST   /control/flights                 add a flight
+//	DELETE /control/flights/{id}            remove a flight straight away
+//	POST   /control/flights/{id}/cancel     cancel a flight that has not taken off
+//	POST   /control/flights/{id}/clearance  grant or withhold take-off or landing clearance
+//	POST   /control/flights/{id}/divert     divert a flight to another airport
+//	POST   /control/flights/{id}/speed      assign a cruise speed
+//	POST   /control/flights/{id}/go-around  abandon the approach and come round again
+//	POST   /control/flights/{id}/emergency  declare or cancel an emergency aboard a flight
+//	POST   /control/flights/{id}/pause      freeze one flight where it is
+//	POST   /control/flights/{id}/resume     let a paused flight carry on
+//	POST   /control/pause                   freeze the whole simulation
+//	POST   /control/resume                  let the simulation carry on
+type Handler struct {
+	fleet    *fleet.Registry
+	sim      Simulation
+	airports *airports.Database
+	token    []byte
+	mux      *http.ServeMux
+}
+
+// NewHandler creates the control API for the flights in registry and those
+// still waiting in sim, accepting requests bearing token.
+func NewHandler(registry *fleet.Registry, sim Simulation, db *airports.Database, token string) *Handler {
+	h := &Handler{fleet: registry, sim: sim, airports: db, token: []byte(token), mux: http.NewServeMux()}
+	h.mux.HandleFunc("POST /control/flights", h.add)
+	h.mux.HandleFunc("DELETE /control/flights/{id}", h.remove)
+	h.mux.HandleFunc("POST /control/flights/{id}/cancel", h.cancel)
+	h.mux.HandleFunc("POST /control/flights/{id}/clearance", h.clearance)
+	h.mux.HandleFunc("POST /control/flights/{id}/divert", h.divert)
+	h.mux.HandleFunc("POST /control/flights/{id}/speed", h.speed)
+	h.mux.HandleFunc("POST /control/flights/{id}/go-around", h.goAround)
+	h.mux.HandleFunc("POST /control/flights/{id}/emergency", h.emergency)
+	h.mux.HandleFunc("POST /control/flights/{id}/pause", h.pauseFlight(true))
+	h.mux.HandleFunc("POST /control/flights/{id}/resume", h.pauseFlight(false))
+	h.mux.HandleFunc("POST /control/pause", h.pause(true))
+	h.mux.HandleFunc("POST /control/resume", h.pause(false))
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="control"`)
+		writeError(w, http.StatusUnauthorized, errors.New("a valid bearer token is required"))
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), h.token) == 1
+}
+
+// add schedules a flight given as in a JSON schedule.
+func (h *Handler) add(w http.ResponseWriter, r *http.Request) {
+	var f schedule.Flight
+	if !readJSON(w, r, &f) {
+		return
+	}
+	if err := h.sim.Add(f); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, fleet.ErrDuplicateFlightID) {
+			status = http.StatusConflict
+		}
+		writeError(w, status, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]string{"flightId": f.FlightID, "tailNum": f.TailNum, "status": "scheduled"})
+}
+
+// remove takes a flight out of the simulation without it flying on to
+// land, as if its transponder had gone quiet.
+func (h *Handler) remove(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !h.sim.Remove(id) {
+		writeError(w, http.StatusNotFound, errors.New("flight not found"))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"flightId": id, "status": "removed"})
+}
+
+// cancel calls off a flight. A running flight stops on its next step; one
+// that has not launched yet never does.
+func (h *Handler) cancel(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if f, ok := h.fleet.ByFlightID(id); ok {
+		if err := f.Cancel(); err != nil {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		writeJSON(w, http.StatusAccepted, map[string]string{"flightId": id, "status": "cancelling"})
+		return
+	}
+	if h.sim.CancelScheduled(id) {
+		writeJSON(w, http.StatusAccepted, map[string]string{"flightId": id, "status": "cancelled"})
+		return
+	}
+	writeError(w, http.StatusNotFound, errors.New("flight not found"))
+}
+
+// clearance grants or withholds a flight's take-off clearance, before it
+// launches or until it takes off, or its landing clearance, until it
+// lands. Take-off is assumed unless "for" says "landing".
+func (h *Handler) clearance(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Granted bool   `json:"granted"`
+		For     string `json:"for"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	id := r.PathValue("id")
+	status := "held"
+	if body.Granted {
+		status = "cleared"
+	}
+	var set func(*flight.Flight, bool) error
+	switch body.For {
+	case "", "takeoff":
+		set = (*flight.Flight).SetTakeOffClearance
+	case "landing":
+		set = (*flight.Flight).SetLandingClearance
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf(`clearance is for "takeoff" or "landing", not %q`, body.For))
+		return
+	}
+	if f, ok := h.fleet.ByFlightID(id); ok {
+		if err := set(f, body.Granted); err != nil {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+		writeJSON(w, http.StatusAccepted, map[string]string{"flightId": id, "status": status})
+		return
+	}
+	// Flights still waiting to depart only have a take-off clearance.
+	if body.For != "landing" && h.sim.HoldScheduled(id, !body.Granted) {
+		writeJSON(w, http.StatusAccepted, map[string]string{"flightId": id, "status": status})
+		return
+	}
+	writeError(w, http.StatusNotFound, errors.New("flight not found"))
+}
+
+// divert sends a flight to another airport from wherever it is.
+func (h *Handler) divert(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Airport string `json:"airport"`
+	}
+	f, ok := h.flight(w, r, &body)
+	if !ok {
+		return
+	}
+	to, ok := h.airports.Lookup(body.Airport)
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unknown airport %q", body.Airport))
+		return
+	}
+	if err := f.Divert(to); err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]string{"flightId": r.PathValue("id"), "status": "diverting", "destination": to.IATA})
+}
+
+// speed assigns the speed a flight cruises at; zero gives it back its
+// usual cruise speed.
+func (h *Handler) speed(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Knots float64 `json:"knots"`
+	}
+	f, ok := h.flight(w, r, &body)
+	if !ok {
+		return
+	}
+	if err := f.AssignSpeed(body.Knots); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]any{"flightId": r.PathValue("id"), "knots": body.Knots})
+}
+
+// goAround has a flight on final approach go around.
+func (h *Handler) goAround(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	f, ok := h.fleet.ByFlightID(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("flight not found"))
+		return
+	}
+	if err := f.GoAround(); err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]string{"flightId": id, "status": "going around"})
+}
+
+// emergency declares an emergency aboard a flight in the air, diverting it
+// to the nearest airport if the emergency calls for it. Declaring "None"
+// cancels one.
+func (h *Handler) emergency(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Emergency domain.Emergency `json:"emergency"`
+	}
+	f, ok := h.flight(w, r, &body)
+	if !ok {
+		return
+	}
+	to, err := f.DeclareEmergency(body.Emergency, h.airports)
+	if err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]string{"flightId": r.PathValue("id"), "emergency": body.Emergency.String(), "destination": to.IATA})
+}
+
+// pause returns the handler that pauses or resumes the simulation. Paused,
+// simulated time stands still: nothing moves, departs or reports.
+func (h *Handler) pause(paused bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.sim.SetPaused(paused)
+		writeJSON(w, http.StatusOK, map[string]string{"status": runState(paused)})
+	}
+}
+
+// pauseFlight returns the handler that pauses or resumes one flight. A
+// paused flight stays where it is and stops reporting; the rest of the
+// simulation carries on around it.
+func (h *Handler) pauseFlight(paused bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if !h.sim.SetFlightPaused(id, paused) {
+			writeError(w, http.StatusNotFound, errors.New("flight not found"))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"flightId": id, "status": runState(paused)})
+	}
+}
+
+func runState(paused bool) string {
+	if paused {
+		return "paused"
+	}
+	return "running"
+}
+
+// flight reads a command's body into v and finds the running flight it is
+// for, writing the error response if either fails.
+func (h *Handler) flight(w http.ResponseWriter, r *http.Request, v any) (*flight.Flight, bool) {
+	if !readJSON(w, r, v) {
+		return nil, false
+	}
+	f, ok := h.fleet.ByFlightID(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("flight not found"))
+	}
+	return f, ok
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
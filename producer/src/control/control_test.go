@@ -0,0 +1,169 @@
+package control
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"plane-producer/src/airports"
+	"plane-producer/src/domain"
+	"plane-producer/src/fleet"
+	"plane-producer/src/flight"
+	"plane-producer/src/ground"
+	"plane-producer/src/performance"
+	"plane-producer/src/schedule"
+)
+
+const token = "secret"
+
+// noSimulation has no flights waiting to depart.
+type noSimulation struct{}
+
+func (noSimulation) Add(schedule.Flight) error                    { return nil }
+func (noSimulation) Remove(string) bool                           { return false }
+func (noSimulation) CancelScheduled(string) bool                  { return false }
+func (noSimulation) HoldScheduled(string, bool) bool              { return false }
+func (noSimulation) SetPaused(bool)                               {}
+func (noSimulation) SetFlightPaused(flightID string, _ bool) bool { return false }
+
+// newTestHandler serves the control API for a JFK to BOS flight UT1 at the
+// gate and a flight UT2 on the same route that has taken off.
+func newTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	db := airports.Default()
+	registry := fleet.NewRegistry()
+	for _, id := range []string{"UT1", "UT2"} {
+		plane, err := domain.NewPlaneDetails("N"+id, id, "JFK", "BOS")
+		if err != nil {
+			t.Fatal(err)
+		}
+		jfk, _ := db.Lookup("JFK")
+		bos, _ := db.Lookup("BOS")
+		f, err := flight.New(plane, jfk, bos, ground.DefaultTaxiModel, rand.New(rand.NewSource(1)), flight.WithPerformanceProfile(performance.Defaults["A320"]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := registry.Add(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f, _ := registry.ByFlightID("UT2")
+	now := time.Now()
+	for f.Plane().Status() != domain.Cruising {
+		now = now.Add(time.Second)
+		if _, err := f.Step(now, time.Second); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewHandler(registry, noSimulation{}, db, token)
+}
+
+// do sends a control request with the given bearer token and returns the
+// response status and decoded body.
+func do(t *testing.T, h http.Handler, bearer, method, path, body string) (int, map[string]string) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var got map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("%s %s: %v in %q", method, path, err, rec.Body)
+	}
+	return rec.Code, got
+}
+
+func TestControlAuth(t *testing.T) {
+	h := newTestHandler(t)
+	for _, bearer := range []string{"", "wrong", token + "x"} {
+		if code, _ := do(t, h, bearer, "POST", "/control/pause", ""); code != http.StatusUnauthorized {
+			t.Errorf("token %q: got %d, want %d", bearer, code, http.StatusUnauthorized)
+		}
+	}
+	req := httptest.NewRequest("POST", "/control/pause", nil)
+	req.Header.Set("Authorization", "Basic "+token)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+		t.Errorf("basic auth: got %d with challenge %q", rec.Code, rec.Header().Get("WWW-Authenticate"))
+	}
+	if code, _ := do(t, h, token, "POST", "/control/pause", ""); code != http.StatusOK {
+		t.Errorf("valid token: got %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestControlCommands(t *testing.T) {
+	h := newTestHandler(t)
+	for _, tc := range []struct {
+		name, method, path, body string
+		want                     int
+	}{
+		{"missing flight", "POST", "/control/flights/UT9/divert", `{"airport":"IAD"}`, http.StatusNotFound},
+		{"missing flight cancelled", "POST", "/control/flights/UT9/cancel", "", http.StatusNotFound},
+		{"missing flight removed", "DELETE", "/control/flights/UT9", "", http.StatusNotFound},
+		{"unknown airport", "POST", "/control/flights/UT1/divert", `{"airport":"XXX"}`, http.StatusBadRequest},
+		{"unknown field", "POST", "/control/flights/UT1/divert", `{"iata":"IAD"}`, http.StatusBadRequest},
+		{"divert to destination", "POST", "/control/flights/UT1/divert", `{"airport":"BOS"}`, http.StatusConflict},
+		{"cancel after take-off", "POST", "/control/flights/UT2/cancel", "", http.StatusConflict},
+		{"take-off clearance after take-off", "POST", "/control/flights/UT2/clearance", `{"granted":false}`, http.StatusConflict},
+		{"go around at the gate", "POST", "/control/flights/UT1/go-around", "", http.StatusConflict},
+		{"speed out of range", "POST", "/control/flights/UT2/speed", `{"knots":900}`, http.StatusBadRequest},
+	} {
+		if code, body := do(t, h, token, tc.method, tc.path, tc.body); code != tc.want {
+			t.Errorf("%s: got %d %v, want %d", tc.name, code, body, tc.want)
+		}
+	}
+}
+
+func TestControlDivert(t *testing.T) {
+	h := newTestHandler(t)
+	code, body := do(t, h, token, "POST", "/control/flights/UT2/divert", `{"airport":"IAD"}`)
+	if code != http.StatusAccepted || body["destination"] != "IAD" {
+		t.Fatalf("got %d %v, want %d diverting to IAD", code, body, http.StatusAccepted)
+	}
+	f, _ := h.fleet.ByFlightID("UT2")
+	if dest := f.Plane().Destination(); dest != "IAD" {
+		t.Errorf("bound for %s, want IAD", dest)
+	}
+	if code, _ := do(t, h, token, "POST", "/control/flights/UT2/divert", `{"airport":"IAD"}`); code != http.StatusConflict {
+		t.Errorf("diverting again to IAD: got %d, want %d", code, http.StatusConflict)
+	}
+}
+
+func TestControlEmergency(t *testing.T) {
+	h := newTestHandler(t)
+	for _, tc := range []struct {
+		name, path, body string
+		want             int
+	}{
+		{"on the ground", "/control/flights/UT1/emergency", `{"emergency":"EngineFailure"}`, http.StatusConflict},
+		{"unknown emergency", "/control/flights/UT2/emergency", `{"emergency":"Fire"}`, http.StatusBadRequest},
+		{"missing flight", "/control/flights/UT9/emergency", `{"emergency":"Medical"}`, http.StatusNotFound},
+	} {
+		if code, body := do(t, h, token, "POST", tc.path, tc.body); code != tc.want {
+			t.Errorf("%s: got %d %v, want %d", tc.name, code, body, tc.want)
+		}
+	}
+
+	f, _ := h.fleet.ByFlightID("UT2")
+	code, body := do(t, h, token, "POST", "/control/flights/UT2/emergency", `{"emergency":"Depressurization"}`)
+	if code != http.StatusAccepted || body["emergency"] != "Depressurization" || body["destination"] == "" {
+		t.Fatalf("declaring: got %d %v", code, body)
+	}
+	if e := f.Plane().Emergency(); e != domain.Depressurization {
+		t.Errorf("flight has %v declared, want %v", e, domain.Depressurization)
+	}
+	code, body = do(t, h, token, "POST", "/control/flights/UT2/emergency", `{"emergency":"None"}`)
+	if code != http.StatusAccepted || body["emergency"] != "None" {
+		t.Fatalf("cancelling: got %d %v", code, body)
+	}
+	if e := f.Plane().Emergency(); e != domain.NoEmergency {
+		t.Errorf("flight has %v declared after cancelling, want none", e)
+	}
+}
@@ -0,0 +1,6 @@
+package domain
+
+// Divert changes the airport the aircraft is bound for.
+func (p *PlaneDetails) Divert(destination string) {
+	p.destination = destination
+}
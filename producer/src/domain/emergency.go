@@ -0,0 +1,78 @@
+package domain
+
+import "fmt"
+
+// Emergency is the kind of emergency an aircraft has declared.
+type Emergency uint8
+
+const (
+	NoEmergency Emergency = iota
+	EngineFailure
+	Depressurization
+	Medical
+)
+
+var emergencyNames = [...]string{
+	NoEmergency:      "None",
+	EngineFailure:    "EngineFailure",
+	Depressurization: "Depressurization",
+	Medical:          "Medical",
+}
+
+func (e Emergency) String() string {
+	if int(e) < len(emergencyNames) {
+		return emergencyNames[e]
+	}
+	return fmt.Sprintf("Emergency(%d)", uint8(e))
+}
+
+func (e Emergency) MarshalText() ([]byte, error) {
+	if int(e) >= len(emergencyNames) {
+		return nil, fmt.Errorf("invalid emergency %d", uint8(e))
+	}
+	return []byte(emergencyNames[e]), nil
+}
+
+func (e *Emergency) UnmarshalText(text []byte) error {
+	for i, name := range emergencyNames {
+		if name == string(text) {
+			*e = Emergency(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown emergency %q", text)
+}
+
+// EmergencyProfile is how an emergency changes the flight: the altitude to
+// descend to (zero to hold the current one) and how fast in feet per
+// minute (zero for the aircraft's usual rate), the fraction of normal
+// cruise speed to fly at, and whether to divert to the nearest suitable
+// airport.
+type EmergencyProfile struct {
+	MaxAltitude float64
+	DescentRate float64
+	SpeedFactor float64
+	Divert      bool
+}
+
+func (e Emergency) Profile() EmergencyProfile {
+	switch e {
+	case EngineFailure:
+		// Drift down to single-engine service ceiling at reduced speed.
+		return EmergencyProfile{MaxAltitude: 20000, SpeedFactor: 0.8, Divert: true}
+	case Depressurization:
+		// Emergency descent to breathable air.
+		return EmergencyProfile{MaxAltitude: 10000, DescentRate: 6000, SpeedFactor: 1, Divert: true}
+	case Medical:
+		return EmergencyProfile{SpeedFactor: 1, Divert: true}
+	default:
+		return EmergencyProfile{SpeedFactor: 1}
+	}
+}
+
+// DeclareEmergency flags the aircraft as having an emergency, which is
+// carried in every report from then on, and sets the transponder to
+// SquawkEmergency. Declaring NoEmergency cancels it.
+func (p *PlaneDetails) DeclareEmergency(e Emergency) {
+	p.emergency = e
+}
@@ -0,0 +1,260 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"plane-producer/src/units"
+)
+
+// FlightRecord is the wire form of a single position report, as written to
+// the stream. Field names are abbreviated to keep records well under the 1KB
+// limit; see sample-record.json. Version is the schema version the record
+// was written in, SchemaVersion for records made by Record. Sequence counts
+// the reports written for the aircraft, from 1, so consumers can spot
+// missing and repeated ones; it is zero until the record is written.
+type FlightRecord struct {
+	TailNum   string `json:"plane"`
+	FlightID  string `json:"flight"`
+	Timestamp int64  `json:"time"` // unix milliseconds
+
+	Origin      string `json:"orig"` // IATA code
+	Destination string `json:"dest"` // IATA code
+
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"long"`
+	Altitude  float64 `json:"alt"`
+
+	Airspeed          float64 `json:"knots"` // true airspeed
+	IndicatedAirspeed float64 `json:"ias"`
+	GroundSpeed       float64 `json:"gs"`
+	VerticalSpeed     float64 `json:"vs"` // feet per minute
+
+	Compass float64 `json:"compass"`
+	Heading float64 `json:"heading"`
+
+	Attitude   float64 `json:"attitude"`
+	Bank       float64 `json:"bank"`
+	RateOfTurn float64 `json:"turn"`
+
+	DeviationDegrees float64 `json:"devDeg"`
+	DeviationMiles   float64 `json:"devNm"`
+
+	Status    Status    `json:"status"`
+	Emergency Emergency `json:"emerg"`
+	Squawk    Squawk    `json:"squawk,omitempty"`
+
+	ETA int64 `json:"eta,omitempty"` // unix milliseconds at the destination gate
+
+	Version  int    `json:"v"`
+	Sequence uint64 `json:"seq"`
+
+	// TakeOffHeld and LandingHeld report a withheld take-off or landing
+	// clearance, keeping the aircraft short of the runway or in the hold.
+	TakeOffHeld bool `json:"toHeld,omitempty"`
+	LandingHeld bool `json:"ldgHeld,omitempty"`
+
+	Gate string `json:"gate,omitempty"` // where the aircraft is parked, if at a gate
+
+	// Units is the system the altitude, speeds and deviation distance are
+	// in, empty for knots, feet and nautical miles, and VerticalSpeedUnit
+	// the unit of the vertical speed, empty for feet per minute. Only
+	// version 2 records carry them.
+	Units             units.System            `json:"units,omitempty"`
+	VerticalSpeedUnit units.VerticalSpeedUnit `json:"vsUnit,omitempty"`
+
+	// Final marks the last report of an aircraft still flying when the
+	// producer shut down; there are no more until it is flown again.
+	Final bool `json:"final,omitempty"`
+}
+
+// SchemaVersion is the version of the record format written by this
+// producer. Version 1 is the abbreviated form above, in knots, feet,
+// nautical miles and feet per minute; records from before versions were introduced carry none
+// and are read as version 1. A change that existing consumers could
+// misread, such as renaming, retyping or re-scaling a field, needs a new
+// version and a decoder for it in decoders. Adding a field does not.
+const SchemaVersion = 1
+
+// UnitsVersion is the version of records reported in other units than
+// SchemaVersion's, which name them in Units and VerticalSpeedUnit. Consumers that only know
+// version 1 reject these rather than misread them.
+const UnitsVersion = 2
+
+// ErrUnsupportedVersion is returned when parsing a record written in a
+// schema version this build does not know, typically by a newer producer.
+var ErrUnsupportedVersion = errors.New("unsupported schema version")
+
+// decoders read a JSON record of each schema version into the current
+// FlightRecord. Older versions are kept so that records already in streams
+// and captures can still be read.
+var decoders = map[int]func(data []byte) (FlightRecord, error){
+	1: decodeV1,
+	2: decodeV2,
+}
+
+func decodeV1(data []byte) (FlightRecord, error) {
+	var r FlightRecord
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func decodeV2(data []byte) (FlightRecord, error) {
+	var r FlightRecord
+	if err := json.Unmarshal(data, &r); err != nil {
+		return r, err
+	}
+	return r.Imperial()
+}
+
+// Imperial returns the record with its altitude, speeds and deviation
+// distance read from the units it names back into feet, knots and nautical
+// miles, and its vertical speed into feet per minute.
+func (r FlightRecord) Imperial() (FlightRecord, error) {
+	system, err := units.ParseSystem(string(r.Units))
+	if err != nil {
+		return r, err
+	}
+	vs, err := units.ParseVerticalSpeedUnit(string(r.VerticalSpeedUnit))
+	if err != nil {
+		return r, err
+	}
+	r.Altitude = system.LengthOf(r.Altitude).Feet()
+	r.Airspeed = system.SpeedOf(r.Airspeed).Knots()
+	r.IndicatedAirspeed = system.SpeedOf(r.IndicatedAirspeed).Knots()
+	r.GroundSpeed = system.SpeedOf(r.GroundSpeed).Knots()
+	r.DeviationMiles = system.DistanceOf(r.DeviationMiles).NauticalMiles()
+	r.VerticalSpeed = vs.Of(r.VerticalSpeed).FeetPerMinute()
+	r.Units, r.VerticalSpeedUnit = "", ""
+	return r, nil
+}
+
+func (p *PlaneDetails) Record() FlightRecord {
+	return FlightRecord{
+		TailNum:   p.tailNum,
+		FlightID:  p.flightId,
+		Timestamp: p.timestamp.UnixNano() / 1e6,
+
+		Origin:      p.origin,
+		Destination: p.destination,
+
+		Latitude:  p.latitude,
+		Longitude: p.longitude,
+		Altitude:  p.altitude,
+
+		Airspeed:          p.airspeed,
+		IndicatedAirspeed: p.indicatedAirspeed,
+		GroundSpeed:       p.groundSpeed,
+		VerticalSpeed:     p.verticalSpeed,
+
+		Compass: p.compass,
+		Heading: p.heading,
+
+		Attitude:   p.attitude,
+		Bank:       p.bank,
+		RateOfTurn: p.rateOfTurn,
+
+		DeviationDegrees: p.deviation.degrees,
+		DeviationMiles:   p.deviation.miles,
+
+		Status:    p.status,
+		Emergency: p.emergency,
+		Squawk:    p.Squawk(),
+
+		ETA: unixMilli(p.eta),
+
+		Version: SchemaVersion,
+	}
+}
+
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+// Time returns the record's timestamp.
+func (r FlightRecord) Time() time.Time {
+	return time.UnixMilli(r.Timestamp).UTC()
+}
+
+// ParseFlightRecord decodes a JSON position report of any known schema
+// version into the current form, in knots, feet and nautical miles.
+func ParseFlightRecord(data []byte) (FlightRecord, error) {
+	var probe struct {
+		Version int `json:"v"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return FlightRecord{}, fmt.Errorf("parsing flight record: %w", err)
+	}
+	version := max(probe.Version, 1)
+	decode, ok := decoders[version]
+	if !ok {
+		return FlightRecord{}, fmt.Errorf("parsing flight record: %w %d", ErrUnsupportedVersion, version)
+	}
+	r, err := decode(data)
+	if err != nil {
+		return FlightRecord{}, fmt.Errorf("parsing flight record: %w", err)
+	}
+	r.Version = SchemaVersion
+	if r.TailNum == "" || r.FlightID == "" {
+		return FlightRecord{}, errors.New("parsing flight record: plane and flight are required")
+	}
+	return r, nil
+}
+
+// Restore rebuilds aircraft state from a record, e.g. to resume a flight
+// from its last checkpoint.
+func Restore(r FlightRecord) (*PlaneDetails, error) {
+	if r.TailNum == "" || r.FlightID == "" {
+		return nil, errors.New("restoring flight: plane and flight are required")
+	}
+	if int(r.Status) >= len(statusNames) {
+		return nil, fmt.Errorf("restoring flight %s: invalid status %d", r.FlightID, uint8(r.Status))
+	}
+
+	p := &PlaneDetails{
+		tailNum:   r.TailNum,
+		flightId:  r.FlightID,
+		timestamp: r.Time(),
+
+		origin:      r.Origin,
+		destination: r.Destination,
+
+		latitude:  r.Latitude,
+		longitude: r.Longitude,
+		altitude:  r.Altitude,
+
+		airspeed:          r.Airspeed,
+		indicatedAirspeed: r.IndicatedAirspeed,
+		groundSpeed:       r.GroundSpeed,
+		verticalSpeed:     r.VerticalSpeed,
+
+		compass: r.Compass,
+		heading: r.Heading,
+
+		attitude:   r.Attitude,
+		bank:       r.Bank,
+		rateOfTurn: r.RateOfTurn,
+
+		status:    r.Status,
+		emergency: r.Emergency,
+	}
+	p.deviation.degrees = r.DeviationDegrees
+	p.deviation.miles = r.DeviationMiles
+	switch {
+	case r.Squawk == SquawkEmergency && r.Emergency != NoEmergency:
+		// Implied by the emergency; the assigned code is not in the record.
+	case r.Squawk.Special():
+		p.squawk.selected = r.Squawk
+	default:
+		p.squawk.assigned = r.Squawk
+	}
+	if r.ETA != 0 {
+		p.eta = time.UnixMilli(r.ETA).UTC()
+	}
+	return p, nil
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"time"
+
+	"plane-producer/src/units"
+)
+
+// Motion is the kinematic state the flight model computes each step.
+// Altitudes are in feet and speeds in knots. Airspeed is true airspeed;
+// IndicatedAirspeed is what the cockpit instrument shows at that altitude,
+// and GroundSpeed adds the wind. Angles are in degrees: Attitude is pitch,
+// positive nose up, and Bank is positive right wing down, turning at
+// RateOfTurn degrees per second.
+type Motion struct {
+	Time time.Time
+
+	Latitude  float64
+	Longitude float64
+	Altitude  float64
+
+	Airspeed          float64
+	IndicatedAirspeed float64
+	GroundSpeed       float64
+	VerticalSpeed     units.VerticalSpeed
+
+	Heading    float64
+	Attitude   float64
+	Bank       float64
+	RateOfTurn float64
+}
+
+// Move updates the aircraft's position and speeds.
+func (p *PlaneDetails) Move(m Motion) {
+	p.timestamp = m.Time
+	p.latitude, p.longitude, p.altitude = m.Latitude, m.Longitude, m.Altitude
+	p.airspeed, p.groundSpeed, p.verticalSpeed = m.Airspeed, m.GroundSpeed, m.VerticalSpeed.FeetPerMinute()
+	p.indicatedAirspeed = m.IndicatedAirspeed
+	p.heading, p.compass = m.Heading, m.Heading
+	p.attitude, p.bank, p.rateOfTurn = m.Attitude, m.Bank, m.RateOfTurn
+}
+
+// Motion returns the aircraft's current kinematic state.
+func (p *PlaneDetails) Motion() Motion {
+	return Motion{
+		Time:              p.timestamp,
+		Latitude:          p.latitude,
+		Longitude:         p.longitude,
+		Altitude:          p.altitude,
+		Airspeed:          p.airspeed,
+		IndicatedAirspeed: p.indicatedAirspeed,
+		GroundSpeed:       p.groundSpeed,
+		VerticalSpeed:     units.FeetPerMinute(p.verticalSpeed),
+		Heading:           p.heading,
+		Attitude:          p.attitude,
+		Bank:              p.bank,
+		RateOfTurn:        p.rateOfTurn,
+	}
+}
+
+// SetDeviation records how far the aircraft is off its planned route: the
+// angle between its heading and the planned course, and the cross-track
+// distance in nautical miles, both positive to the right.
+func (p *PlaneDetails) SetDeviation(degrees, miles float64) {
+	p.deviation.degrees, p.deviation.miles = degrees, miles
+}
+
+// SetETA records when the aircraft is expected at the destination gate. A
+// zero time means no estimate.
+func (p *PlaneDetails) SetETA(eta time.Time) {
+	p.eta = eta
+}
+
+// ETA returns the value last given to SetETA.
+func (p *PlaneDetails) ETA() time.Time {
+	return p.eta
+}
+
+// Deviation returns the values last given to SetDeviation.
+func (p *PlaneDetails) Deviation() (degrees, miles float64) {
+	return p.deviation.degrees, p.deviation.miles
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Option configures a PlaneDetails built by NewPlaneDetails.
+type Option func(*PlaneDetails) error
+
+// NewPlaneDetails creates the state for a flight from origin to destination,
+// parked and idle unless options say otherwise. Invalid input is reported
+// rather than left as zero values.
+func NewPlaneDetails(tailNum, flightID, origin, destination string, opts ...Option) (*PlaneDetails, error) {
+	switch {
+	case tailNum == "":
+		return nil, errors.New("tail number is required")
+	case flightID == "":
+		return nil, errors.New("flight id is required")
+	case origin == "" || destination == "":
+		return nil, fmt.Errorf("flight %s: origin and destination are required", flightID)
+	case origin == destination:
+		return nil, fmt.Errorf("flight %s: origin and destination are the same", flightID)
+	}
+
+	p := &PlaneDetails{
+		tailNum:     tailNum,
+		flightId:    flightID,
+		origin:      origin,
+		destination: destination,
+		timestamp:   time.Now(),
+		status:      Idle,
+	}
+	for _, opt := range opts {
+		if err := opt(p); err != nil {
+			return nil, fmt.Errorf("flight %s: %w", flightID, err)
+		}
+	}
+	return p, nil
+}
+
+// WithPosition places the aircraft at a latitude and longitude in degrees
+// and an altitude in feet.
+func WithPosition(latitude, longitude, altitude float64) Option {
+	return func(p *PlaneDetails) error {
+		if latitude < -90 || latitude > 90 {
+			return fmt.Errorf("latitude %v out of range", latitude)
+		}
+		if longitude < -180 || longitude > 180 {
+			return fmt.Errorf("longitude %v out of range", longitude)
+		}
+		if altitude < -1500 {
+			return fmt.Errorf("altitude %v below sea level limit", altitude)
+		}
+		p.latitude, p.longitude, p.altitude = latitude, longitude, altitude
+		return nil
+	}
+}
+
+// WithHeading sets the aircraft's heading and compass reading in degrees.
+func WithHeading(heading float64) Option {
+	return func(p *PlaneDetails) error {
+		if heading < 0 || heading >= 360 {
+			return fmt.Errorf("heading %v out of range", heading)
+		}
+		p.heading, p.compass = heading, heading
+		return nil
+	}
+}
+
+// WithTimestamp sets the time of the aircraft's state.
+func WithTimestamp(t time.Time) Option {
+	return func(p *PlaneDetails) error {
+		if t.IsZero() {
+			return errors.New("timestamp is required")
+		}
+		p.timestamp = t
+		return nil
+	}
+}
+
+// WithStatus sets the aircraft's flight phase.
+func WithStatus(s Status) Option {
+	return func(p *PlaneDetails) error {
+		if int(s) >= len(statusNames) {
+			return fmt.Errorf("invalid status %d", uint8(s))
+		}
+		p.status = s
+		return nil
+	}
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+
+	"plane-producer/src/fsm"
+)
+
+// Phases is the flight phase state machine used by Transition. New phases
+// and guards are added by registering them here rather than by editing the
+// callers.
+var Phases = fsm.New[Status, *PlaneDetails]().
+	Allow(Idle, Taxi, nil).
+	Allow(Taxi, TakeOff, noEmergency).
+	Allow(Taxi, Idle, nil).
+	Allow(TakeOff, Cruising, nil).
+	Allow(Cruising, AwaitingLanding, nil).
+	Allow(AwaitingLanding, Landing, nil).
+	Allow(AwaitingLanding, Cruising, nil). // diversion
+	Allow(Landing, AwaitingLanding, nil).  // go-around
+	Allow(Landing, Taxi, nil).
+	OnEnter(Idle, func(p *PlaneDetails, _ Status) {
+		p.airspeed, p.groundSpeed, p.verticalSpeed = 0, 0, 0
+	})
+
+func noEmergency(p *PlaneDetails) error {
+	if p.emergency != NoEmergency {
+		return errors.New("aircraft has declared an emergency")
+	}
+	return nil
+}
+
+// Transition moves the aircraft to the next flight phase if Phases allows
+// it, running the phase's entry action and the transition hooks.
+func (p *PlaneDetails) Transition(to Status) error {
+	if err := Phases.Fire(p, p.status, to); err != nil {
+		return err
+	}
+	p.SetStatus(to)
+	return nil
+}
@@ -1,38 +1,54 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type PlaneDetails struct {
-	tailNum string
-	flightId string
+	tailNum   string
+	flightId  string
 	timestamp time.Time
 
-	latitude float64
+	origin      string
+	destination string
+
+	latitude  float64
 	longitude float64
-	altitude float64
+	altitude  float64
 
-	airspeed float64
-	groundSpeed float64
-	verticalSpeed float64
+	airspeed          float64
+	indicatedAirspeed float64
+	groundSpeed       float64
+	verticalSpeed     float64
 
 	compass float64
 	heading float64
 
-	attitude float64
-	bank float64
+	attitude   float64
+	bank       float64
 	rateOfTurn float64
 
 	deviation struct {
 		degrees float64
-		miles float64
+		miles   float64
+	}
+
+	eta time.Time
+
+	status    Status
+	emergency Emergency
+	squawk    struct {
+		assigned Squawk
+		selected Squawk
 	}
 
-	status Status
+	transitionHooks []TransitionFunc
 }
 
 type Status uint8
 
-const(
+const (
 	Idle Status = iota
 	Taxi
 	TakeOff
@@ -40,3 +56,60 @@ const(
 	AwaitingLanding
 	Landing
 )
+
+var statusNames = [...]string{
+	Idle:            "Idle",
+	Taxi:            "Taxi",
+	TakeOff:         "TakeOff",
+	Cruising:        "Cruising",
+	AwaitingLanding: "AwaitingLanding",
+	Landing:         "Landing",
+}
+
+func (s Status) String() string {
+	if int(s) < len(statusNames) {
+		return statusNames[s]
+	}
+	return fmt.Sprintf("Status(%d)", uint8(s))
+}
+
+func (s Status) MarshalText() ([]byte, error) {
+	if int(s) >= len(statusNames) {
+		return nil, fmt.Errorf("invalid status %d", uint8(s))
+	}
+	return []byte(statusNames[s]), nil
+}
+
+func (s *Status) UnmarshalText(text []byte) error {
+	for i, name := range statusNames {
+		if name == string(text) {
+			*s = Status(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown status %q", text)
+}
+
+// Read-only accessors for monitoring code. Record returns all of them at
+// once as a snapshot.
+
+func (p *PlaneDetails) TailNum() string      { return p.tailNum }
+func (p *PlaneDetails) FlightID() string     { return p.flightId }
+func (p *PlaneDetails) Timestamp() time.Time { return p.timestamp }
+func (p *PlaneDetails) Origin() string       { return p.origin }
+func (p *PlaneDetails) Destination() string  { return p.destination }
+
+func (p *PlaneDetails) Latitude() float64  { return p.latitude }
+func (p *PlaneDetails) Longitude() float64 { return p.longitude }
+func (p *PlaneDetails) Altitude() float64  { return p.altitude }
+
+func (p *PlaneDetails) Airspeed() float64          { return p.airspeed }
+func (p *PlaneDetails) IndicatedAirspeed() float64 { return p.indicatedAirspeed }
+func (p *PlaneDetails) GroundSpeed() float64       { return p.groundSpeed }
+func (p *PlaneDetails) VerticalSpeed() float64     { return p.verticalSpeed }
+
+func (p *PlaneDetails) Compass() float64 { return p.compass }
+func (p *PlaneDetails) Heading() float64 { return p.heading }
+
+func (p *PlaneDetails) Status() Status       { return p.status }
+func (p *PlaneDetails) Emergency() Emergency { return p.emergency }
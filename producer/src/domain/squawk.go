@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+)
+
+// Squawk is a transponder code, four octal digits from 0000 to 7777. The
+// zero value means no code has been assigned and formats as "".
+type Squawk uint16
+
+// Special codes an aircraft squawks in place of its assigned one.
+const (
+	SquawkHijack       Squawk = 07500
+	SquawkRadioFailure Squawk = 07600
+	SquawkEmergency    Squawk = 07700
+)
+
+// reservedSquawks are never handed out as discrete codes: the special codes
+// above and the conspicuity codes for VFR and unassigned traffic.
+var reservedSquawks = map[Squawk]bool{
+	SquawkHijack:       true,
+	SquawkRadioFailure: true,
+	SquawkEmergency:    true,
+	01200:              true,
+	02000:              true,
+	07000:              true,
+	07777:              true,
+}
+
+// NewSquawk draws a discrete code for a flight's clearance.
+func NewSquawk(rng *rand.Rand) Squawk {
+	for {
+		// 0000 is left out along with the reserved codes, since zero means
+		// unassigned.
+		s := Squawk(1 + rng.Intn(07777))
+		if !reservedSquawks[s] {
+			return s
+		}
+	}
+}
+
+// ParseSquawk reads a four-digit code such as "7700".
+func ParseSquawk(s string) (Squawk, error) {
+	if len(s) != 4 {
+		return 0, fmt.Errorf("squawk %q must be four octal digits", s)
+	}
+	v, err := strconv.ParseUint(s, 8, 16)
+	if err != nil {
+		return 0, fmt.Errorf("squawk %q must be four octal digits", s)
+	}
+	return Squawk(v), nil
+}
+
+// Special reports whether the code signals hijack, radio failure or an
+// emergency.
+func (s Squawk) Special() bool {
+	return s == SquawkHijack || s == SquawkRadioFailure || s == SquawkEmergency
+}
+
+func (s Squawk) String() string {
+	if s == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%04o", uint16(s))
+}
+
+func (s Squawk) MarshalText() ([]byte, error) {
+	if s > 07777 {
+		return nil, fmt.Errorf("invalid squawk %o", uint16(s))
+	}
+	return []byte(s.String()), nil
+}
+
+func (s *Squawk) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*s = 0
+		return nil
+	}
+	v, err := ParseSquawk(string(text))
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+// AssignSquawk sets the discrete code the aircraft was given with its
+// clearance.
+func (p *PlaneDetails) AssignSquawk(s Squawk) {
+	p.squawk.assigned = s
+}
+
+// SelectSquawk sets the transponder to a code other than the assigned one,
+// such as SquawkHijack or SquawkRadioFailure. Selecting zero returns to the
+// assigned code.
+func (p *PlaneDetails) SelectSquawk(s Squawk) {
+	p.squawk.selected = s
+}
+
+// Squawk returns the code the transponder is sending: SquawkEmergency while
+// an emergency is declared, otherwise any selected code, otherwise the
+// assigned one.
+func (p *PlaneDetails) Squawk() Squawk {
+	switch {
+	case p.emergency != NoEmergency:
+		return SquawkEmergency
+	case p.squawk.selected != 0:
+		return p.squawk.selected
+	}
+	return p.squawk.assigned
+}
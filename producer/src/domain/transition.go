@@ -0,0 +1,25 @@
+package domain
+
+// TransitionFunc is called when an aircraft changes flight phase, e.g. to
+// update a departure board on TakeOff or send a notification on Landing.
+type TransitionFunc func(p *PlaneDetails, from, to Status)
+
+// OnTransition registers fn to be called on every status change, in
+// registration order. Hooks run synchronously on the goroutine that changes
+// the status, so slow work should be handed off.
+func (p *PlaneDetails) OnTransition(fn TransitionFunc) {
+	p.transitionHooks = append(p.transitionHooks, fn)
+}
+
+// SetStatus moves the aircraft to a new flight phase and runs the
+// transition hooks. Setting the current status again does nothing.
+func (p *PlaneDetails) SetStatus(s Status) {
+	from := p.status
+	if from == s {
+		return
+	}
+	p.status = s
+	for _, fn := range p.transitionHooks {
+		fn(p, from, s)
+	}
+}
@@ -0,0 +1,110 @@
+package encoder
+
+import (
+	"context"
+	_ "embed"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"plane-producer/src/domain"
+)
+
+// AvroSchema is the Avro schema records are written with.
+//
+//go:embed flightRecord.avsc
+var AvroSchema string
+
+// Avro encodes records in Avro binary form using AvroSchema. When created
+// with NewAvro, each payload is prefixed with the header of the schema
+// registry the schema was registered with, so consumers can look it up.
+type Avro struct {
+	header []byte
+}
+
+// NewAvro registers AvroSchema with the registry and returns an encoder that
+// tags every payload with the registered schema.
+func NewAvro(ctx context.Context, registry SchemaRegistry) (Avro, error) {
+	header, err := registry.Register(ctx, AvroSchema)
+	if err != nil {
+		return Avro{}, fmt.Errorf("avro: registering schema: %w", err)
+	}
+	return Avro{header: header}, nil
+}
+
+func (a Avro) Encode(record domain.FlightRecord) ([]byte, error) {
+	b := make([]byte, 0, len(a.header)+96)
+	b = append(b, a.header...)
+
+	b = appendAvroString(b, record.TailNum)
+	b = appendAvroString(b, record.FlightID)
+	b = binary.AppendVarint(b, record.Timestamp)
+
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(record.Latitude))
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(record.Longitude))
+	b = appendAvroFloat(b, record.Altitude)
+
+	b = appendAvroFloat(b, record.Airspeed)
+	b = appendAvroFloat(b, record.GroundSpeed)
+	b = appendAvroFloat(b, record.VerticalSpeed)
+
+	b = appendAvroFloat(b, record.Compass)
+	b = appendAvroFloat(b, record.Heading)
+
+	b = appendAvroFloat(b, record.Attitude)
+	b = appendAvroFloat(b, record.Bank)
+	b = appendAvroFloat(b, record.RateOfTurn)
+
+	b = appendAvroFloat(b, record.DeviationDegrees)
+	b = appendAvroFloat(b, record.DeviationMiles)
+
+	b = binary.AppendVarint(b, int64(record.Status))
+
+	b = appendAvroString(b, record.Origin)
+	b = appendAvroString(b, record.Destination)
+
+	b = binary.AppendVarint(b, int64(record.Emergency))
+
+	b = binary.AppendVarint(b, record.ETA)
+
+	b = appendAvroFloat(b, record.IndicatedAirspeed)
+
+	b = appendAvroString(b, record.Squawk.String())
+
+	b = binary.AppendVarint(b, int64(record.Version))
+	b = binary.AppendVarint(b, int64(record.Sequence))
+
+	b = appendAvroBoolean(b, record.TakeOffHeld)
+	b = appendAvroBoolean(b, record.LandingHeld)
+
+	b = appendAvroString(b, record.Gate)
+	b = appendAvroString(b, string(record.Units))
+	b = appendAvroString(b, string(record.VerticalSpeedUnit))
+
+	b = appendAvroBoolean(b, record.Final)
+
+	return b, nil
+}
+
+func (Avro) ContentType() string {
+	return "avro/binary"
+}
+
+// Avro ints and longs are zig-zag varints, which is exactly what
+// binary.AppendVarint writes.
+
+func appendAvroString(b []byte, v string) []byte {
+	b = binary.AppendVarint(b, int64(len(v)))
+	return append(b, v...)
+}
+
+func appendAvroBoolean(b []byte, v bool) []byte {
+	if v {
+		return append(b, 1)
+	}
+	return append(b, 0)
+}
+
+func appendAvroFloat(b []byte, v float64) []byte {
+	return binary.LittleEndian.AppendUint32(b, math.Float32bits(float32(v)))
+}
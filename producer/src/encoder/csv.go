@@ -0,0 +1,85 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+
+	"plane-producer/src/domain"
+)
+
+// CSVColumns is the fixed column order of CSV output. New fields are only
+// ever appended so existing consumers keep working.
+var CSVColumns = []string{
+	"plane", "flight", "time",
+	"lat", "long", "alt",
+	"knots", "gs", "vs",
+	"compass", "heading",
+	"attitude", "bank", "turn",
+	"devDeg", "devNm",
+	"status",
+	"orig", "dest",
+	"emerg",
+	"eta",
+	"ias",
+	"squawk",
+	"v",
+	"seq",
+	"toHeld", "ldgHeld",
+	"gate",
+	"units", "vsUnit",
+	"final",
+}
+
+// CSV encodes each record as a single CSV row without a trailing newline.
+// Header returns the matching header row.
+type CSV struct{}
+
+func (CSV) Encode(record domain.FlightRecord) ([]byte, error) {
+	f := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	return csvRow([]string{
+		record.TailNum, record.FlightID, strconv.FormatInt(record.Timestamp, 10),
+		f(record.Latitude), f(record.Longitude), f(record.Altitude),
+		f(record.Airspeed), f(record.GroundSpeed), f(record.VerticalSpeed),
+		f(record.Compass), f(record.Heading),
+		f(record.Attitude), f(record.Bank), f(record.RateOfTurn),
+		f(record.DeviationDegrees), f(record.DeviationMiles),
+		record.Status.String(),
+		record.Origin, record.Destination,
+		record.Emergency.String(),
+		strconv.FormatInt(record.ETA, 10),
+		f(record.IndicatedAirspeed),
+		record.Squawk.String(),
+		strconv.Itoa(record.Version),
+		strconv.FormatUint(record.Sequence, 10),
+		strconv.FormatBool(record.TakeOffHeld), strconv.FormatBool(record.LandingHeld),
+		record.Gate,
+		string(record.Units), string(record.VerticalSpeedUnit),
+		strconv.FormatBool(record.Final),
+	})
+}
+
+func (CSV) Header() []byte {
+	header, _ := csvRow(CSVColumns)
+	return header
+}
+
+func (CSV) ContentType() string {
+	return "text/csv"
+}
+
+func csvRow(fields []string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(fields); err != nil {
+		return nil, err
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,35 @@
+package encoder
+
+import (
+	"fmt"
+
+	"plane-producer/src/domain"
+)
+
+// Encoder turns a FlightRecord into the payload written to a sink.
+type Encoder interface {
+	Encode(record domain.FlightRecord) ([]byte, error)
+	ContentType() string
+}
+
+// New returns the encoder for the named format.
+func New(format string) (Encoder, error) {
+	switch format {
+	case "", "json":
+		return JSON{}, nil
+	case "protobuf":
+		return Protobuf{}, nil
+	case "avro":
+		return Avro{}, nil
+	case "geojson":
+		return GeoJSON{}, nil
+	case "csv":
+		return CSV{}, nil
+	case "msgpack":
+		return MessagePack{}, nil
+	case "sbs":
+		return SBS{}, nil
+	default:
+		return nil, fmt.Errorf("unknown encoding %q", format)
+	}
+}
@@ -0,0 +1,84 @@
+package encoder
+
+import (
+	"testing"
+	"time"
+
+	"plane-producer/src/domain"
+)
+
+var formats = []string{"json", "protobuf", "avro", "geojson", "csv", "msgpack", "sbs"}
+
+// cruising is a typical report, from an aircraft in the cruise over the
+// North Atlantic.
+var cruising = domain.FlightRecord{
+	TailNum:           "N512UT",
+	FlightID:          "UTP1024",
+	Timestamp:         time.Date(2024, 1, 1, 14, 30, 0, 0, time.UTC).UnixMilli(),
+	Origin:            "JFK",
+	Destination:       "LHR",
+	Latitude:          51.234567,
+	Longitude:         -32.345678,
+	Altitude:          37000,
+	Airspeed:          472.3,
+	IndicatedAirspeed: 268.1,
+	GroundSpeed:       511.8,
+	VerticalSpeed:     0,
+	Compass:           71.25,
+	Heading:           68.9,
+	Attitude:          2.1,
+	Bank:              0.4,
+	RateOfTurn:        0.02,
+	DeviationDegrees:  0.3,
+	DeviationMiles:    0.05,
+	Status:            domain.Cruising,
+	Squawk:            01200,
+	ETA:               time.Date(2024, 1, 1, 18, 5, 0, 0, time.UTC).UnixMilli(),
+	Version:           domain.SchemaVersion,
+	Sequence:          4242,
+}
+
+func BenchmarkEncode(b *testing.B) {
+	for _, format := range formats {
+		enc, err := New(format)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(format, func(b *testing.B) {
+			b.ReportAllocs()
+			var size int
+			for i := 0; i < b.N; i++ {
+				data, err := enc.Encode(cruising)
+				if err != nil {
+					b.Fatal(err)
+				}
+				size = len(data)
+			}
+			b.ReportMetric(float64(size), "bytes/record")
+		})
+	}
+}
+
+// BenchmarkAppend measures the encoders that can write into a reused
+// buffer, which is how the sinks batch records.
+func BenchmarkAppend(b *testing.B) {
+	for _, format := range formats {
+		enc, err := New(format)
+		if err != nil {
+			b.Fatal(err)
+		}
+		appender, ok := enc.(Appender)
+		if !ok {
+			continue
+		}
+		b.Run(format, func(b *testing.B) {
+			b.ReportAllocs()
+			var buf []byte
+			for i := 0; i < b.N; i++ {
+				if buf, err = appender.Append(buf[:0], cruising); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
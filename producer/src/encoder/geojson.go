@@ -0,0 +1,90 @@
+package encoder
+
+import (
+	"encoding/json"
+
+	"plane-producer/src/domain"
+	"plane-producer/src/units"
+)
+
+// GeoJSON encodes each record as a GeoJSON Feature with a Point geometry,
+// so reports can be dropped straight onto a map layer.
+type GeoJSON struct{}
+
+type geoJSONFeature struct {
+	Type       string            `json:"type"`
+	ID         string            `json:"id,omitempty"`
+	Geometry   geoJSONPoint      `json:"geometry"`
+	Properties geoJSONProperties `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [3]float64 `json:"coordinates"` // long, lat, alt
+}
+
+type geoJSONProperties struct {
+	TailNum       string                  `json:"plane"`
+	FlightID      string                  `json:"flight"`
+	Timestamp     int64                   `json:"time"`
+	Origin        string                  `json:"orig"`
+	Destination   string                  `json:"dest"`
+	Status        domain.Status           `json:"status"`
+	Emergency     domain.Emergency        `json:"emerg"`
+	Squawk        domain.Squawk           `json:"squawk,omitempty"`
+	Altitude      float64                 `json:"alt"`
+	Airspeed      float64                 `json:"knots"`
+	IAS           float64                 `json:"ias"`
+	GroundSpeed   float64                 `json:"gs"`
+	VerticalSpeed float64                 `json:"vs"`
+	Heading       float64                 `json:"heading"`
+	ETA           int64                   `json:"eta,omitempty"`
+	Version       int                     `json:"v"`
+	Sequence      uint64                  `json:"seq"`
+	TakeOffHeld   bool                    `json:"toHeld,omitempty"`
+	LandingHeld   bool                    `json:"ldgHeld,omitempty"`
+	Gate          string                  `json:"gate,omitempty"`
+	Units         units.System            `json:"units,omitempty"`
+	VSUnit        units.VerticalSpeedUnit `json:"vsUnit,omitempty"`
+	Final         bool                    `json:"final,omitempty"`
+}
+
+func (GeoJSON) Encode(record domain.FlightRecord) ([]byte, error) {
+	return json.Marshal(geoJSONFeature{
+		Type: "Feature",
+		ID:   record.FlightID,
+		Geometry: geoJSONPoint{
+			Type:        "Point",
+			Coordinates: [3]float64{record.Longitude, record.Latitude, record.Altitude},
+		},
+		Properties: geoJSONProperties{
+			TailNum:       record.TailNum,
+			FlightID:      record.FlightID,
+			Timestamp:     record.Timestamp,
+			Origin:        record.Origin,
+			Destination:   record.Destination,
+			Status:        record.Status,
+			Emergency:     record.Emergency,
+			Squawk:        record.Squawk,
+			Altitude:      record.Altitude,
+			Airspeed:      record.Airspeed,
+			IAS:           record.IndicatedAirspeed,
+			GroundSpeed:   record.GroundSpeed,
+			VerticalSpeed: record.VerticalSpeed,
+			Heading:       record.Heading,
+			ETA:           record.ETA,
+			Version:       record.Version,
+			Sequence:      record.Sequence,
+			TakeOffHeld:   record.TakeOffHeld,
+			LandingHeld:   record.LandingHeld,
+			Gate:          record.Gate,
+			Units:         record.Units,
+			VSUnit:        record.VerticalSpeedUnit,
+			Final:         record.Final,
+		},
+	})
+}
+
+func (GeoJSON) ContentType() string {
+	return "application/geo+json"
+}
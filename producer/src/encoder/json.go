@@ -0,0 +1,212 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"plane-producer/src/domain"
+)
+
+// JSON encodes records as JSON objects using the abbreviated field names.
+// The output is byte for byte what encoding/json makes of a FlightRecord,
+// but is formatted by appending to pooled buffers rather than by
+// reflection, so each record costs a single allocation for its payload.
+// With OmitOptional set, the route and the attitude and deviation fields
+// are left out when empty, as SizeGuard needs to shrink a record.
+type JSON struct {
+	OmitOptional bool
+}
+
+// Appender is implemented by encoders that can append a record to a
+// caller's buffer, which lets a caller that does not keep the payload reuse
+// one buffer and encode without allocating.
+type Appender interface {
+	Append(dst []byte, record domain.FlightRecord) ([]byte, error)
+}
+
+// jsonBuffers holds scratch buffers sized for a typical record.
+var jsonBuffers = sync.Pool{New: func() any {
+	b := make([]byte, 0, MaxRecordBytes)
+	return &b
+}}
+
+func (j JSON) Encode(record domain.FlightRecord) ([]byte, error) {
+	buf := jsonBuffers.Get().(*[]byte)
+	defer jsonBuffers.Put(buf)
+
+	b, err := j.Append((*buf)[:0], record)
+	if err != nil {
+		return nil, err
+	}
+	*buf = b
+	return bytes.Clone(b), nil
+}
+
+// Append appends the JSON form of record to dst. The fields must be kept in
+// step with FlightRecord's.
+func (j JSON) Append(dst []byte, r domain.FlightRecord) ([]byte, error) {
+	b := append(dst, `{"plane":`...)
+	b = appendJSONString(b, r.TailNum)
+	b = append(b, `,"flight":`...)
+	b = appendJSONString(b, r.FlightID)
+	b = append(b, `,"time":`...)
+	b = strconv.AppendInt(b, r.Timestamp, 10)
+	for _, f := range []struct {
+		key string
+		v   string
+	}{
+		{`,"orig":`, r.Origin},
+		{`,"dest":`, r.Destination},
+	} {
+		if j.OmitOptional && f.v == "" {
+			continue
+		}
+		b = append(b, f.key...)
+		b = appendJSONString(b, f.v)
+	}
+
+	for _, f := range []struct {
+		key      string
+		v        float64
+		optional bool
+	}{
+		{`,"lat":`, r.Latitude, false},
+		{`,"long":`, r.Longitude, false},
+		{`,"alt":`, r.Altitude, false},
+		{`,"knots":`, r.Airspeed, false},
+		{`,"ias":`, r.IndicatedAirspeed, false},
+		{`,"gs":`, r.GroundSpeed, false},
+		{`,"vs":`, r.VerticalSpeed, false},
+		{`,"compass":`, r.Compass, false},
+		{`,"heading":`, r.Heading, false},
+		{`,"attitude":`, r.Attitude, true},
+		{`,"bank":`, r.Bank, true},
+		{`,"turn":`, r.RateOfTurn, true},
+		{`,"devDeg":`, r.DeviationDegrees, true},
+		{`,"devNm":`, r.DeviationMiles, true},
+	} {
+		if j.OmitOptional && f.optional && f.v == 0 {
+			continue
+		}
+		b = append(b, f.key...)
+		var err error
+		if b, err = appendJSONFloat(b, f.v); err != nil {
+			return dst, err
+		}
+	}
+
+	status, emergency := statusText[r.Status], emergencyText[r.Emergency]
+	if status == "" {
+		return dst, fmt.Errorf("invalid status %d", uint8(r.Status))
+	}
+	if emergency == "" {
+		return dst, fmt.Errorf("invalid emergency %d", uint8(r.Emergency))
+	}
+	b = append(b, `,"status":`...)
+	b = appendJSONString(b, status)
+	b = append(b, `,"emerg":`...)
+	b = appendJSONString(b, emergency)
+	if r.Squawk != 0 {
+		if r.Squawk > 07777 {
+			return dst, fmt.Errorf("invalid squawk %o", uint16(r.Squawk))
+		}
+		b = append(b, `,"squawk":"`...)
+		for shift := 9; shift >= 0; shift -= 3 {
+			b = append(b, '0'+byte(r.Squawk>>shift&7))
+		}
+		b = append(b, '"')
+	}
+	if r.ETA != 0 {
+		b = append(b, `,"eta":`...)
+		b = strconv.AppendInt(b, r.ETA, 10)
+	}
+	b = append(b, `,"v":`...)
+	b = strconv.AppendInt(b, int64(r.Version), 10)
+	b = append(b, `,"seq":`...)
+	b = strconv.AppendUint(b, r.Sequence, 10)
+	if r.TakeOffHeld {
+		b = append(b, `,"toHeld":true`...)
+	}
+	if r.LandingHeld {
+		b = append(b, `,"ldgHeld":true`...)
+	}
+	if r.Gate != "" {
+		b = append(b, `,"gate":`...)
+		b = appendJSONString(b, r.Gate)
+	}
+	if r.Units != "" {
+		b = append(b, `,"units":`...)
+		b = appendJSONString(b, string(r.Units))
+	}
+	if r.VerticalSpeedUnit != "" {
+		b = append(b, `,"vsUnit":`...)
+		b = appendJSONString(b, string(r.VerticalSpeedUnit))
+	}
+	if r.Final {
+		b = append(b, `,"final":true`...)
+	}
+	return append(b, '}'), nil
+}
+
+// statusText and emergencyText hold the text form of every valid status
+// and emergency, and "" for the rest, so encoding them does not allocate.
+var statusText, emergencyText = func() (status, emergency [256]string) {
+	for i := range status {
+		if text, err := domain.Status(i).MarshalText(); err == nil {
+			status[i] = string(text)
+		}
+		if text, err := domain.Emergency(i).MarshalText(); err == nil {
+			emergency[i] = string(text)
+		}
+	}
+	return status, emergency
+}()
+
+// appendJSONFloat formats f the way encoding/json does: plainly unless it
+// is very large or small, with short exponents.
+func appendJSONFloat(b []byte, f float64) ([]byte, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return b, fmt.Errorf("json: unsupported value %v", f)
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	b = strconv.AppendFloat(b, f, format, -1, 64)
+	if format == 'e' {
+		// Clean up e-09 to e-9.
+		if n := len(b); n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	return b, nil
+}
+
+// appendJSONString quotes s. Identifiers and status names are plain ASCII,
+// so anything that needs escaping is left to encoding/json.
+func appendJSONString(b []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c >= utf8.RuneSelf || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+			quoted, _ := json.Marshal(s)
+			return append(b, quoted...)
+		}
+	}
+	b = append(b, '"')
+	b = append(b, s...)
+	return append(b, '"')
+}
+
+// Compact returns a JSON encoder that leaves out the optional fields.
+func (JSON) Compact() Encoder {
+	return JSON{OmitOptional: true}
+}
+
+func (JSON) ContentType() string {
+	return "application/json"
+}
@@ -0,0 +1,97 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+	"testing/quick"
+
+	"plane-producer/src/domain"
+)
+
+// TestJSONMatchesEncodingJSON checks that JSON writes exactly the bytes
+// encoding/json does, for generated records and for the strings and floats
+// where the two are most likely to part ways.
+func TestJSONMatchesEncodingJSON(t *testing.T) {
+	matches := func(r domain.FlightRecord) bool {
+		want, err := json.Marshal(r)
+		if err != nil {
+			t.Logf("json.Marshal(%+v): %v", r, err)
+			return false
+		}
+		got, err := JSON{}.Encode(r)
+		if err != nil {
+			t.Logf("encoding %+v: %v", r, err)
+			return false
+		}
+		if !bytes.Equal(got, want) {
+			t.Logf("got  %s\nwant %s", got, want)
+			return false
+		}
+		return true
+	}
+
+	generated := func(a anyRecord) bool { return matches(domain.FlightRecord(a)) }
+	if err := quick.Check(generated, &quick.Config{MaxCount: 10000}); err != nil {
+		t.Error(err)
+	}
+
+	for _, s := range []string{
+		"",
+		`quote " and backslash \`,
+		"<script>&amp;</script>",
+		"control \x00\x01\x1f\x7f",
+		"\b\f\n\r\t",
+		"invalid \xff\xfe utf-8 \xc3",
+		"line\u2028and paragraph\u2029separators",
+		"é☃\U0001F6E9",
+	} {
+		r := cruising
+		r.TailNum, r.FlightID, r.Origin, r.Destination = "N"+s, "UT"+s, s, s
+		if !matches(r) {
+			t.Errorf("%q is encoded differently", s)
+		}
+	}
+
+	for _, f := range []float64{
+		0, math.Copysign(0, -1), 1, -1, 0.1, 1e-6, 9.99999e-7, 1e-7, 123456789.125,
+		1e20, 1e21, -1e21, math.MaxFloat64, math.SmallestNonzeroFloat64, 5e-324, 1e-100,
+	} {
+		r := cruising
+		r.Latitude, r.Altitude, r.DeviationMiles = f, f, f
+		if !matches(r) {
+			t.Errorf("%v is encoded differently", f)
+		}
+	}
+}
+
+func BenchmarkJSON(b *testing.B) {
+	b.Run("Append", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf []byte
+		var err error
+		for i := 0; i < b.N; i++ {
+			if buf, err = (JSON{}).Append(buf[:0], cruising); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Encode", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := (JSON{}).Encode(cruising); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	// The baseline Append replaced.
+	b.Run("encoding/json", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := json.Marshal(cruising); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding"
+	"reflect"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"plane-producer/src/domain"
+)
+
+// MessagePack encodes records as MessagePack maps keyed by the same
+// abbreviated names as the JSON encoder, with floats narrowed wherever that
+// loses no precision.
+type MessagePack struct{}
+
+func init() {
+	// Write enums by name, as JSON does, rather than as raw text bytes.
+	registerText(domain.Status(0))
+	registerText(domain.Emergency(0))
+	registerText(domain.Squawk(0))
+}
+
+func registerText(value encoding.TextMarshaler) {
+	msgpack.Register(value,
+		func(enc *msgpack.Encoder, v reflect.Value) error {
+			text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+			if err != nil {
+				return err
+			}
+			return enc.EncodeString(string(text))
+		},
+		func(dec *msgpack.Decoder, v reflect.Value) error {
+			name, err := dec.DecodeString()
+			if err != nil {
+				return err
+			}
+			return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(name))
+		})
+}
+
+func (MessagePack) Encode(record domain.FlightRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	enc.UseCompactInts(true)
+	enc.UseCompactFloats(true)
+
+	if err := enc.Encode(record); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (MessagePack) ContentType() string {
+	return "application/msgpack"
+}
@@ -0,0 +1,124 @@
+package encoder
+
+import (
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"plane-producer/src/domain"
+)
+
+// Protobuf encodes records in the binary format described by
+// flightRecord.proto. The message is small and stable enough that it is
+// written field by field rather than through generated code.
+type Protobuf struct{}
+
+func (Protobuf) Encode(record domain.FlightRecord) ([]byte, error) {
+	b := make([]byte, 0, 128)
+
+	b = appendString(b, 1, record.TailNum)
+	b = appendString(b, 2, record.FlightID)
+	if record.Timestamp != 0 {
+		b = protowire.AppendTag(b, 3, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(record.Timestamp))
+	}
+
+	b = appendDouble(b, 4, record.Latitude)
+	b = appendDouble(b, 5, record.Longitude)
+	b = appendFloat(b, 6, record.Altitude)
+
+	b = appendFloat(b, 7, record.Airspeed)
+	b = appendFloat(b, 8, record.GroundSpeed)
+	b = appendFloat(b, 9, record.VerticalSpeed)
+
+	b = appendFloat(b, 10, record.Compass)
+	b = appendFloat(b, 11, record.Heading)
+
+	b = appendFloat(b, 12, record.Attitude)
+	b = appendFloat(b, 13, record.Bank)
+	b = appendFloat(b, 14, record.RateOfTurn)
+
+	b = appendFloat(b, 15, record.DeviationDegrees)
+	b = appendFloat(b, 16, record.DeviationMiles)
+
+	if record.Status != 0 {
+		b = protowire.AppendTag(b, 17, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(record.Status))
+	}
+
+	b = appendString(b, 18, record.Origin)
+	b = appendString(b, 19, record.Destination)
+
+	if record.Emergency != 0 {
+		b = protowire.AppendTag(b, 20, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(record.Emergency))
+	}
+
+	if record.ETA != 0 {
+		b = protowire.AppendTag(b, 21, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(record.ETA))
+	}
+
+	b = appendFloat(b, 22, record.IndicatedAirspeed)
+
+	b = appendString(b, 23, record.Squawk.String())
+
+	if record.Version != 0 {
+		b = protowire.AppendTag(b, 24, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(record.Version))
+	}
+	if record.Sequence != 0 {
+		b = protowire.AppendTag(b, 25, protowire.VarintType)
+		b = protowire.AppendVarint(b, record.Sequence)
+	}
+
+	if record.TakeOffHeld {
+		b = protowire.AppendTag(b, 26, protowire.VarintType)
+		b = protowire.AppendVarint(b, 1)
+	}
+	if record.LandingHeld {
+		b = protowire.AppendTag(b, 27, protowire.VarintType)
+		b = protowire.AppendVarint(b, 1)
+	}
+
+	b = appendString(b, 28, record.Gate)
+	b = appendString(b, 29, string(record.Units))
+	b = appendString(b, 30, string(record.VerticalSpeedUnit))
+
+	if record.Final {
+		b = protowire.AppendTag(b, 31, protowire.VarintType)
+		b = protowire.AppendVarint(b, 1)
+	}
+
+	return b, nil
+}
+
+func (Protobuf) ContentType() string {
+	return "application/x-protobuf"
+}
+
+// The helpers below skip zero values, matching proto3's implicit presence.
+
+func appendString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+func appendDouble(b []byte, num protowire.Number, v float64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, math.Float64bits(v))
+}
+
+func appendFloat(b []byte, num protowire.Number, v float64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.Fixed32Type)
+	return protowire.AppendFixed32(b, math.Float32bits(float32(v)))
+}
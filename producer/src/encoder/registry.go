@@ -0,0 +1,167 @@
+package encoder
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// SchemaRegistry registers a schema and returns the header that identifies
+// it at the front of every encoded payload.
+type SchemaRegistry interface {
+	Register(ctx context.Context, schema string) ([]byte, error)
+}
+
+// ConfluentRegistry registers schemas with a Confluent Schema Registry.
+// Payloads are framed with a zero magic byte and the 4-byte schema ID.
+type ConfluentRegistry struct {
+	URL      string
+	Subject  string
+	Username string
+	Password string
+	Client   *http.Client
+}
+
+func (r ConfluentRegistry) Register(ctx context.Context, schema string) ([]byte, error) {
+	if r.URL == "" || r.Subject == "" {
+		return nil, errors.New("confluent registry: url and subject are required")
+	}
+
+	body, err := json.Marshal(map[string]string{"schemaType": "AVRO", "schema": schema})
+	if err != nil {
+		return nil, err
+	}
+
+	url := strings.TrimSuffix(r.URL, "/") + "/subjects/" + r.Subject + "/versions"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	if r.Username != "" {
+		req.SetBasicAuth(r.Username, r.Password)
+	}
+
+	var out struct {
+		ID uint32 `json:"id"`
+	}
+	if err := doJSON(r.Client, req, &out); err != nil {
+		return nil, fmt.Errorf("confluent registry: %w", err)
+	}
+
+	header := []byte{0}
+	return binary.BigEndian.AppendUint32(header, out.ID), nil
+}
+
+// GlueRegistry registers schemas with an AWS Glue Schema Registry, creating
+// the schema if it does not exist yet. Payloads are framed with the Glue
+// header version byte, a compression byte and the 16-byte schema version ID.
+type GlueRegistry struct {
+	Region       string
+	RegistryName string
+	SchemaName   string
+	Client       *http.Client
+}
+
+func (r GlueRegistry) Register(ctx context.Context, schema string) ([]byte, error) {
+	if r.RegistryName == "" || r.SchemaName == "" {
+		return nil, errors.New("glue registry: registry and schema names are required")
+	}
+
+	var opts []func(*config.LoadOptions) error
+	if r.Region != "" {
+		opts = append(opts, config.WithRegion(r.Region))
+	}
+	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("glue registry: loading aws config: %w", err)
+	}
+
+	var out struct {
+		SchemaVersionId string
+	}
+	err = r.call(ctx, awsCfg, "RegisterSchemaVersion", map[string]interface{}{
+		"SchemaId":         map[string]string{"RegistryName": r.RegistryName, "SchemaName": r.SchemaName},
+		"SchemaDefinition": schema,
+	}, &out)
+	if err != nil && strings.Contains(err.Error(), "EntityNotFoundException") {
+		err = r.call(ctx, awsCfg, "CreateSchema", map[string]interface{}{
+			"RegistryId":       map[string]string{"RegistryName": r.RegistryName},
+			"SchemaName":       r.SchemaName,
+			"DataFormat":       "AVRO",
+			"Compatibility":    "BACKWARD",
+			"SchemaDefinition": schema,
+		}, &out)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("glue registry: %w", err)
+	}
+
+	id, err := hex.DecodeString(strings.ReplaceAll(out.SchemaVersionId, "-", ""))
+	if err != nil || len(id) != 16 {
+		return nil, fmt.Errorf("glue registry: invalid schema version id %q", out.SchemaVersionId)
+	}
+
+	return append([]byte{3, 0}, id...), nil
+}
+
+// call invokes a Glue API action over its JSON protocol.
+func (r GlueRegistry) call(ctx context.Context, awsCfg aws.Config, action string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("https://glue.%s.amazonaws.com/", awsCfg.Region)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "AWSGlue."+action)
+
+	creds, err := awsCfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("retrieving aws credentials: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	if err := v4.NewSigner().SignHTTP(ctx, creds, req, hex.EncodeToString(sum[:]), "glue", awsCfg.Region, time.Now()); err != nil {
+		return fmt.Errorf("signing request: %w", err)
+	}
+
+	return doJSON(r.Client, req, out)
+}
+
+func doJSON(client *http.Client, req *http.Request, out interface{}) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,435 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+	"testing/quick"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"plane-producer/src/domain"
+	"plane-producer/src/units"
+)
+
+// anyRecord generates valid records for testing/quick, with identifiers
+// that need escaping and floats from the whole range as well as realistic
+// ones.
+type anyRecord domain.FlightRecord
+
+func (anyRecord) Generate(rng *rand.Rand, size int) reflect.Value {
+	// Tail numbers and flight IDs are required, so never empty.
+	str := func(min int) string {
+		const chars = `ABCXYZ0189 ,"\<>&` + "\n\té☃\U0001F6E9"
+		runes := []rune(chars)
+		b := make([]rune, min+rng.Intn(size+1))
+		for i := range b {
+			b[i] = runes[rng.Intn(len(runes))]
+		}
+		return string(b)
+	}
+	num := func() float64 {
+		switch rng.Intn(4) {
+		case 0:
+			return 0
+		case 1:
+			return rng.NormFloat64() * math.Pow(10, float64(rng.Intn(40)-20))
+		default:
+			return (rng.Float64() - 0.5) * 1000
+		}
+	}
+	r := domain.FlightRecord{
+		TailNum:           str(1),
+		FlightID:          str(1),
+		Timestamp:         rng.Int63(),
+		Origin:            str(0),
+		Destination:       str(0),
+		Latitude:          (rng.Float64() - 0.5) * 180,
+		Longitude:         (rng.Float64() - 0.5) * 360,
+		Altitude:          num(),
+		Airspeed:          num(),
+		IndicatedAirspeed: num(),
+		GroundSpeed:       num(),
+		VerticalSpeed:     num(),
+		Compass:           num(),
+		Heading:           num(),
+		Attitude:          num(),
+		Bank:              num(),
+		RateOfTurn:        num(),
+		DeviationDegrees:  num(),
+		DeviationMiles:    num(),
+		Status:            domain.Status(rng.Intn(int(domain.Landing) + 1)),
+		Emergency:         domain.Emergency(rng.Intn(int(domain.Medical) + 1)),
+		Squawk:            domain.Squawk(rng.Intn(07777 + 1)),
+		ETA:               rng.Int63n(2) * rng.Int63(),
+		Version:           domain.SchemaVersion,
+		Sequence:          rng.Uint64(),
+		TakeOffHeld:       rng.Intn(2) == 0,
+		LandingHeld:       rng.Intn(2) == 0,
+		Gate:              str(0),
+		Units:             []units.System{"", units.Metric, units.Statute}[rng.Intn(3)],
+		VerticalSpeedUnit: []units.VerticalSpeedUnit{"", units.FPS, units.MPS}[rng.Intn(3)],
+		Final:             rng.Intn(2) == 0,
+	}
+	return reflect.ValueOf(anyRecord(r))
+}
+
+// narrow rounds the fields an encoder writes as 32-bit floats.
+func narrow(r domain.FlightRecord) domain.FlightRecord {
+	for _, f := range []*float64{
+		&r.Altitude, &r.Airspeed, &r.IndicatedAirspeed, &r.GroundSpeed, &r.VerticalSpeed,
+		&r.Compass, &r.Heading, &r.Attitude, &r.Bank, &r.RateOfTurn,
+		&r.DeviationDegrees, &r.DeviationMiles,
+	} {
+		*f = float64(float32(*f))
+	}
+	return r
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		enc    Encoder
+		decode func([]byte) (domain.FlightRecord, error)
+		// want is what the record should decode to, for encoders that
+		// narrow some fields.
+		want func(domain.FlightRecord) domain.FlightRecord
+	}{
+		{"json", JSON{}, domain.ParseFlightRecord, nil},
+		{"msgpack", MessagePack{}, decodeMessagePack, nil},
+		{"csv", CSV{}, decodeCSV, nil},
+		{"protobuf", Protobuf{}, decodeProtobuf, narrow},
+		{"avro", Avro{}, decodeAvro, narrow},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			roundTrips := func(a anyRecord) bool {
+				r := domain.FlightRecord(a)
+				data, err := tc.enc.Encode(r)
+				if err != nil {
+					t.Logf("encoding %+v: %v", r, err)
+					return false
+				}
+				got, err := tc.decode(data)
+				if err != nil {
+					t.Logf("decoding %q: %v", data, err)
+					return false
+				}
+				want := r
+				if tc.want != nil {
+					want = tc.want(r)
+				}
+				if got != want {
+					t.Logf("got  %+v\nwant %+v", got, want)
+					return false
+				}
+				return true
+			}
+			if err := quick.Check(roundTrips, &quick.Config{MaxCount: 2000}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func decodeMessagePack(data []byte) (domain.FlightRecord, error) {
+	var r domain.FlightRecord
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	err := dec.Decode(&r)
+	return r, err
+}
+
+func decodeCSV(data []byte) (domain.FlightRecord, error) {
+	var r domain.FlightRecord
+	row, err := csv.NewReader(bytes.NewReader(data)).Read()
+	if err != nil {
+		return r, err
+	}
+	if len(row) != len(CSVColumns) {
+		return r, fmt.Errorf("%d columns, want %d", len(row), len(CSVColumns))
+	}
+
+	var errs []error
+	float := func(s string) float64 {
+		v, err := strconv.ParseFloat(s, 64)
+		errs = append(errs, err)
+		return v
+	}
+	integer := func(s string) int64 {
+		v, err := strconv.ParseInt(s, 10, 64)
+		errs = append(errs, err)
+		return v
+	}
+	for i, col := range CSVColumns {
+		v := row[i]
+		switch col {
+		case "plane":
+			r.TailNum = v
+		case "flight":
+			r.FlightID = v
+		case "time":
+			r.Timestamp = integer(v)
+		case "lat":
+			r.Latitude = float(v)
+		case "long":
+			r.Longitude = float(v)
+		case "alt":
+			r.Altitude = float(v)
+		case "knots":
+			r.Airspeed = float(v)
+		case "gs":
+			r.GroundSpeed = float(v)
+		case "vs":
+			r.VerticalSpeed = float(v)
+		case "compass":
+			r.Compass = float(v)
+		case "heading":
+			r.Heading = float(v)
+		case "attitude":
+			r.Attitude = float(v)
+		case "bank":
+			r.Bank = float(v)
+		case "turn":
+			r.RateOfTurn = float(v)
+		case "devDeg":
+			r.DeviationDegrees = float(v)
+		case "devNm":
+			r.DeviationMiles = float(v)
+		case "status":
+			errs = append(errs, r.Status.UnmarshalText([]byte(v)))
+		case "orig":
+			r.Origin = v
+		case "dest":
+			r.Destination = v
+		case "emerg":
+			errs = append(errs, r.Emergency.UnmarshalText([]byte(v)))
+		case "eta":
+			r.ETA = integer(v)
+		case "ias":
+			r.IndicatedAirspeed = float(v)
+		case "squawk":
+			errs = append(errs, r.Squawk.UnmarshalText([]byte(v)))
+		case "v":
+			r.Version = int(integer(v))
+		case "seq":
+			seq, err := strconv.ParseUint(v, 10, 64)
+			r.Sequence = seq
+			errs = append(errs, err)
+		case "toHeld":
+			held, err := strconv.ParseBool(v)
+			r.TakeOffHeld = held
+			errs = append(errs, err)
+		case "ldgHeld":
+			held, err := strconv.ParseBool(v)
+			r.LandingHeld = held
+			errs = append(errs, err)
+		case "gate":
+			r.Gate = v
+		case "units":
+			r.Units = units.System(v)
+		case "vsUnit":
+			r.VerticalSpeedUnit = units.VerticalSpeedUnit(v)
+		case "final":
+			final, err := strconv.ParseBool(v)
+			r.Final = final
+			errs = append(errs, err)
+		default:
+			return r, fmt.Errorf("unknown column %q", col)
+		}
+	}
+	for _, err := range errs {
+		if err != nil {
+			return r, err
+		}
+	}
+	return r, nil
+}
+
+// decodeProtobuf reads the message described by flightRecord.proto.
+func decodeProtobuf(data []byte) (domain.FlightRecord, error) {
+	var r domain.FlightRecord
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return r, protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		var v uint64
+		var s string
+		switch typ {
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(data)
+		case protowire.Fixed32Type:
+			var u uint32
+			u, n = protowire.ConsumeFixed32(data)
+			v = uint64(u)
+		case protowire.Fixed64Type:
+			v, n = protowire.ConsumeFixed64(data)
+		case protowire.BytesType:
+			s, n = protowire.ConsumeString(data)
+		default:
+			return r, fmt.Errorf("field %d: unexpected wire type %d", num, typ)
+		}
+		if n < 0 {
+			return r, protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		f32 := float64(math.Float32frombits(uint32(v)))
+		switch num {
+		case 1:
+			r.TailNum = s
+		case 2:
+			r.FlightID = s
+		case 3:
+			r.Timestamp = int64(v)
+		case 4:
+			r.Latitude = math.Float64frombits(v)
+		case 5:
+			r.Longitude = math.Float64frombits(v)
+		case 6:
+			r.Altitude = f32
+		case 7:
+			r.Airspeed = f32
+		case 8:
+			r.GroundSpeed = f32
+		case 9:
+			r.VerticalSpeed = f32
+		case 10:
+			r.Compass = f32
+		case 11:
+			r.Heading = f32
+		case 12:
+			r.Attitude = f32
+		case 13:
+			r.Bank = f32
+		case 14:
+			r.RateOfTurn = f32
+		case 15:
+			r.DeviationDegrees = f32
+		case 16:
+			r.DeviationMiles = f32
+		case 17:
+			r.Status = domain.Status(v)
+		case 18:
+			r.Origin = s
+		case 19:
+			r.Destination = s
+		case 20:
+			r.Emergency = domain.Emergency(v)
+		case 21:
+			r.ETA = int64(v)
+		case 22:
+			r.IndicatedAirspeed = f32
+		case 23:
+			if err := r.Squawk.UnmarshalText([]byte(s)); err != nil {
+				return r, err
+			}
+		case 24:
+			r.Version = int(v)
+		case 25:
+			r.Sequence = v
+		case 26:
+			r.TakeOffHeld = v != 0
+		case 27:
+			r.LandingHeld = v != 0
+		case 28:
+			r.Gate = s
+		case 29:
+			r.Units = units.System(s)
+		case 30:
+			r.VerticalSpeedUnit = units.VerticalSpeedUnit(s)
+		case 31:
+			r.Final = v != 0
+		}
+	}
+	return r, nil
+}
+
+// decodeAvro reads a record written with AvroSchema, field by field in
+// schema order.
+func decodeAvro(data []byte) (domain.FlightRecord, error) {
+	var r domain.FlightRecord
+	var err error
+	long := func() int64 {
+		v, n := binary.Varint(data)
+		if n <= 0 {
+			err = fmt.Errorf("bad varint")
+			return 0
+		}
+		data = data[n:]
+		return v
+	}
+	str := func() string {
+		n := long()
+		if err != nil || n < 0 || int(n) > len(data) {
+			err = fmt.Errorf("bad string length %d", n)
+			return ""
+		}
+		s := string(data[:n])
+		data = data[n:]
+		return s
+	}
+	double := func() float64 {
+		if len(data) < 8 {
+			err = fmt.Errorf("short double")
+			return 0
+		}
+		v := math.Float64frombits(binary.LittleEndian.Uint64(data))
+		data = data[8:]
+		return v
+	}
+	boolean := func() bool {
+		if len(data) < 1 || data[0] > 1 {
+			err = fmt.Errorf("bad boolean")
+			return false
+		}
+		v := data[0] == 1
+		data = data[1:]
+		return v
+	}
+	float := func() float64 {
+		if len(data) < 4 {
+			err = fmt.Errorf("short float")
+			return 0
+		}
+		v := math.Float32frombits(binary.LittleEndian.Uint32(data))
+		data = data[4:]
+		return float64(v)
+	}
+
+	r.TailNum, r.FlightID, r.Timestamp = str(), str(), long()
+	r.Latitude, r.Longitude, r.Altitude = double(), double(), float()
+	r.Airspeed, r.GroundSpeed, r.VerticalSpeed = float(), float(), float()
+	r.Compass, r.Heading = float(), float()
+	r.Attitude, r.Bank, r.RateOfTurn = float(), float(), float()
+	r.DeviationDegrees, r.DeviationMiles = float(), float()
+	r.Status = domain.Status(long())
+	r.Origin, r.Destination = str(), str()
+	r.Emergency = domain.Emergency(long())
+	r.ETA = long()
+	r.IndicatedAirspeed = float()
+	squawk := str()
+	r.Version = int(long())
+	r.Sequence = uint64(long())
+	r.TakeOffHeld, r.LandingHeld = boolean(), boolean()
+	r.Gate = str()
+	r.Units = units.System(str())
+	r.VerticalSpeedUnit = units.VerticalSpeedUnit(str())
+	r.Final = boolean()
+	if err != nil {
+		return r, err
+	}
+	if len(data) > 0 {
+		return r, fmt.Errorf("%d bytes left over", len(data))
+	}
+	return r, r.Squawk.UnmarshalText([]byte(squawk))
+}
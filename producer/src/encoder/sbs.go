@@ -0,0 +1,81 @@
+package encoder
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"plane-producer/src/domain"
+)
+
+// SBS encodes each record as BaseStation (SBS-1) messages, the CSV feed
+// served on port 30003 by ADS-B receivers and read by tools such as Virtual
+// Radar Server. Every record produces three CRLF-terminated lines: an
+// identification message (MSG,1), an airborne position (MSG,3) and an
+// airborne velocity (MSG,4), followed by a surveillance ID message (MSG,6)
+// carrying the squawk once one is assigned. Times are given in Location,
+// or UTC if it is nil.
+type SBS struct {
+	Location *time.Location
+}
+
+func (s SBS) Encode(record domain.FlightRecord) ([]byte, error) {
+	ts := time.UnixMilli(record.Timestamp).UTC()
+	if s.Location != nil {
+		ts = ts.In(s.Location)
+	}
+	date := ts.Format("2006/01/02")
+	clock := ts.Format("15:04:05.000")
+	hexIdent := sbsHexIdent(record.TailNum)
+
+	ground := "0"
+	if record.Status == domain.Idle || record.Status == domain.Taxi {
+		ground = "-1"
+	}
+	emergency := "0"
+	if record.Squawk.Special() {
+		emergency = "-1"
+	}
+
+	f := func(v float64, prec int) string {
+		return strconv.FormatFloat(v, 'f', prec, 64)
+	}
+
+	msg := func(transmission string, fields [12]string) string {
+		return strings.Join(append([]string{
+			"MSG", transmission, "1", "1", hexIdent, "1", date, clock, date, clock,
+		}, fields[:]...), ",") + "\r\n"
+	}
+
+	var b strings.Builder
+	b.WriteString(msg("1", [12]string{record.FlightID, "", "", "", "", "", "", "", "", "", "", ""}))
+	b.WriteString(msg("3", [12]string{
+		"", f(record.Altitude, 0), "", "", f(record.Latitude, 5), f(record.Longitude, 5),
+		"", "", "0", emergency, "0", ground,
+	}))
+	b.WriteString(msg("4", [12]string{
+		"", "", f(record.GroundSpeed, 0), f(record.Compass, 0), "", "",
+		f(record.VerticalSpeed, 0), "", "", "", "", ground,
+	}))
+	if record.Squawk != 0 {
+		b.WriteString(msg("6", [12]string{
+			"", f(record.Altitude, 0), "", "", "", "",
+			"", record.Squawk.String(), "0", emergency, "0", ground,
+		}))
+	}
+
+	return []byte(b.String()), nil
+}
+
+func (SBS) ContentType() string {
+	return "text/plain"
+}
+
+// sbsHexIdent derives a stable 24-bit ICAO-style address from the tail
+// number, since simulated aircraft have no real transponder address.
+func sbsHexIdent(tailNum string) string {
+	h := fnv.New32a()
+	h.Write([]byte(tailNum))
+	return strings.ToUpper(strconv.FormatUint(uint64(h.Sum32()&0xFFFFFF)|0x1000000, 16)[1:])
+}
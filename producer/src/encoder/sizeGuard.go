@@ -0,0 +1,91 @@
+package encoder
+
+import (
+	"fmt"
+	"math"
+
+	"plane-producer/src/domain"
+)
+
+// MaxRecordBytes is the per-record budget the stream is designed around.
+const MaxRecordBytes = 1024
+
+// SizeGuard wraps an encoder and keeps payloads within MaxBytes. A payload
+// that is too large is re-encoded with lower float precision, then with the
+// optional attitude, deviation and route fields cleared, which encoders
+// that can leave them out altogether do. Only if it still does not fit is
+// an error returned.
+type SizeGuard struct {
+	Encoder
+	MaxBytes int
+}
+
+// Compacter is implemented by encoders that can leave out the optional
+// fields of a record when they are empty, rather than writing them as
+// zeros.
+type Compacter interface {
+	Compact() Encoder
+}
+
+// trimSteps are applied in order until the record fits.
+var trimSteps = []func(*domain.FlightRecord){
+	lowerPrecision,
+	dropOptionalFields,
+}
+
+func (g SizeGuard) Encode(record domain.FlightRecord) ([]byte, error) {
+	limit := g.MaxBytes
+	if limit <= 0 {
+		limit = MaxRecordBytes
+	}
+
+	b, err := g.Encoder.Encode(record)
+	if err != nil || len(b) <= limit {
+		return b, err
+	}
+
+	for _, trim := range trimSteps {
+		trim(&record)
+		b, err = g.Encoder.Encode(record)
+		if err != nil || len(b) <= limit {
+			return b, err
+		}
+	}
+	if c, ok := g.Encoder.(Compacter); ok {
+		b, err = c.Compact().Encode(record)
+		if err != nil || len(b) <= limit {
+			return b, err
+		}
+	}
+
+	return nil, fmt.Errorf("record for %s is %d bytes, over the %d byte budget", record.TailNum, len(b), limit)
+}
+
+// lowerPrecision rounds positions to 5 decimal places (about a metre) and
+// everything else to a tenth.
+func lowerPrecision(r *domain.FlightRecord) {
+	round := func(v float64, places int) float64 {
+		p := math.Pow(10, float64(places))
+		return math.Round(v*p) / p
+	}
+
+	r.Latitude = round(r.Latitude, 5)
+	r.Longitude = round(r.Longitude, 5)
+	for _, v := range []*float64{
+		&r.Altitude, &r.Airspeed, &r.IndicatedAirspeed, &r.GroundSpeed, &r.VerticalSpeed,
+		&r.Compass, &r.Heading, &r.Attitude, &r.Bank, &r.RateOfTurn,
+		&r.DeviationDegrees, &r.DeviationMiles,
+	} {
+		*v = round(*v, 1)
+	}
+}
+
+func dropOptionalFields(r *domain.FlightRecord) {
+	r.Attitude = 0
+	r.Bank = 0
+	r.RateOfTurn = 0
+	r.DeviationDegrees = 0
+	r.DeviationMiles = 0
+	r.Origin = ""
+	r.Destination = ""
+}
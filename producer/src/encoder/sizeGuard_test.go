@@ -0,0 +1,93 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"plane-producer/src/domain"
+)
+
+// TestSizeGuardWithinBudget checks records under and exactly at the budget
+// are written as they are, whatever the format.
+func TestSizeGuardWithinBudget(t *testing.T) {
+	for _, format := range formats {
+		enc, err := New(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := enc.Encode(cruising)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, limit := range []int{len(want) + 1, len(want)} {
+			got, err := SizeGuard{Encoder: enc, MaxBytes: limit}.Encode(cruising)
+			if err != nil {
+				t.Fatalf("%s within %d bytes: %v", format, limit, err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s within %d bytes was changed:\n%s\nwant\n%s", format, limit, got, want)
+			}
+		}
+	}
+}
+
+// TestSizeGuardOverBudget checks a record over the budget is trimmed only
+// as far as it needs to be, and that one that cannot be made to fit is an
+// error.
+func TestSizeGuardOverBudget(t *testing.T) {
+	full, err := JSON{}.Encode(cruising)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trimmed := cruising
+	lowerPrecision(&trimmed)
+	rounded, err := JSON{}.Encode(trimmed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dropOptionalFields(&trimmed)
+	dropped, err := JSON{}.Encode(trimmed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compact, err := JSON{}.Compact().Encode(trimmed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !(len(compact) < len(dropped) && len(dropped) < len(rounded) && len(rounded) < len(full)) {
+		t.Fatalf("trimming does not shrink the record: %d, %d, %d then %d bytes", len(full), len(rounded), len(dropped), len(compact))
+	}
+
+	for _, tc := range []struct {
+		name  string
+		limit int
+		want  []byte
+	}{
+		{"one byte over", len(full) - 1, rounded},
+		{"rounded", len(rounded), rounded},
+		{"optional fields cleared", len(rounded) - 1, dropped},
+		{"compacted", len(dropped) - 1, compact},
+		{"just fits compacted", len(compact), compact},
+	} {
+		got, err := SizeGuard{Encoder: JSON{}, MaxBytes: tc.limit}.Encode(cruising)
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if len(got) > tc.limit {
+			t.Errorf("%s: %d bytes, over the %d byte budget", tc.name, len(got), tc.limit)
+		}
+		if !bytes.Equal(got, tc.want) {
+			t.Errorf("%s: got\n%s\nwant\n%s", tc.name, got, tc.want)
+		}
+		var r domain.FlightRecord
+		if err := json.Unmarshal(got, &r); err != nil || r.TailNum != cruising.TailNum {
+			t.Errorf("%s: does not decode: %v", tc.name, err)
+		}
+	}
+
+	if got, err := (SizeGuard{Encoder: JSON{}, MaxBytes: len(compact) - 1}).Encode(cruising); err == nil {
+		t.Errorf("a record that cannot fit was encoded as %d bytes", len(got))
+	}
+}
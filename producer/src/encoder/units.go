@@ -0,0 +1,35 @@
+package encoder
+
+import (
+	"plane-producer/src/domain"
+	"plane-producer/src/units"
+)
+
+// Units wraps an encoder and converts speeds, altitudes and distances from
+// knots, feet and nautical miles into System, and vertical speeds from feet
+// per minute into VerticalSpeed, before encoding. Field names are unchanged,
+// but records in other than imperial units or feet per minute are written
+// as domain.UnitsVersion and name their units, so consumers convert them
+// back or reject them.
+type Units struct {
+	Encoder
+	System        units.System
+	VerticalSpeed units.VerticalSpeedUnit
+}
+
+func (u Units) Encode(record domain.FlightRecord) ([]byte, error) {
+	s := u.System
+	if s != units.Imperial {
+		record.Version, record.Units = domain.UnitsVersion, s
+	}
+	if u.VerticalSpeed != units.FPM {
+		record.Version, record.VerticalSpeedUnit = domain.UnitsVersion, u.VerticalSpeed
+	}
+	record.Altitude = s.Length(units.Feet(record.Altitude))
+	record.Airspeed = s.Speed(units.Knots(record.Airspeed))
+	record.IndicatedAirspeed = s.Speed(units.Knots(record.IndicatedAirspeed))
+	record.GroundSpeed = s.Speed(units.Knots(record.GroundSpeed))
+	record.DeviationMiles = s.Distance(units.NauticalMiles(record.DeviationMiles))
+	record.VerticalSpeed = u.VerticalSpeed.Express(units.FeetPerMinute(record.VerticalSpeed))
+	return u.Encoder.Encode(record)
+}
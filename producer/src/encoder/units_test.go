@@ -0,0 +1,68 @@
+package encoder
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"plane-producer/src/domain"
+	"plane-producer/src/units"
+)
+
+// TestUnitsParseBack checks reports in each unit system and vertical speed
+// unit are marked with them and read back into knots, feet, nautical miles
+// and feet per minute, and that imperial ones are written as before.
+func TestUnitsParseBack(t *testing.T) {
+	climbing := cruising
+	climbing.VerticalSpeed = 1850
+	plain, err := JSON{}.Encode(climbing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		system units.System
+		vs     units.VerticalSpeedUnit
+	}{
+		{units.Imperial, units.FPM},
+		{units.Metric, units.FPM},
+		{units.Statute, units.FPM},
+		{units.Imperial, units.FPS},
+		{units.Metric, units.MPS},
+	} {
+		system := tc.system
+		data, err := Units{Encoder: JSON{}, System: system, VerticalSpeed: tc.vs}.Encode(climbing)
+		if err != nil {
+			t.Fatal(err)
+		}
+		converted := system != units.Imperial || tc.vs != units.FPM
+		if !converted && !bytes.Equal(data, plain) {
+			t.Errorf("imperial report changed:\n%s\nwant\n%s", data, plain)
+		}
+		if converted && !bytes.Contains(data, []byte(`"v":2,`)) {
+			t.Errorf("%s %s report is not version 2: %s", system, tc.vs, data)
+		}
+
+		got, err := domain.ParseFlightRecord(data)
+		if err != nil {
+			t.Fatalf("%s: %v", system, err)
+		}
+		for _, f := range []struct {
+			name      string
+			got, want float64
+		}{
+			{"alt", got.Altitude, cruising.Altitude},
+			{"knots", got.Airspeed, cruising.Airspeed},
+			{"ias", got.IndicatedAirspeed, cruising.IndicatedAirspeed},
+			{"gs", got.GroundSpeed, cruising.GroundSpeed},
+			{"devNm", got.DeviationMiles, cruising.DeviationMiles},
+			{"vs", got.VerticalSpeed, climbing.VerticalSpeed},
+		} {
+			if math.Abs(f.got-f.want) > 1e-9*math.Max(1, f.want) {
+				t.Errorf("%s %s %s read back as %v, want %v", system, tc.vs, f.name, f.got, f.want)
+			}
+		}
+		if got.Units != "" || got.VerticalSpeedUnit != "" || got.Version != domain.SchemaVersion {
+			t.Errorf("%s %s read back in %q and %q, version %d", system, tc.vs, got.Units, got.VerticalSpeedUnit, got.Version)
+		}
+	}
+}
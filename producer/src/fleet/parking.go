@@ -0,0 +1,101 @@
+package fleet
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"plane-producer/src/domain"
+)
+
+// Parked is an aircraft at the gate after a flight: its last report,
+// which airport and gate it is at, the gate empty if it is on a remote
+// stand, and when its next flight departs, zero if none is planned.
+type Parked struct {
+	Record  domain.FlightRecord
+	Airport string
+	Gate    string
+	Next    time.Time
+}
+
+// Parking tracks the aircraft at the gate between flights, by tail number,
+// so they can be heard from while parked. It is safe for concurrent use.
+type Parking struct {
+	mu     sync.Mutex
+	byTail map[string]Parked
+}
+
+// NewParking creates an empty parking.
+func NewParking() *Parking {
+	return &Parking{byTail: make(map[string]Parked)}
+}
+
+// Park notes an aircraft as parked.
+func (p *Parking) Park(parked Parked) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.byTail[parked.Record.TailNum] = parked
+}
+
+// Leave notes that an aircraft is no longer parked, e.g. as it sets off on
+// its next flight.
+func (p *Parking) Leave(tailNum string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.byTail, tailNum)
+}
+
+// All returns the parked aircraft by tail number.
+func (p *Parking) All() []Parked {
+	p.mu.Lock()
+	parked := make([]Parked, 0, len(p.byTail))
+	for _, a := range p.byTail {
+		parked = append(parked, a)
+	}
+	p.mu.Unlock()
+
+	sort.Slice(parked, func(i, j int) bool { return parked[i].Record.TailNum < parked[j].Record.TailNum })
+	return parked
+}
+
+// Heartbeat is the record written now and then for a parked aircraft, so
+// consumers can tell one that is parked from one they have lost. Like
+// geofence events it is told apart from position reports by its "event"
+// field. Flight is the last flight the aircraft flew, and Since the time
+// of its last report.
+type Heartbeat struct {
+	Event     string        `json:"event"`
+	TailNum   string        `json:"plane"`
+	FlightID  string        `json:"flight"`
+	Timestamp int64         `json:"time"` // unix milliseconds
+	Status    domain.Status `json:"status"`
+	Airport   string        `json:"airport"`
+	Gate      string        `json:"gate,omitempty"`
+	Latitude  float64       `json:"lat"`
+	Longitude float64       `json:"long"`
+	Altitude  float64       `json:"alt"`
+	Since     int64         `json:"since"`          // unix milliseconds
+	Next      int64         `json:"next,omitempty"` // unix milliseconds of the next departure
+}
+
+// HeartbeatOf returns the heartbeat of a parked aircraft at now.
+func HeartbeatOf(p Parked, now time.Time) Heartbeat {
+	r := p.Record
+	h := Heartbeat{
+		Event:     "heartbeat",
+		TailNum:   r.TailNum,
+		FlightID:  r.FlightID,
+		Timestamp: now.UnixMilli(),
+		Status:    r.Status,
+		Airport:   p.Airport,
+		Gate:      p.Gate,
+		Latitude:  r.Latitude,
+		Longitude: r.Longitude,
+		Altitude:  r.Altitude,
+		Since:     r.Timestamp,
+	}
+	if !p.Next.IsZero() {
+		h.Next = p.Next.UnixMilli()
+	}
+	return h
+}
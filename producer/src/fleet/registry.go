@@ -0,0 +1,106 @@
+package fleet
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"plane-producer/src/flight"
+)
+
+var (
+	// ErrDuplicateTail is returned when an aircraft already flying is added
+	// again.
+	ErrDuplicateTail = errors.New("tail number is already flying")
+	// ErrDuplicateFlightID is returned when a flight ID is already in use.
+	ErrDuplicateFlightID = errors.New("flight id is already in use")
+)
+
+// Registry tracks the flights currently running, by tail number and by
+// flight ID, so that neither is ever shared by two of them. It is safe for
+// concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	byTail   map[string]*flight.Flight
+	byFlight map[string]*flight.Flight
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		byTail:   make(map[string]*flight.Flight),
+		byFlight: make(map[string]*flight.Flight),
+	}
+}
+
+// Add registers a flight, failing if its tail number or flight ID belongs
+// to one already registered.
+func (r *Registry) Add(f *flight.Flight) error {
+	tail, id := f.Plane().TailNum(), f.Plane().FlightID()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.byTail[tail]; ok {
+		return fmt.Errorf("%s: %w", tail, ErrDuplicateTail)
+	}
+	if _, ok := r.byFlight[id]; ok {
+		return fmt.Errorf("%s: %w", id, ErrDuplicateFlightID)
+	}
+	r.byTail[tail] = f
+	r.byFlight[id] = f
+	return nil
+}
+
+// Remove unregisters a flight, e.g. once it has arrived. Removing a flight
+// that is not registered does nothing.
+func (r *Registry) Remove(f *flight.Flight) {
+	tail, id := f.Plane().TailNum(), f.Plane().FlightID()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.byTail[tail] == f {
+		delete(r.byTail, tail)
+	}
+	if r.byFlight[id] == f {
+		delete(r.byFlight, id)
+	}
+}
+
+// ByTailNum returns the running flight of an aircraft.
+func (r *Registry) ByTailNum(tail string) (*flight.Flight, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	f, ok := r.byTail[tail]
+	return f, ok
+}
+
+// ByFlightID returns the running flight with the given ID.
+func (r *Registry) ByFlightID(id string) (*flight.Flight, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	f, ok := r.byFlight[id]
+	return f, ok
+}
+
+// Len returns the number of registered flights.
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.byFlight)
+}
+
+// Flights returns every registered flight, ordered by flight ID.
+func (r *Registry) Flights() []*flight.Flight {
+	r.mu.RLock()
+	flights := make([]*flight.Flight, 0, len(r.byFlight))
+	for _, f := range r.byFlight {
+		flights = append(flights, f)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(flights, func(i, j int) bool {
+		return flights[i].Plane().FlightID() < flights[j].Plane().FlightID()
+	})
+	return flights
+}
@@ -0,0 +1,83 @@
+package flight
+
+import (
+	"time"
+
+	"plane-producer/src/domain"
+)
+
+// Cancel calls off a flight that is still at the gate or taxiing out. On
+// its next step the aircraft stops, returns to Idle, gives up its gate and
+// any place in the queue for the runway, and the flight is done.
+func (f *Flight) Cancel() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch f.plane.Status() {
+	case domain.Idle, domain.Taxi:
+		if !f.arrived {
+			f.cancelled = true
+			return nil
+		}
+	}
+	return ErrTakenOff
+}
+
+// Release gives up the gate and runway the flight holds or is queued for,
+// as when it is taken out of the simulation.
+func (f *Flight) Release() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.withdrawRunway()
+	f.vacateGate()
+}
+
+// Cancelled reports whether the flight was cancelled.
+func (f *Flight) Cancelled() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.cancelled
+}
+
+// stop ends a cancelled flight where it is and returns its final report.
+func (f *Flight) stop(now time.Time) (domain.FlightRecord, error) {
+	m := f.plane.Motion()
+	m.Time = now
+	m.Airspeed, m.IndicatedAirspeed, m.GroundSpeed, m.VerticalSpeed = 0, 0, 0, 0
+	f.plane.Move(m)
+	f.plane.SetETA(time.Time{})
+	f.withdrawRunway()
+	f.vacateGate()
+	f.done = true
+	f.drawNoise()
+	if f.plane.Status() == domain.Idle {
+		return f.record(), nil
+	}
+	err := f.plane.Transition(domain.Idle)
+	return f.record(), err
+}
+
+// Cancellation is the record written when a flight is cancelled. Like
+// geofence events it is told apart from position reports by its "event"
+// field.
+type Cancellation struct {
+	Event       string `json:"event"`
+	TailNum     string `json:"plane"`
+	FlightID    string `json:"flight"`
+	Timestamp   int64  `json:"time"` // unix milliseconds
+	Origin      string `json:"orig"`
+	Destination string `json:"dest"`
+}
+
+// CancellationOf returns the cancellation record for a flight whose last
+// report is r.
+func CancellationOf(r domain.FlightRecord) Cancellation {
+	return Cancellation{
+		Event:       "cancelled",
+		TailNum:     r.TailNum,
+		FlightID:    r.FlightID,
+		Timestamp:   r.Timestamp,
+		Origin:      r.Origin,
+		Destination: r.Destination,
+	}
+}
@@ -0,0 +1,58 @@
+package flight
+
+import (
+	"testing"
+	"time"
+
+	"plane-producer/src/domain"
+	"plane-producer/src/ground"
+)
+
+// TestCancelFreesGateAndRunway cancels one flight at the gate and another
+// holding short of a busy runway, and checks both give up what they held.
+func TestCancelFreesGateAndRunway(t *testing.T) {
+	runways := ground.NewRunways()
+	gates := ground.NewGates(nil, time.Hour)
+	runways.Request("JFK", "N9UT")
+	parked := newTestAircraft(t, "N1UT", "JFK", "BOS", WithGates(gates), WithRunways(runways))
+	waiting := newTestAircraft(t, "N2UT", "JFK", "BOS", WithGates(gates), WithRunways(runways))
+	if err := parked.SetTakeOffClearance(false); err != nil {
+		t.Fatal(err)
+	}
+
+	c := newClock(t)
+	for waiting.plane.Status() != domain.Taxi || waiting.taxiRemaining > 0 {
+		if c.elapsed() > time.Hour {
+			t.Fatal("never reached the runway")
+		}
+		c.step(parked, waiting)
+	}
+	if _, queued := runways.Occupancy("JFK"); queued != 1 {
+		t.Fatalf("%d queued for the runway, want 1", queued)
+	}
+	if _, ok := gates.Lookup("N1UT"); !ok {
+		t.Fatal("held flight is not at a gate")
+	}
+
+	for _, f := range []*Flight{parked, waiting} {
+		if err := f.Cancel(); err != nil {
+			t.Fatal(err)
+		}
+		r, err := f.Step(c.now.Add(time.Second), time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Status != domain.Idle || r.Gate != "" {
+			t.Errorf("%s cancelled: %v at gate %q, want Idle at none", r.TailNum, r.Status, r.Gate)
+		}
+	}
+	if _, queued := runways.Occupancy("JFK"); queued != 0 {
+		t.Errorf("%d still queued for the runway", queued)
+	}
+	if a, ok := gates.Lookup("N1UT"); ok {
+		t.Errorf("cancelled flight still at gate %s", a.Gate)
+	}
+	if _, err := gates.Occupy("JFK", "N3UT", c.now); err != nil {
+		t.Error(err)
+	}
+}
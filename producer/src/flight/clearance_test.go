@@ -0,0 +1,66 @@
+package flight
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"plane-producer/src/domain"
+)
+
+// TestClearances withholds a flight's take-off and then its landing
+// clearance and checks it holds short of the runway and then in the hold,
+// reporting each on its records, until cleared.
+func TestClearances(t *testing.T) {
+	f := newTestFlight(t, "JFK", "BOS")
+	c := newClock(t)
+	until := func(status domain.Status) {
+		t.Helper()
+		for f.plane.Status() != status {
+			if c.elapsed() > 5*time.Hour {
+				t.Fatalf("never reached %v", status)
+			}
+			c.step(f)
+		}
+	}
+
+	until(domain.Taxi)
+	if err := f.SetTakeOffClearance(false); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3600; i++ {
+		if r := c.step(f); r.Status != domain.Taxi || !r.TakeOffHeld {
+			t.Fatalf("%v with take-off held %v, want held on the taxiway", r.Status, r.TakeOffHeld)
+		}
+	}
+	if err := f.SetTakeOffClearance(true); err != nil {
+		t.Fatal(err)
+	}
+	until(domain.TakeOff)
+	if err := f.SetTakeOffClearance(false); !errors.Is(err, ErrTakenOff) {
+		t.Errorf("withholding take-off clearance on the runway: %v, want %v", err, ErrTakenOff)
+	}
+
+	if err := f.SetLandingClearance(false); err != nil {
+		t.Fatal(err)
+	}
+	until(domain.AwaitingLanding)
+	for f.hold == nil {
+		c.step(f)
+	}
+	for i := 0; i < 3600; i++ {
+		if r := c.step(f); r.Status != domain.AwaitingLanding || !r.LandingHeld {
+			t.Fatalf("%v with landing held %v, want held in the hold", r.Status, r.LandingHeld)
+		}
+	}
+	if err := f.SetLandingClearance(true); err != nil {
+		t.Fatal(err)
+	}
+	until(domain.Landing)
+	if r := c.step(f); r.TakeOffHeld || r.LandingHeld {
+		t.Errorf("cleared flight reports take-off held %v and landing held %v", r.TakeOffHeld, r.LandingHeld)
+	}
+	if err := f.SetLandingClearance(false); !errors.Is(err, ErrLanding) {
+		t.Errorf("withholding landing clearance on final: %v, want %v", err, ErrLanding)
+	}
+}
@@ -0,0 +1,136 @@
+package flight
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"plane-producer/src/airports"
+	"plane-producer/src/domain"
+	"plane-producer/src/geo"
+)
+
+// Errors returned when a command no longer applies to a flight.
+var (
+	ErrTakenOff = errors.New("flight has already taken off")
+	ErrLanding  = errors.New("flight is already landing")
+)
+
+// maxSpeedFactor bounds an assigned speed above the profile's cruise
+// speed, roughly the margin to the maximum operating speed.
+const maxSpeedFactor = 1.1
+
+// SetTakeOffClearance grants or withholds a flight's take-off clearance.
+// Without it the aircraft waits at the gate, or holds short of the runway
+// if it has already pushed back, giving up its place in the queue for it.
+// Flights are cleared unless told otherwise.
+func (f *Flight) SetTakeOffClearance(granted bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if s := f.plane.Status(); s != domain.Idle && s != domain.Taxi || f.arrived {
+		return ErrTakenOff
+	}
+	f.takeOffHeld = !granted
+	if f.takeOffHeld && f.runway == f.origin.IATA {
+		f.withdrawRunway()
+	}
+	return nil
+}
+
+// SetLandingClearance grants or withholds a flight's landing clearance.
+// Without it the aircraft flies the hold at the final approach fix, giving
+// up its place in the queue for the runway. Flights are cleared unless told
+// otherwise.
+func (f *Flight) SetLandingClearance(granted bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.arrived || f.touchdown || f.plane.Status() == domain.Landing {
+		return ErrLanding
+	}
+	f.landingHeld = !granted
+	if f.landingHeld && f.runway == f.destination.IATA {
+		f.withdrawRunway()
+	}
+	return nil
+}
+
+// AssignSpeed sets the speed the aircraft cruises at in knots, which must
+// be between its approach speed and a little over its cruise speed. Zero
+// returns it to its profile's cruise speed.
+func (f *Flight) AssignSpeed(knots float64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p := f.profile
+	if knots != 0 && (knots < p.ApproachSpeed || knots > p.CruiseSpeed*maxSpeedFactor) {
+		return fmt.Errorf("a %s flies between %.0f and %.0f knots", p.Type, p.ApproachSpeed, p.CruiseSpeed*maxSpeedFactor)
+	}
+	f.speed = knots
+	return nil
+}
+
+// cruiseSpeed returns the speed to cruise at, as assigned or from the
+// profile.
+func (f *Flight) cruiseSpeed() float64 {
+	if f.speed > 0 {
+		return f.speed
+	}
+	return f.profile.CruiseSpeed
+}
+
+// Divert sends the flight to another airport. A flight still on the ground
+// is replanned from its origin, and one in the air from where it is, turning
+// onto the new route and flying it as planned from there: climbing if the
+// new cruise altitude is higher and descending once it reaches the new top
+// of descent. One already descending to land, or holding, goes back to the
+// cruise to do so.
+func (f *Flight) Divert(to airports.Airport) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.divert(to)
+}
+
+func (f *Flight) divert(to airports.Airport) error {
+	switch {
+	case f.arrived || f.touchdown || f.plane.Status() == domain.Landing:
+		return ErrLanding
+	case to.IATA == f.destination.IATA && to.ICAO == f.destination.ICAO:
+		return fmt.Errorf("flight %s is already bound for %s", f.plane.FlightID(), to.IATA)
+	}
+	if err := to.Position().Validate(); err != nil {
+		return fmt.Errorf("airport %s: %w", to.IATA, err)
+	}
+
+	from := f.origin.Position()
+	m := f.plane.Motion()
+	switch f.plane.Status() {
+	case domain.Idle, domain.Taxi:
+	default:
+		from = geo.Position{Latitude: geo.Degrees(m.Latitude), Longitude: geo.Degrees(m.Longitude)}
+	}
+
+	// A place in the queue to land at the old destination is given up;
+	// one to take off from the origin is kept.
+	if f.runway == f.destination.IATA {
+		f.withdrawRunway()
+	}
+	f.track = f.navigation.NewRoute(f.earth, from, to.Position())
+	f.flown, f.crossTrack = 0, 0
+	f.hold, f.missed = nil, false
+	f.destination = to
+	f.planCruise()
+	final := f.cruiseAltitude
+	if len(f.steps) > 0 {
+		final = f.steps[len(f.steps)-1].Altitude
+	}
+	f.topOfDescent, _ = f.profile.TopOfDescent(f.track, math.Max(final, m.Altitude), to.Elevation)
+	f.planDetours()
+	f.taxiInNm, f.taxiIn = f.taxi.TaxiIn(to.IATA, f.rng)
+	f.plane.Divert(to.IATA)
+	if f.plane.Status() == domain.AwaitingLanding {
+		return f.plane.Transition(domain.Cruising)
+	}
+	return nil
+}
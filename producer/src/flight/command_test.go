@@ -0,0 +1,113 @@
+package flight
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"plane-producer/src/airports"
+	"plane-producer/src/domain"
+)
+
+// TestDivertOnApproach diverts a flight already descending to land and
+// checks it goes back to the cruise for the new route, then lands there.
+func TestDivertOnApproach(t *testing.T) {
+	f := newTestFlight(t, "JFK", "BOS")
+	c := newClock(t)
+	for f.plane.Status() != domain.AwaitingLanding {
+		if c.elapsed() > 2*time.Hour {
+			t.Fatal("never descending")
+		}
+		c.step(f)
+	}
+	iad, ok := airports.Default().Lookup("IAD")
+	if !ok {
+		t.Fatal("no airport IAD")
+	}
+	if err := f.Divert(iad); err != nil {
+		t.Fatal(err)
+	}
+	if s := f.plane.Status(); s != domain.Cruising {
+		t.Fatalf("%v after diverting, want %v", s, domain.Cruising)
+	}
+
+	var fastest float64
+	var last domain.FlightRecord
+	for !f.Done() {
+		if c.elapsed() > 4*time.Hour {
+			t.Fatal("never arrived")
+		}
+		r := c.step(f)
+		fastest = max(fastest, r.Airspeed)
+		last = r
+	}
+	if fastest < f.profile.CruiseSpeed*0.9 {
+		t.Errorf("flew to IAD at no more than %.0f knots, want about %.0f", fastest, f.profile.CruiseSpeed)
+	}
+	if last.Destination != "IAD" {
+		t.Errorf("arrived at %s, want IAD", last.Destination)
+	}
+	if err := f.Divert(iad); !errors.Is(err, ErrLanding) {
+		t.Errorf("diverting after arriving: %v, want %v", err, ErrLanding)
+	}
+}
+
+// TestCommandsWhileStepping issues commands and reads reports from other
+// goroutines while the flight is stepped, as the control API and the
+// scheduler do; run with -race it checks the flight guards its state.
+func TestCommandsWhileStepping(t *testing.T) {
+	f := newTestFlight(t, "JFK", "BOS")
+	stepped := make(chan error)
+	go func() {
+		now := epoch
+		for !f.Done() {
+			if now.Sub(epoch) > 4*time.Hour {
+				stepped <- errors.New("never arrived")
+				return
+			}
+			now = now.Add(time.Second)
+			if _, err := f.Step(now, time.Second); err != nil {
+				stepped <- err
+				return
+			}
+		}
+		close(stepped)
+	}()
+
+	db := airports.Default()
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				f.SetTakeOffClearance(true)
+				f.SetLandingClearance(true)
+				f.AssignSpeed(0)
+				f.DeclareEmergency(domain.NoEmergency, db)
+				f.Report()
+				f.Milestones()
+				f.Encounters()
+				f.Cancelled()
+				f.Done()
+				f.Remaining()
+			}
+		}()
+	}
+	err := <-stepped
+	close(stop)
+	wg.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := f.Report(); r.Destination != "BOS" || r.Status != domain.Idle {
+		t.Errorf("ended %v at %s, want %v at BOS", r.Status, r.Destination, domain.Idle)
+	}
+}
@@ -0,0 +1,76 @@
+package flight
+
+import (
+	"errors"
+	"math"
+
+	"plane-producer/src/airports"
+	"plane-producer/src/domain"
+	"plane-producer/src/geo"
+)
+
+// alternates is how many of the airports nearest an aircraft in an
+// emergency are considered for the diversion.
+const alternates = 10
+
+// ErrOnGround is returned when an emergency is declared for a flight that
+// is not in the air.
+var ErrOnGround = errors.New("flight is on the ground")
+
+// DeclareEmergency declares e aboard a flight in the air, or cancels the
+// one declared with domain.NoEmergency. From its next step the aircraft
+// flies by the emergency's profile, descending to and staying below its
+// altitude at its speed. If the profile calls for it, the flight diverts
+// at once to the nearest airport in db unless it is already landing or
+// bound there. It returns the airport the flight is now bound for.
+func (f *Flight) DeclareEmergency(e domain.Emergency, db *airports.Database) (airports.Airport, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.airborne(f.plane.Motion()) {
+		return f.destination, ErrOnGround
+	}
+	f.plane.DeclareEmergency(e)
+	if !e.Profile().Divert || f.plane.Status() == domain.Landing {
+		return f.destination, nil
+	}
+	to, ok := f.nearest(db)
+	if !ok || to.IATA == f.destination.IATA && to.ICAO == f.destination.ICAO {
+		return f.destination, nil
+	}
+	if err := f.divert(to); err != nil {
+		return f.destination, err
+	}
+	return to, nil
+}
+
+// nearest returns the airport in db nearest the aircraft that it can be
+// routed to.
+func (f *Flight) nearest(db *airports.Database) (airports.Airport, bool) {
+	m := f.plane.Motion()
+	here := geo.Position{Latitude: geo.Degrees(m.Latitude), Longitude: geo.Degrees(m.Longitude)}
+	for _, a := range db.Nearest(here, alternates) {
+		if a.IATA != "" && a.Position().Validate() == nil {
+			return a.Airport, true
+		}
+	}
+	return airports.Airport{}, false
+}
+
+// ceiling returns altitude, or the declared emergency's maximum altitude if
+// that is lower.
+func (f *Flight) ceiling(altitude float64) float64 {
+	if top := f.plane.Emergency().Profile().MaxAltitude; top > 0 {
+		return math.Min(altitude, top)
+	}
+	return altitude
+}
+
+// emergencyDescentRate returns how fast in feet per minute to descend to
+// the declared emergency's maximum altitude.
+func (f *Flight) emergencyDescentRate() float64 {
+	if rate := f.plane.Emergency().Profile().DescentRate; rate > 0 {
+		return rate
+	}
+	return f.profile.DescentRate
+}
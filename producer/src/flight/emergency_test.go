@@ -0,0 +1,76 @@
+package flight
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"plane-producer/src/airports"
+	"plane-producer/src/domain"
+	"plane-producer/src/geo"
+)
+
+// TestDeclareEmergency declares a depressurization in cruise and checks the
+// aircraft descends no faster than the profile allows to below its ceiling,
+// stays there, and lands at the nearest airport instead of its destination.
+func TestDeclareEmergency(t *testing.T) {
+	db := airports.Default()
+	f := newTestFlight(t, "JFK", "LAX")
+	c := newClock(t)
+
+	if _, err := f.DeclareEmergency(domain.Depressurization, db); !errors.Is(err, ErrOnGround) {
+		t.Errorf("declaring at the gate: %v, want %v", err, ErrOnGround)
+	}
+	var r domain.FlightRecord
+	for r.Status != domain.Cruising {
+		if c.elapsed() > time.Hour {
+			t.Fatal("never cruising")
+		}
+		r = c.step(f)
+	}
+	for range 600 {
+		r = c.step(f)
+	}
+	here := geo.Position{Latitude: geo.Degrees(r.Latitude), Longitude: geo.Degrees(r.Longitude)}
+	var nearest airports.Nearby
+	for _, a := range db.Nearest(here, alternates) {
+		if a.IATA != "" {
+			nearest = a
+			break
+		}
+	}
+	to, err := f.DeclareEmergency(domain.Depressurization, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if to.IATA != nearest.IATA || to.IATA == "LAX" {
+		t.Fatalf("diverted to %s, want the nearest airport %s", to.IATA, nearest.IATA)
+	}
+
+	profile := domain.Depressurization.Profile()
+	below := false
+	for !f.Done() {
+		if c.elapsed() > 3*time.Hour {
+			t.Fatal("never arrived")
+		}
+		last := r
+		r = c.step(f)
+		if r.Emergency != domain.Depressurization || r.Squawk != domain.SquawkEmergency {
+			t.Fatalf("reported %v squawking %v", r.Emergency, r.Squawk)
+		}
+		if rate := (last.Altitude - r.Altitude) * 60; rate > profile.DescentRate+1 {
+			t.Fatalf("descending at %.0f ft/min", rate)
+		}
+		if r.Altitude <= profile.MaxAltitude {
+			below = true
+		} else if below {
+			t.Fatalf("climbed back to %.0f ft", r.Altitude)
+		}
+	}
+	if !below {
+		t.Error("never descended below the emergency ceiling")
+	}
+	if r.Destination != to.IATA {
+		t.Errorf("arrived at %s, want %s", r.Destination, to.IATA)
+	}
+}
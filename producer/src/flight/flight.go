@@ -0,0 +1,598 @@
+package flight
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"plane-producer/src/airports"
+	"plane-producer/src/domain"
+	"plane-producer/src/geo"
+	"plane-producer/src/ground"
+	"plane-producer/src/performance"
+	"plane-producer/src/units"
+	"plane-producer/src/weather"
+)
+
+// finalApproachNm is how far out an aircraft on descent is cleared to land.
+const finalApproachNm = 10
+
+// Flight flies one aircraft from gate to gate along a planned route between
+// two airports, the great circle unless WithNavigation says otherwise: taxi
+// out, take off and climb, cruise with any step
+// climbs, descend from top of descent, land and taxi in. Aloft it corrects
+// for the wind, slows down in moderate turbulence and flies around severe
+// weather cells. It implements sim.Stepper.
+type Flight struct {
+	plane   *domain.PlaneDetails
+	profile performance.Profile
+
+	origin      airports.Airport
+	destination airports.Airport
+	track       geo.Route
+	navigation  geo.Navigation
+	earth       geo.EarthModel
+
+	taxi ground.TaxiModel
+	rng  *rand.Rand
+
+	noise    Noise
+	noiseRng *rand.Rand
+	offset   offset
+
+	weather    weather.Weather
+	detours    []detour
+	cell       weather.Cell
+	encounters []weather.Cell
+	// drift is the wind correction angle last flown, the difference
+	// between the heading and the track over the ground.
+	drift geo.Degrees
+
+	// runway is the airport whose runway the aircraft holds, or is queued
+	// for if not onRunway.
+	runways  *ground.Runways
+	runway   string
+	onRunway bool
+	// hold is the racetrack flown at the final approach fix while waiting
+	// to land, and holdNm how far round it the aircraft has flown.
+	hold   *geo.Hold
+	holdNm float64
+	// gate is where the aircraft is parked at the gates, if anywhere.
+	gates *ground.Gates
+	gate  string
+	// goArounds is the chance of each approach ending in a go-around, and
+	// goAroundNm how far from the runway this one will, if above zero.
+	// missed is set while flying the missed approach after one, climbing
+	// to missedAltitude.
+	goArounds      float64
+	goAroundNm     float64
+	missed         bool
+	missedAltitude float64
+
+	// assignedAltitude is the cruise altitude set by WithCruiseAltitude,
+	// zero to cruise as the profile plans.
+	assignedAltitude float64
+	cruiseAltitude   float64
+	steps            []performance.Step
+	topOfDescent     float64
+	descentFrom      float64
+
+	squawk         domain.Squawk
+	reportInterval time.Duration
+
+	taxiOut, taxiIn          time.Duration
+	taxiOutNm, taxiInNm      float64
+	taxiRemaining            time.Duration
+	flown, crossTrack        float64
+	arrived, touchdown, done bool
+
+	// mu guards the flight against Cancel and the other commands, which
+	// are called from outside the scheduler.
+	mu          sync.Mutex
+	cancelled   bool
+	takeOffHeld bool
+	landingHeld bool
+	speed       float64
+	milestones  []Milestone
+	phases      phaseTimes
+}
+
+// Option configures a Flight.
+type Option func(*options)
+
+type options struct {
+	profile        performance.Profile
+	cruiseAltitude float64
+	reportInterval time.Duration
+	navigation     geo.Navigation
+	earth          geo.EarthModel
+	noise          Noise
+	noiseRng       *rand.Rand
+	weather        weather.Weather
+	runways        *ground.Runways
+	goArounds      float64
+	gates          *ground.Gates
+}
+
+// WithPerformanceProfile flies the aircraft with profile p. Every flight
+// needs one.
+func WithPerformanceProfile(p performance.Profile) Option {
+	return func(o *options) { o.profile = p }
+}
+
+// WithCruiseAltitude cruises at altitude feet, whatever the route length,
+// instead of at the profile's levels with their step climbs. It must be no
+// higher than the profile's service ceiling; zero leaves it to the profile.
+func WithCruiseAltitude(altitude float64) Option {
+	return func(o *options) { o.cruiseAltitude = altitude }
+}
+
+// WithReportInterval writes the flight's reports every d, in place of the
+// reporting policy's interval for it. It must not be negative; zero leaves
+// the interval to the policy.
+func WithReportInterval(d time.Duration) Option {
+	return func(o *options) { o.reportInterval = d }
+}
+
+// WithNavigation plans the route with the given navigation mode.
+func WithNavigation(n geo.Navigation) Option {
+	return func(o *options) { o.navigation = n }
+}
+
+// WithEarthModel plans the route on the given model of the Earth.
+func WithEarthModel(m geo.EarthModel) Option {
+	return func(o *options) { o.earth = m }
+}
+
+// New prepares a flight for plane, parked at the origin. Taxi times are
+// drawn from taxi using rng. The aircraft's performance profile is given
+// by WithPerformanceProfile.
+func New(plane *domain.PlaneDetails, origin, destination airports.Airport, taxi ground.TaxiModel, rng *rand.Rand, opts ...Option) (*Flight, error) {
+	f, err := plan(plane, origin, destination, taxi, rng, opts)
+	if err != nil {
+		return nil, err
+	}
+	f.takeGate(plane.Timestamp())
+	plane.Move(domain.Motion{
+		Time:      plane.Timestamp(),
+		Latitude:  origin.Latitude,
+		Longitude: origin.Longitude,
+		Altitude:  origin.Elevation,
+		Heading:   float64(f.track.CourseAt(0)),
+	})
+	return f, nil
+}
+
+// plan prepares a flight for plane from origin to destination, wherever the
+// aircraft is.
+func plan(plane *domain.PlaneDetails, origin, destination airports.Airport, taxi ground.TaxiModel, rng *rand.Rand, opts []Option) (*Flight, error) {
+	o := options{navigation: geo.GreatCircle, earth: geo.Sphere}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	profile := o.profile
+	if err := profile.Validate(); err != nil {
+		return nil, fmt.Errorf("flight %s: %w", plane.FlightID(), err)
+	}
+	if o.cruiseAltitude < 0 || o.cruiseAltitude > profile.ServiceCeiling {
+		return nil, fmt.Errorf("flight %s: cruise altitude %.0fft must be between 0 and the %s's %.0fft service ceiling", plane.FlightID(), o.cruiseAltitude, profile.Type, profile.ServiceCeiling)
+	}
+	if o.reportInterval < 0 {
+		return nil, fmt.Errorf("flight %s: report interval %v is negative", plane.FlightID(), o.reportInterval)
+	}
+	if origin.IATA == destination.IATA && origin.ICAO == destination.ICAO {
+		return nil, fmt.Errorf("flight %s: origin and destination are the same", plane.FlightID())
+	}
+	for _, a := range []airports.Airport{origin, destination} {
+		if err := a.Position().Validate(); err != nil {
+			return nil, fmt.Errorf("flight %s: airport %s: %w", plane.FlightID(), a.IATA, err)
+		}
+	}
+
+	track := o.navigation.NewRoute(o.earth, origin.Position(), destination.Position())
+
+	f := &Flight{
+		plane:       plane,
+		profile:     profile,
+		origin:      origin,
+		destination: destination,
+		track:       track,
+		navigation:  o.navigation,
+		earth:       o.earth,
+		taxi:        taxi,
+		rng:         rng,
+		noise:       o.noise,
+		noiseRng:    o.noiseRng,
+		weather:     o.weather,
+		runways:     o.runways,
+		goArounds:   o.goArounds,
+		gates:       o.gates,
+
+		assignedAltitude: o.cruiseAltitude,
+		reportInterval:   o.reportInterval,
+	}
+	f.planDetours()
+
+	f.planCruise()
+	final := f.cruiseAltitude
+	if len(f.steps) > 0 {
+		final = f.steps[len(f.steps)-1].Altitude
+	}
+	f.topOfDescent, _ = profile.TopOfDescent(track, final, destination.Elevation)
+
+	f.taxiOutNm, f.taxiOut = taxi.TaxiOut(origin.IATA, rng)
+	f.taxiInNm, f.taxiIn = taxi.TaxiIn(destination.IATA, rng)
+	f.squawk = domain.NewSquawk(rng)
+	plane.OnTransition(f.transitioned)
+	return f, nil
+}
+
+// planCruise plans the cruise along the route: at the assigned altitude if
+// there is one, or at the profile's levels for the route.
+func (f *Flight) planCruise() {
+	if f.assignedAltitude > 0 {
+		f.cruiseAltitude, f.steps = f.assignedAltitude, nil
+		return
+	}
+	f.cruiseAltitude, f.steps = f.profile.CruisePlan(f.track.Length(), f.track.CourseAt(0))
+}
+
+// ReportInterval returns how often the flight's reports are written, zero
+// if that is up to the reporting policy.
+func (f *Flight) ReportInterval() time.Duration {
+	return f.reportInterval
+}
+
+// Plane returns the aircraft being flown.
+func (f *Flight) Plane() *domain.PlaneDetails {
+	return f.plane
+}
+
+// Done reports whether the aircraft has arrived at the destination gate.
+func (f *Flight) Done() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.done
+}
+
+// Report returns the aircraft's current position report.
+func (f *Flight) Report() domain.FlightRecord {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.record()
+}
+
+// Remaining returns the distance left to fly in nautical miles.
+func (f *Flight) Remaining() float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.remaining()
+}
+
+func (f *Flight) remaining() float64 {
+	return math.Max(f.track.Length()-f.flown, 0)
+}
+
+// Step advances the flight by dt to the simulated time now and returns its
+// position report.
+func (f *Flight) Step(now time.Time, dt time.Duration) (domain.FlightRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.cancelled {
+		return f.stop(now)
+	}
+
+	m := f.plane.Motion()
+	m.Time = now
+	p := f.profile
+	status, airborne := f.plane.Status(), f.airborne(m)
+	f.phases.add(now, status, airborne, f.arrived, f.hold != nil && !f.missed, dt)
+
+	var err error
+	switch f.plane.Status() {
+	case domain.Idle:
+		if f.arrived {
+			f.done = true
+			break
+		}
+		if f.takeOffHeld || !f.leaveGate(now) {
+			break
+		}
+		// Clearance comes with the transponder code.
+		f.plane.AssignSquawk(f.squawk)
+		f.taxiRemaining = f.taxiOut
+		err = f.plane.Transition(domain.Taxi)
+
+	case domain.Taxi:
+		distance, total := f.taxiOutNm, f.taxiOut
+		if f.arrived {
+			distance, total = f.taxiInNm, f.taxiIn
+		}
+		m.Airspeed = ground.TaxiSpeed(distance, total)
+		m.GroundSpeed = m.Airspeed
+		m.VerticalSpeed = 0
+
+		f.taxiRemaining -= dt
+		if f.taxiRemaining > 0 {
+			break
+		}
+		switch {
+		case f.arrived && !f.parkAtGate(now):
+			// Waiting on the apron for a gate to come free.
+			m.Airspeed, m.GroundSpeed = 0, 0
+		case f.arrived:
+			m.Airspeed, m.GroundSpeed = 0, 0
+			err = f.plane.Transition(domain.Idle)
+		case !f.takeOffHeld && f.requestRunway(f.origin.IATA):
+			err = f.plane.Transition(domain.TakeOff)
+		default:
+			// Holding short until cleared and the runway is free.
+			m.Airspeed, m.GroundSpeed = 0, 0
+		}
+
+	case domain.TakeOff:
+		m.Airspeed = p.RampSpeed(m.Airspeed, p.ClimbSpeed, dt)
+		if m.Airspeed >= p.TakeOffSpeed {
+			m.VerticalSpeed = p.RampVerticalSpeed(m.VerticalSpeed, units.FeetPerMinute(p.ClimbRate), dt)
+			m.Altitude += m.VerticalSpeed.Over(dt).Feet()
+		}
+		f.fly(&m, dt)
+		if f.airborne(m) {
+			f.leaveRunway()
+		}
+		if top := f.ceiling(f.cruiseAltitude); m.Altitude >= top {
+			m.Altitude, m.VerticalSpeed = top, 0
+			err = f.plane.Transition(domain.Cruising)
+		} else if f.flown >= f.topOfDescent {
+			err = f.plane.Transition(domain.Cruising)
+		}
+
+	case domain.Cruising:
+		speed := f.cruiseSpeed() * f.speedFactor() * f.plane.Emergency().Profile().SpeedFactor
+		m.Airspeed = p.RampSpeed(m.Airspeed, speed, dt)
+		target := f.cruiseAltitude
+		for _, s := range f.steps {
+			if f.flown >= s.AtNm {
+				target = s.Altitude
+			}
+		}
+		target = f.ceiling(target)
+		switch {
+		case m.Altitude < target:
+			m.VerticalSpeed = p.RampVerticalSpeed(m.VerticalSpeed, units.FeetPerMinute(p.ClimbRate), dt)
+			m.Altitude = math.Min(m.Altitude+m.VerticalSpeed.Over(dt).Feet(), target)
+		case m.Altitude > target:
+			m.VerticalSpeed = p.RampVerticalSpeed(m.VerticalSpeed, units.FeetPerMinute(-f.emergencyDescentRate()), dt)
+			m.Altitude = math.Max(m.Altitude+m.VerticalSpeed.Over(dt).Feet(), target)
+		default:
+			m.VerticalSpeed = 0
+		}
+		f.fly(&m, dt)
+		if f.flown >= f.topOfDescent {
+			f.descentFrom = m.Altitude
+			err = f.plane.Transition(domain.AwaitingLanding)
+		}
+
+	case domain.AwaitingLanding:
+		m.Airspeed = p.RampSpeed(m.Airspeed, p.ApproachSpeed, dt)
+		var atFix bool
+		switch {
+		case f.missed:
+			atFix = f.flyMissedApproach(&m, dt)
+		case f.hold != nil:
+			atFix = f.flyHold(&m, dt)
+		default:
+			f.fly(&m, dt)
+			f.descend(&m, dt)
+			atFix = f.remaining() <= finalApproachNm
+		}
+		if !atFix {
+			break
+		}
+		if !f.landingHeld && f.requestRunway(f.destination.IATA) {
+			f.hold = nil
+			f.planApproach()
+			err = f.plane.Transition(domain.Landing)
+			break
+		}
+		// Holding at the final approach fix until cleared to land and the
+		// runway is free, asking again each time round.
+		if f.hold == nil {
+			f.enterHold(m)
+		}
+
+	case domain.Landing:
+		if !f.touchdown {
+			m.Airspeed = p.RampSpeed(m.Airspeed, p.ApproachSpeed, dt)
+			f.fly(&m, dt)
+			f.descend(&m, dt)
+			if f.goAroundNm > 0 && f.remaining() <= f.goAroundNm {
+				err = f.goAround(m)
+				break
+			}
+			f.touchdown = f.remaining() <= 0
+			break
+		}
+		m.Airspeed = p.RampSpeed(m.Airspeed, p.TaxiSpeed, dt)
+		m.GroundSpeed = m.Airspeed
+		m.Altitude, m.VerticalSpeed = f.destination.Elevation, 0
+		if m.Airspeed <= p.TaxiSpeed {
+			f.leaveRunway()
+			f.arrived = true
+			f.taxiRemaining = f.taxiIn
+			err = f.plane.Transition(domain.Taxi)
+		}
+	}
+
+	m.IndicatedAirspeed = performance.IndicatedAirspeed(m.Airspeed, m.Altitude)
+	if f.airborne(m) {
+		m.Attitude = flightPathAngle(m)
+	} else {
+		m.Attitude, m.Bank, m.RateOfTurn = 0, 0, 0
+	}
+	f.plane.Move(m)
+	f.encounter(m)
+	f.trackDeviation(m)
+	f.plane.SetETA(f.eta(m))
+	f.passed(airborne, m)
+	f.drawNoise()
+	if err != nil {
+		return f.record(), fmt.Errorf("flight %s: %w", f.plane.FlightID(), err)
+	}
+	return f.record(), nil
+}
+
+// fly moves the aircraft along the track at its airspeed. In the air the
+// aircraft turns onto the route, or onto a detour beside it, with limited
+// bank, so it drifts off it while the course changes; crossTrack keeps how
+// far, and the position is offset from the route by that much. Aloft it
+// heads into the wind to make good the track it steers, at the ground speed
+// the wind leaves it; on the ground ground speed equals airspeed.
+func (f *Flight) fly(m *domain.Motion, dt time.Duration) {
+	course := f.track.CourseAt(f.flown)
+	track := course.Normalized()
+	m.GroundSpeed = m.Airspeed
+	if f.airborne(*m) {
+		track = f.steer(m, geo.Degrees(m.Heading)-f.drift, course, dt)
+		var heading geo.Degrees
+		heading, m.GroundSpeed = f.weather.Wind.Correct(track, m.Airspeed)
+		f.drift = (heading - track).Signed()
+		m.Heading = float64(heading)
+	} else {
+		f.drift = 0
+		m.Heading = float64(track)
+	}
+
+	off := (track - course).Radians()
+	d := m.GroundSpeed * dt.Hours()
+	f.flown = math.Min(math.Max(f.flown+d*math.Cos(float64(off)), 0), f.track.Length())
+	f.crossTrack += d * math.Sin(float64(off))
+	if f.flown >= f.track.Length() {
+		f.crossTrack = 0
+	}
+
+	pos := f.track.PositionAt(f.flown)
+	if f.crossTrack != 0 {
+		pos = geo.Destination(pos, f.track.CourseAt(f.flown)+90, f.crossTrack)
+	}
+	m.Latitude, m.Longitude = float64(pos.Latitude), float64(pos.Longitude)
+}
+
+// steer banks from the track being flown towards the one that rejoins the
+// route, or the detour beside it, about a minute ahead, turning no faster
+// than the aircraft can and cutting in at no more than 45 degrees. It
+// returns the new track.
+func (f *Flight) steer(m *domain.Motion, track, course geo.Degrees, dt time.Duration) geo.Degrees {
+	lookahead := math.Max(m.GroundSpeed/60, 1)
+	intercept := math.Max(-math.Pi/4, math.Min(math.Atan((f.crossTrack-f.offsetAt(f.flown))/lookahead), math.Pi/4))
+	desired := course - geo.Radians(intercept).Degrees()
+
+	bank, turned := performance.Turn(m.Bank, float64((desired - track).Signed()), m.Airspeed, dt)
+	m.Bank, m.RateOfTurn = bank, turned/dt.Seconds()
+	return (track + geo.Degrees(turned)).Normalized()
+}
+
+// airborne reports whether the aircraft is off the ground.
+func (f *Flight) airborne(m domain.Motion) bool {
+	switch f.plane.Status() {
+	case domain.Idle, domain.Taxi:
+		return false
+	case domain.TakeOff:
+		return m.Altitude > f.origin.Elevation
+	case domain.Landing:
+		return !f.touchdown
+	}
+	return true
+}
+
+// flightPathAngle returns the climb or descent angle in degrees, which
+// stands in for pitch attitude.
+func flightPathAngle(m domain.Motion) float64 {
+	if m.GroundSpeed <= 0 {
+		return 0
+	}
+	feetPerMinute := m.GroundSpeed * 6076.12 / 60
+	return float64(geo.Radians(math.Atan2(m.VerticalSpeed.FeetPerMinute(), feetPerMinute)).Degrees())
+}
+
+// trackDeviation records how far the aircraft is off the planned route.
+// Deviation is only meaningful in the air; on the ground it is cleared.
+func (f *Flight) trackDeviation(m domain.Motion) {
+	switch f.plane.Status() {
+	case domain.Idle, domain.Taxi:
+		f.plane.SetDeviation(0, 0)
+		return
+	}
+	course := f.track.CourseAt(f.flown)
+	f.plane.SetDeviation(float64((geo.Degrees(m.Heading) - f.drift - course).Signed()), f.crossTrack)
+}
+
+// eta estimates when the aircraft will reach the destination gate: the
+// rest of the climb at climb speed, the cruise at cruise speed and the
+// descent slowing to approach speed, each corrected by the wind implied by
+// the current ground speed, plus the taxi time still to go.
+func (f *Flight) eta(m domain.Motion) time.Time {
+	p := f.profile
+	switch {
+	case f.arrived:
+		return m.Time.Add(max(f.taxiRemaining, 0))
+	case f.touchdown:
+		return m.Time.Add(f.taxiIn)
+	}
+
+	status := f.plane.Status()
+	ground := f.taxiIn
+	switch status {
+	case domain.Idle:
+		ground += f.taxiOut
+	case domain.Taxi:
+		ground += f.taxiRemaining
+	}
+
+	wind := m.GroundSpeed - m.Airspeed
+	remaining, speed := f.remainingNm(), m.GroundSpeed
+	var hours float64
+	if status != domain.AwaitingLanding && status != domain.Landing {
+		toDescent := math.Min(math.Max(f.topOfDescent-f.flown, 0), remaining)
+		var climbNm float64
+		if status != domain.Cruising {
+			climbMinutes := math.Max(f.cruiseAltitude-m.Altitude, 0) / p.ClimbRate
+			climbNm = math.Min((p.ClimbSpeed+wind)*climbMinutes/60, toDescent)
+		}
+		speed = f.cruiseSpeed() + wind
+		hours = climbNm/(p.ClimbSpeed+wind) + (toDescent-climbNm)/speed
+		remaining -= toDescent
+	}
+	hours += f.approachHours(speed, p.ApproachSpeed+wind, remaining)
+	return m.Time.Add(ground + time.Duration(hours*float64(time.Hour)))
+}
+
+// approachHours estimates how long it takes to fly distanceNm while slowing
+// from ground speed from to approach.
+func (f *Flight) approachHours(from, approach, distanceNm float64) float64 {
+	if from <= approach {
+		return distanceNm / approach
+	}
+	slowing := (from - approach) / (f.profile.Deceleration * 3600)
+	slowingNm := (from + approach) / 2 * slowing
+	if slowingNm >= distanceNm {
+		return distanceNm / ((from + approach) / 2)
+	}
+	return slowing + (distanceNm-slowingNm)/approach
+}
+
+// descend sets the altitude for the constant-gradient descent from where
+// the descent began to the destination field, or for the descent below a
+// declared emergency's maximum altitude if that is lower.
+func (f *Flight) descend(m *domain.Motion, dt time.Duration) {
+	target := f.profile.DescentAltitude(f.remaining(), f.descentFrom, f.destination.Elevation)
+	target = math.Min(target, m.Altitude)
+	if top := f.ceiling(target); top < target {
+		target = math.Max(top, m.Altitude-f.emergencyDescentRate()*dt.Minutes())
+	}
+	m.VerticalSpeed = units.FeetPerMinute((target - m.Altitude) / dt.Minutes())
+	m.Altitude = target
+}
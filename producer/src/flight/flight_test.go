@@ -0,0 +1,196 @@
+package flight
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"plane-producer/src/airports"
+	"plane-producer/src/domain"
+	"plane-producer/src/geo"
+	"plane-producer/src/ground"
+	"plane-producer/src/performance"
+)
+
+var epoch = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// newTestFlight prepares a flight between two bundled airports.
+func newTestFlight(t testing.TB, from, to string, opts ...Option) *Flight {
+	t.Helper()
+	return newTestAircraft(t, "N1UT", from, to, opts...)
+}
+
+// newTestAircraft is newTestFlight for the aircraft with the given tail
+// number.
+func newTestAircraft(t testing.TB, tailNum, from, to string, opts ...Option) *Flight {
+	t.Helper()
+	db := airports.Default()
+	origin, ok := db.Lookup(from)
+	if !ok {
+		t.Fatalf("no airport %s", from)
+	}
+	destination, ok := db.Lookup(to)
+	if !ok {
+		t.Fatalf("no airport %s", to)
+	}
+	plane, err := domain.NewPlaneDetails(tailNum, "UT"+tailNum, from, to, domain.WithTimestamp(epoch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := New(plane, origin, destination, ground.DefaultTaxiModel, rand.New(rand.NewSource(1)), append([]Option{WithPerformanceProfile(performance.Defaults["A320"])}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// clock steps flights a second at a time from epoch.
+type clock struct {
+	t   testing.TB
+	now time.Time
+}
+
+func newClock(t testing.TB) *clock {
+	return &clock{t: t, now: epoch}
+}
+
+// step moves the clock on a second and steps each flight to it, returning
+// the last one's report.
+func (c *clock) step(fs ...*Flight) domain.FlightRecord {
+	c.t.Helper()
+	c.now = c.now.Add(time.Second)
+	var r domain.FlightRecord
+	for _, f := range fs {
+		var err error
+		if r, err = f.Step(c.now, time.Second); err != nil {
+			c.t.Fatal(err)
+		}
+	}
+	return r
+}
+
+// elapsed returns the time stepped since epoch.
+func (c *clock) elapsed() time.Duration {
+	return c.now.Sub(epoch)
+}
+
+// fly steps f a second at a time until it is done, returning its reports.
+func fly(t testing.TB, f *Flight) []domain.FlightRecord {
+	t.Helper()
+	var records []domain.FlightRecord
+	c := newClock(t)
+	for !f.Done() {
+		if c.elapsed() > 48*time.Hour {
+			t.Fatal("flight did not finish in two days")
+		}
+		records = append(records, c.step(f))
+	}
+	return records
+}
+
+// TestFlownPathFollowsRoute is the regression test for flying the planned
+// great circle: once established on course after take-off the aircraft
+// stays on the route, and it arrives at the destination.
+func TestFlownPathFollowsRoute(t *testing.T) {
+	for _, route := range [][2]string{{"JFK", "LHR"}, {"LAX", "JFK"}, {"SEA", "ICN"}} {
+		t.Run(route[0]+"-"+route[1], func(t *testing.T) {
+			f := newTestFlight(t, route[0], route[1])
+			origin, destination := f.origin.Position(), f.destination.Position()
+			track := geo.NewTrack(origin, destination)
+
+			var worst float64
+			for _, r := range fly(t, f) {
+				if r.Status != domain.Cruising {
+					continue
+				}
+				p := geo.Position{Latitude: geo.Degrees(r.Latitude), Longitude: geo.Degrees(r.Longitude)}
+				if _, xt := track.Deviation(p, geo.Degrees(r.Heading)); math.Abs(xt) > worst {
+					worst = math.Abs(xt)
+				}
+			}
+			if worst > 0.5 {
+				t.Errorf("cruised up to %.2f nm off the great circle", worst)
+			}
+
+			last := f.Report()
+			at := geo.Position{Latitude: geo.Degrees(last.Latitude), Longitude: geo.Degrees(last.Longitude)}
+			if d := geo.Distance(at, destination); d > 0.01 {
+				t.Errorf("finished %.3f nm from %s", d, route[1])
+			}
+		})
+	}
+}
+
+// TestCruiseAltitude flies a long route at an assigned altitude, which
+// replaces the profile's levels and step climbs.
+func TestCruiseAltitude(t *testing.T) {
+	f := newTestFlight(t, "JFK", "LHR", WithCruiseAltitude(24000))
+	var highest float64
+	for _, r := range fly(t, f) {
+		highest = math.Max(highest, r.Altitude)
+	}
+	if highest != 24000 {
+		t.Errorf("cruised up to %.0fft, want 24000ft", highest)
+	}
+}
+
+// TestNewInvalid rejects flights that could not be flown as configured.
+func TestNewInvalid(t *testing.T) {
+	db := airports.Default()
+	jfk, _ := db.Lookup("JFK")
+	bos, _ := db.Lookup("BOS")
+	a320 := WithPerformanceProfile(performance.Defaults["A320"])
+	for name, opts := range map[string][]Option{
+		"no profile":        nil,
+		"above ceiling":     {a320, WithCruiseAltitude(45000)},
+		"negative altitude": {a320, WithCruiseAltitude(-1)},
+		"negative interval": {a320, WithReportInterval(-time.Second)},
+	} {
+		plane, err := domain.NewPlaneDetails("N1UT", "UT1", "JFK", "BOS", domain.WithTimestamp(epoch))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := New(plane, jfk, bos, ground.DefaultTaxiModel, rand.New(rand.NewSource(1)), opts...); err == nil {
+			t.Errorf("%s: flight created", name)
+		}
+	}
+
+	f := newTestFlight(t, "JFK", "BOS", WithReportInterval(time.Minute))
+	if got := f.ReportInterval(); got != time.Minute {
+		t.Errorf("report interval %v, want 1m", got)
+	}
+}
+
+// TestStepDeterministic drives two identical flights step by step, with
+// uneven steps, and checks they report the same, each at the time it was
+// stepped to.
+func TestStepDeterministic(t *testing.T) {
+	a, b := newTestFlight(t, "JFK", "BOS"), newTestFlight(t, "JFK", "BOS")
+	dts := []time.Duration{time.Second, 5 * time.Second, 30 * time.Second}
+	now := epoch
+	for i := 0; !a.Done(); i++ {
+		if i > 24*3600 {
+			t.Fatal("flight did not finish")
+		}
+		dt := dts[i%len(dts)]
+		now = now.Add(dt)
+		ra, err := a.Step(now, dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rb, err := b.Step(now, dt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ra != rb {
+			t.Fatalf("step %d: reports differ:\n%+v\n%+v", i, ra, rb)
+		}
+		if ra.Timestamp != now.UnixMilli() {
+			t.Fatalf("step %d: reported at %d, want %d", i, ra.Timestamp, now.UnixMilli())
+		}
+	}
+	if !b.Done() {
+		t.Error("second flight not done with the first")
+	}
+}
@@ -0,0 +1,76 @@
+package flight
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"plane-producer/src/ground"
+)
+
+// WithGates parks the aircraft at a gate of g: the one it is already at,
+// or a free one, at the origin, and a free one on arrival, waiting on the
+// apron if there is none. It pushes back once the gate's turnaround is
+// done.
+func WithGates(g *ground.Gates) Option {
+	return func(o *options) { o.gates = g }
+}
+
+// takeGate finds the aircraft's gate at the origin before it departs.
+// Without a free one, or if it is still noted at a gate elsewhere, it
+// leaves from a remote stand.
+func (f *Flight) takeGate(now time.Time) {
+	if f.gates == nil {
+		return
+	}
+	a, ok := f.gates.Lookup(f.plane.TailNum())
+	if ok && a.Airport != strings.ToUpper(f.origin.IATA) {
+		return
+	}
+	if !ok {
+		var err error
+		if a, err = f.gates.Occupy(f.origin.IATA, f.plane.TailNum(), now); err != nil {
+			return
+		}
+	}
+	f.gate = a.Gate
+}
+
+// leaveGate frees the gate as the aircraft pushes back, reporting false
+// while it is still being turned around.
+func (f *Flight) leaveGate(now time.Time) bool {
+	if f.gate == "" {
+		return true
+	}
+	if a, ok := f.gates.Lookup(f.plane.TailNum()); ok && now.Before(a.ReadyAt) {
+		return false
+	}
+	f.gates.Release(f.plane.TailNum(), now)
+	f.gate = ""
+	return true
+}
+
+// vacateGate frees the aircraft's gate whether or not it has been turned
+// around.
+func (f *Flight) vacateGate() {
+	if f.gate == "" {
+		return
+	}
+	f.gates.Vacate(f.plane.TailNum())
+	f.gate = ""
+}
+
+// parkAtGate asks for a gate at the destination once the aircraft has
+// taxied in, reporting false while it waits for one to come free. One
+// that cannot be given a gate at all parks on a remote stand.
+func (f *Flight) parkAtGate(now time.Time) bool {
+	if f.gates == nil || f.gate != "" {
+		return true
+	}
+	a, err := f.gates.Assign(f.destination.IATA, f.plane.TailNum(), now)
+	if err != nil {
+		return !errors.Is(err, ground.ErrNoGate)
+	}
+	f.gate = a.Gate
+	return true
+}
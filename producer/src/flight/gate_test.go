@@ -0,0 +1,74 @@
+package flight
+
+import (
+	"testing"
+	"time"
+
+	"plane-producer/src/domain"
+	"plane-producer/src/ground"
+)
+
+// TestGates flies two aircraft into an airport with a single gate and
+// checks the second waits on the apron until the first has turned round
+// and pushed back on its next flight.
+func TestGates(t *testing.T) {
+	const turnaround = 30 * time.Minute
+	gates := ground.NewGates(map[string][]string{"BOS": {"A1"}}, turnaround)
+	first := newTestAircraft(t, "N1UT", "JFK", "BOS", WithGates(gates))
+	second := newTestAircraft(t, "N2UT", "JFK", "BOS", WithGates(gates))
+	if r := first.Report(); r.Gate != "G1" {
+		t.Errorf("first aircraft starts out at gate %q, want G1", r.Gate)
+	}
+
+	c := newClock(t)
+	for !first.Done() {
+		if c.elapsed() > 3*time.Hour {
+			t.Fatal("first aircraft never arrived")
+		}
+		// The second follows ten minutes behind.
+		if c.elapsed() < 10*time.Minute {
+			c.step(first)
+		} else {
+			c.step(first, second)
+		}
+	}
+	if r := first.Report(); r.Gate != "A1" || r.Destination != "BOS" {
+		t.Fatalf("first aircraft arrived at %s gate %q, want BOS gate A1", r.Destination, r.Gate)
+	}
+	parked, _ := gates.Lookup("N1UT")
+
+	next := newTestAircraft(t, "N1UT", "BOS", "JFK", WithGates(gates))
+	next.plane.Move(domain.Motion{Time: c.now, Latitude: next.origin.Latitude, Longitude: next.origin.Longitude})
+	if r := next.Report(); r.Gate != "A1" {
+		t.Fatalf("next flight starts at gate %q, want A1", r.Gate)
+	}
+	var pushback time.Time
+	var waited time.Duration
+	for !second.Done() {
+		if c.elapsed() > 6*time.Hour {
+			t.Fatal("second aircraft never parked")
+		}
+		c.step(next, second)
+		if pushback.IsZero() && next.plane.Status() != domain.Idle {
+			pushback = c.now
+		}
+		if r := second.Report(); second.arrived && second.plane.Status() == domain.Taxi && second.taxiRemaining <= 0 {
+			if r.GroundSpeed != 0 {
+				t.Fatalf("second aircraft moving at %.0f knots while waiting for a gate", r.GroundSpeed)
+			}
+			waited += time.Second
+		}
+	}
+	if pushback.Sub(parked.Arrived) < turnaround {
+		t.Errorf("pushed back %v after arriving, before the %v turnaround", pushback.Sub(parked.Arrived), turnaround)
+	}
+	if waited < time.Minute {
+		t.Errorf("second aircraft waited %v for the gate", waited)
+	}
+	if r := second.Report(); r.Gate != "A1" {
+		t.Errorf("second aircraft parked at gate %q, want A1", r.Gate)
+	}
+	if next.Report().Gate != "" {
+		t.Errorf("departed aircraft still at gate %q", next.Report().Gate)
+	}
+}
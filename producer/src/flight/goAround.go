@@ -0,0 +1,112 @@
+package flight
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"plane-producer/src/domain"
+	"plane-producer/src/geo"
+	"plane-producer/src/units"
+)
+
+const (
+	// decisionNm is how far from the runway an approach that is not going
+	// to end in a landing is abandoned, about 200 ft up a 3° glide path.
+	decisionNm = 0.6
+	// missedApproachNm is how far past the runway a missed approach climbs
+	// straight ahead before turning back for another approach.
+	missedApproachNm = 5
+)
+
+// ErrNotLanding is returned when a go-around is ordered for a flight that
+// is not on final approach.
+var ErrNotLanding = errors.New("flight is not on final approach")
+
+// WithGoArounds abandons each approach for a go-around with the given
+// probability, drawn from the flight's random stream as the approach
+// begins.
+func WithGoArounds(probability float64) Option {
+	return func(o *options) { o.goArounds = probability }
+}
+
+// planApproach decides, as the aircraft turns onto final, whether the
+// approach ends in a go-around at the decision point.
+func (f *Flight) planApproach() {
+	f.goAroundNm = 0
+	if f.goArounds > 0 && f.rng.Float64() < f.goArounds {
+		f.goAroundNm = decisionNm
+	}
+}
+
+// GoAround has a flight on final approach abandon it on its next step,
+// climb away and come round for another.
+func (f *Flight) GoAround() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.plane.Status() != domain.Landing || f.touchdown {
+		return ErrNotLanding
+	}
+	f.goAroundNm = math.Inf(1)
+	return nil
+}
+
+// goAround abandons the approach and frees the runway. The missed approach
+// is a circuit round a racetrack whose inbound leg is the final approach
+// course, from the final approach fix to missedApproachNm past the runway:
+// it climbs straight ahead to the altitude of the fix, turns back,
+// flies downwind and turns in again over the fix.
+func (f *Flight) goAround(m domain.Motion) error {
+	f.leaveRunway()
+	f.goAroundNm = 0
+
+	fafNm := math.Max(f.track.Length()-finalApproachNm, 0)
+	course := f.track.CourseAt(fafNm)
+	leg := f.track.Length() - fafNm + missedApproachNm
+	f.hold = &geo.Hold{
+		Fix:           geo.Destination(f.track.PositionAt(fafNm), course, leg),
+		InboundCourse: course,
+		LegNm:         leg,
+		TurnRadiusNm:  geo.StandardRateRadius(m.Airspeed),
+	}
+	f.holdNm, f.missed = f.flown-fafNm, true
+	f.missedAltitude = f.profile.DescentAltitude(f.track.Length()-fafNm, f.descentFrom, f.destination.Elevation)
+	return f.plane.Transition(domain.AwaitingLanding)
+}
+
+// flyMissedApproach flies dt round the missed approach, reporting whether
+// the aircraft is back at the final approach fix, where it rejoins the
+// route.
+func (f *Flight) flyMissedApproach(m *domain.Motion, dt time.Duration) bool {
+	p := f.profile
+	h := *f.hold
+	m.GroundSpeed = m.Airspeed
+	f.holdNm += m.GroundSpeed * dt.Hours()
+	f.drift = 0
+	if m.Altitude < f.missedAltitude {
+		m.VerticalSpeed = p.RampVerticalSpeed(m.VerticalSpeed, units.FeetPerMinute(p.ClimbRate), dt)
+		m.Altitude = math.Min(m.Altitude+m.VerticalSpeed.Over(dt).Feet(), f.missedAltitude)
+	} else {
+		m.VerticalSpeed = 0
+	}
+
+	if f.holdNm < h.CircuitNm() {
+		pos, course := h.PositionAt(f.holdNm)
+		f.turnTo(m, pos, course, dt)
+		return false
+	}
+	f.hold, f.missed = nil, false
+	f.flown, f.crossTrack = math.Max(f.track.Length()-finalApproachNm, 0), 0
+	f.turnTo(m, f.track.PositionAt(f.flown), f.track.CourseAt(f.flown), dt)
+	return true
+}
+
+// remainingNm returns the distance left to fly to the runway, round the
+// rest of any missed approach and down final again.
+func (f *Flight) remainingNm() float64 {
+	if !f.missed {
+		return f.remaining()
+	}
+	return math.Max(f.hold.CircuitNm()-f.holdNm, 0) + math.Min(finalApproachNm, f.track.Length())
+}
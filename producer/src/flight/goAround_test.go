@@ -0,0 +1,96 @@
+package flight
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"plane-producer/src/domain"
+	"plane-producer/src/geo"
+)
+
+// TestGoAround orders a go-around on final and checks the aircraft climbs
+// away without touching down, flies the missed approach back to the final
+// approach fix without jumping, and lands from the second approach.
+func TestGoAround(t *testing.T) {
+	f := newTestFlight(t, "JFK", "BOS")
+	c := newClock(t)
+	var last domain.FlightRecord
+	step := func() domain.FlightRecord {
+		t.Helper()
+		r := c.step(f)
+		if last.Timestamp != 0 {
+			from := geo.Position{Latitude: geo.Degrees(last.Latitude), Longitude: geo.Degrees(last.Longitude)}
+			to := geo.Position{Latitude: geo.Degrees(r.Latitude), Longitude: geo.Degrees(r.Longitude)}
+			if d := geo.Distance(from, to); d > max(last.GroundSpeed, r.GroundSpeed)/3600+0.01 {
+				t.Fatalf("%v: moved %.2f nm in a second at %.0f knots", r.Status, d, r.GroundSpeed)
+			}
+		}
+		last = r
+		return r
+	}
+
+	if err := f.GoAround(); !errors.Is(err, ErrNotLanding) {
+		t.Errorf("going around at the gate: %v, want %v", err, ErrNotLanding)
+	}
+	for f.plane.Status() != domain.Landing {
+		if c.elapsed() > 3*time.Hour {
+			t.Fatal("never landing")
+		}
+		step()
+	}
+	for f.Remaining() > 3 {
+		step()
+	}
+	if err := f.GoAround(); err != nil {
+		t.Fatal(err)
+	}
+	low := step()
+	if low.Status != domain.AwaitingLanding {
+		t.Fatalf("%v after going around, want %v", low.Status, domain.AwaitingLanding)
+	}
+
+	var high float64
+	var landings int
+	for !f.Done() {
+		if c.elapsed() > 4*time.Hour {
+			t.Fatal("never arrived")
+		}
+		before := f.plane.Status()
+		r := step()
+		if r.Status == domain.Landing && before != domain.Landing {
+			landings++
+		}
+		if landings == 0 {
+			high = max(high, r.Altitude)
+		}
+	}
+	if high < low.Altitude+1000 {
+		t.Errorf("climbed to %.0f ft from %.0f ft on the missed approach", high, low.Altitude)
+	}
+	if landings != 1 {
+		t.Errorf("%d approaches after going around, want 1", landings)
+	}
+}
+
+// TestGoArounds checks that a flight certain to go around keeps coming
+// round for another approach and never lands, and that the runway is given
+// up each time.
+func TestGoArounds(t *testing.T) {
+	f := newTestFlight(t, "JFK", "BOS", WithGoArounds(1))
+	c := newClock(t)
+	var goArounds int
+	for c.elapsed() < 3*time.Hour {
+		before := f.plane.Status()
+		r := c.step(f)
+		if before == domain.Landing && r.Status == domain.AwaitingLanding {
+			goArounds++
+		}
+		if f.touchdown {
+			t.Fatal("landed")
+		}
+	}
+	if goArounds < 3 {
+		t.Errorf("went around %d times, want at least 3", goArounds)
+	}
+}
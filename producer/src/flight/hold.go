@@ -0,0 +1,48 @@
+package flight
+
+import (
+	"math"
+	"time"
+
+	"plane-producer/src/domain"
+	"plane-producer/src/geo"
+	"plane-producer/src/performance"
+)
+
+// enterHold starts a standard hold at the final approach fix, where the
+// aircraft is, with the approach course as the inbound leg. The aircraft
+// is at the fix, so it begins with the turn onto the outbound leg.
+func (f *Flight) enterHold(m domain.Motion) {
+	h := geo.NewHold(f.track.PositionAt(f.flown), f.track.CourseAt(f.flown), m.Airspeed)
+	f.hold, f.holdNm = &h, h.LegNm
+}
+
+// flyHold flies dt around the hold at a constant altitude, reporting
+// whether the aircraft came back over the fix, where it is then left.
+func (f *Flight) flyHold(m *domain.Motion, dt time.Duration) bool {
+	h := *f.hold
+	before := f.holdNm
+	m.GroundSpeed, m.VerticalSpeed = m.Airspeed, 0
+	f.holdNm += m.GroundSpeed * dt.Hours()
+	f.drift = 0
+
+	// The fix is at the end of every inbound leg. The aircraft is put
+	// over it, to go round again from there or leave the hold.
+	circuit := h.CircuitNm()
+	atFix := math.Floor((f.holdNm-h.LegNm)/circuit) > math.Floor((before-h.LegNm)/circuit)
+	if atFix {
+		f.holdNm = h.LegNm
+	}
+	pos, course := h.PositionAt(f.holdNm)
+	f.turnTo(m, pos, course, dt)
+	return atFix
+}
+
+// turnTo puts the aircraft at pos on course, banked for the turn onto it
+// over dt.
+func (f *Flight) turnTo(m *domain.Motion, pos geo.Position, course geo.Degrees, dt time.Duration) {
+	m.RateOfTurn = float64((course - geo.Degrees(m.Heading)).Signed()) / dt.Seconds()
+	m.Bank = performance.BankFor(m.RateOfTurn, m.Airspeed)
+	m.Latitude, m.Longitude = float64(pos.Latitude), float64(pos.Longitude)
+	m.Heading = float64(course)
+}
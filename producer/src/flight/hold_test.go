@@ -0,0 +1,73 @@
+package flight
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"plane-producer/src/domain"
+	"plane-producer/src/geo"
+	"plane-producer/src/ground"
+)
+
+// TestHoldForRunway keeps the destination runway busy and checks the
+// arrival flies the racetrack at the final approach fix, level and within
+// the pattern, until it is freed and the aircraft lands from the fix.
+func TestHoldForRunway(t *testing.T) {
+	runways := ground.NewRunways()
+	runways.Request("BOS", "N9UT")
+	f := newTestFlight(t, "JFK", "BOS", WithRunways(runways))
+
+	c := newClock(t)
+	for f.hold == nil {
+		if c.elapsed() > 3*time.Hour {
+			t.Fatal("never started holding")
+		}
+		c.step(f)
+	}
+
+	h := *f.hold
+	extent := h.LegNm + 2*h.TurnRadiusNm
+	var start domain.FlightRecord
+	for i := 0; i < 3*int(h.CircuitNm()/h.LegNm*60); i++ {
+		r := c.step(f)
+		if i == 0 {
+			start = r
+		}
+		if r.Status != domain.AwaitingLanding {
+			t.Fatalf("%v while the runway is busy", r.Status)
+		}
+		p := geo.Position{Latitude: geo.Degrees(r.Latitude), Longitude: geo.Degrees(r.Longitude)}
+		if d := geo.Distance(h.Fix, p); d > extent+0.1 {
+			t.Fatalf("%.1f nm from the fix, outside the hold", d)
+		}
+		if r.Altitude != start.Altitude || r.VerticalSpeed != 0 {
+			t.Fatalf("altitude %.0f ft at %.0f ft/min in the hold, want level at %.0f ft", r.Altitude, r.VerticalSpeed, start.Altitude)
+		}
+		if math.Abs(r.RateOfTurn) > 3.1 {
+			t.Fatalf("turning at %.1f°/s", r.RateOfTurn)
+		}
+	}
+
+	if err := runways.Release("BOS", "N9UT"); err != nil {
+		t.Fatal(err)
+	}
+	for f.plane.Status() == domain.AwaitingLanding {
+		if c.elapsed() > 4*time.Hour {
+			t.Fatal("never left the hold")
+		}
+		r := c.step(f)
+		if r.Status == domain.Landing {
+			p := geo.Position{Latitude: geo.Degrees(r.Latitude), Longitude: geo.Degrees(r.Longitude)}
+			if d := geo.Distance(h.Fix, p); d > 1e-6 {
+				t.Errorf("left the hold %.2f nm from the fix", d)
+			}
+		}
+	}
+	for !f.Done() {
+		if c.elapsed() > 5*time.Hour {
+			t.Fatal("never arrived")
+		}
+		c.step(f)
+	}
+}
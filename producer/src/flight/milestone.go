@@ -0,0 +1,76 @@
+package flight
+
+import "plane-producer/src/domain"
+
+// Milestone is a point in a flight that consumers care about more than
+// the phase changes around it.
+type Milestone string
+
+const (
+	Departed Milestone = "DEPARTED" // left the gate
+	Airborne Milestone = "AIRBORNE" // lifted off
+	Landed   Milestone = "LANDED"   // touched down
+	Arrived  Milestone = "ARRIVED"  // reached the gate
+)
+
+// MilestoneEvent is the record written when a flight passes a milestone.
+// Like geofence events it is told apart from position reports by its
+// "event" field.
+type MilestoneEvent struct {
+	Event       Milestone `json:"event"`
+	TailNum     string    `json:"plane"`
+	FlightID    string    `json:"flight"`
+	Timestamp   int64     `json:"time"` // unix milliseconds
+	Origin      string    `json:"orig"`
+	Destination string    `json:"dest"`
+	Latitude    float64   `json:"lat"`
+	Longitude   float64   `json:"long"`
+	Altitude    float64   `json:"alt"`
+}
+
+// MilestoneOf returns the event for a milestone passed at the report r.
+func MilestoneOf(m Milestone, r domain.FlightRecord) MilestoneEvent {
+	return MilestoneEvent{
+		Event:       m,
+		TailNum:     r.TailNum,
+		FlightID:    r.FlightID,
+		Timestamp:   r.Timestamp,
+		Origin:      r.Origin,
+		Destination: r.Destination,
+		Latitude:    r.Latitude,
+		Longitude:   r.Longitude,
+		Altitude:    r.Altitude,
+	}
+}
+
+// Milestones returns the milestones passed since it was last called, in
+// order.
+func (f *Flight) Milestones() []Milestone {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	passed := f.milestones
+	f.milestones = nil
+	return passed
+}
+
+// transitioned notes the milestones marked by a change of status. It is
+// the plane's transition hook, so runs within Step or a command, under f.mu.
+func (f *Flight) transitioned(_ *domain.PlaneDetails, from, to domain.Status) {
+	switch {
+	case from == domain.Idle && to == domain.Taxi:
+		f.milestones = append(f.milestones, Departed)
+	case from == domain.Taxi && to == domain.Idle && f.arrived:
+		f.milestones = append(f.milestones, Arrived)
+	}
+}
+
+// passed notes the milestones between being on the ground or not before a
+// step and after it.
+func (f *Flight) passed(airborne bool, m domain.Motion) {
+	switch up := f.airborne(m); {
+	case !airborne && up:
+		f.milestones = append(f.milestones, Airborne)
+	case airborne && !up:
+		f.milestones = append(f.milestones, Landed)
+	}
+}
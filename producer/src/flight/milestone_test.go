@@ -0,0 +1,26 @@
+package flight
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestMilestones flies a flight gate to gate and checks it passes each
+// milestone once, in order.
+func TestMilestones(t *testing.T) {
+	f := newTestFlight(t, "JFK", "BOS")
+	c := newClock(t)
+	var passed []Milestone
+	for !f.Done() {
+		if c.elapsed() > 4*time.Hour {
+			t.Fatal("never arrived")
+		}
+		c.step(f)
+		passed = append(passed, f.Milestones()...)
+	}
+	want := []Milestone{Departed, Airborne, Landed, Arrived}
+	if !slices.Equal(passed, want) {
+		t.Errorf("passed %v, want %v", passed, want)
+	}
+}
@@ -0,0 +1,67 @@
+package flight
+
+import (
+	"math"
+	"math/rand"
+
+	"plane-producer/src/domain"
+	"plane-producer/src/geo"
+)
+
+const metresPerNm = 1852.0
+
+// Noise is the standard deviation of the error added to each reported
+// position, standing in for GPS and altimeter error: Position in metres
+// across the ground and Altitude in feet. Only reports are affected; the
+// aircraft flies its true path.
+type Noise struct {
+	Position float64
+	Altitude float64
+}
+
+// WithNoise adds noise to the flight's reports, drawn from rng so that the
+// flight itself flies the same whether or not its reports are noisy.
+func WithNoise(n Noise, rng *rand.Rand) Option {
+	return func(o *options) {
+		o.noise, o.noiseRng = n, rng
+	}
+}
+
+// offset is the noise drawn for one step: metres north and east and feet
+// of altitude.
+type offset struct {
+	north, east, altitude float64
+}
+
+// drawNoise draws the noise for the step just taken, so that every report
+// until the next step carries the same error. Callers hold f.mu.
+func (f *Flight) drawNoise() {
+	if f.noiseRng == nil {
+		return
+	}
+	var o offset
+	if sigma := f.noise.Position; sigma > 0 {
+		o.north, o.east = f.noiseRng.NormFloat64()*sigma, f.noiseRng.NormFloat64()*sigma
+	}
+	if sigma := f.noise.Altitude; sigma > 0 {
+		o.altitude = f.noiseRng.NormFloat64() * sigma
+	}
+	f.offset = o
+}
+
+// record returns the aircraft's report, with its clearances, gate and the
+// step's noise added. Callers hold f.mu.
+func (f *Flight) record() domain.FlightRecord {
+	r := f.plane.Record()
+	r.TakeOffHeld, r.LandingHeld = f.takeOffHeld, f.landingHeld
+	r.Gate = f.gate
+	o := f.offset
+	if o.north != 0 || o.east != 0 {
+		bearing := geo.Radians(math.Atan2(o.east, o.north)).Degrees()
+		from := geo.Position{Latitude: geo.Degrees(r.Latitude), Longitude: geo.Degrees(r.Longitude)}
+		p := geo.Destination(from, bearing, math.Hypot(o.north, o.east)/metresPerNm)
+		r.Latitude, r.Longitude = float64(p.Latitude), float64(p.Longitude)
+	}
+	r.Altitude += o.altitude
+	return r
+}
@@ -0,0 +1,28 @@
+package flight
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// TestNoisePerStep checks reports are noisy, and carry the same noise
+// however often the flight is asked for one between steps.
+func TestNoisePerStep(t *testing.T) {
+	noisy := newTestFlight(t, "JFK", "BOS", WithNoise(Noise{Position: 50, Altitude: 50}, rand.New(rand.NewSource(1))))
+	clean := newTestFlight(t, "JFK", "BOS")
+	c := newClock(t)
+	for range 600 {
+		r := c.step(noisy)
+		truth, err := clean.Step(c.now, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Latitude == truth.Latitude || r.Altitude == truth.Altitude {
+			t.Fatalf("report without noise: %+v", r)
+		}
+		if again := noisy.Report(); again != r {
+			t.Fatalf("reported %+v, then %+v before the next step", r, again)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package flight
+
+import (
+	"math"
+	"time"
+
+	"plane-producer/src/domain"
+)
+
+// phaseTimes is how long a flight has spent in each part of its trip.
+type phaseTimes struct {
+	status                    map[domain.Status]time.Duration
+	taxiOut, taxiIn, airborne time.Duration
+	gateDelay, holding        time.Duration
+	last                      time.Time
+}
+
+// add accounts the step to now taken from status, on the ground or not,
+// and in a hold or not. The step is timed by the clock rather than dt, so
+// the phases add up to the times between reports even when ticks are
+// dropped. Gate delay is time kept at the gate after the flight was due,
+// without clearance or still being turned around; a flight that leaves on
+// time spends only its first step at the gate, which is not counted.
+func (t *phaseTimes) add(now time.Time, status domain.Status, airborne, arrived, holding bool, dt time.Duration) {
+	if t.status == nil {
+		t.status = make(map[domain.Status]time.Duration)
+	}
+	first := t.last.IsZero()
+	if !first {
+		dt = now.Sub(t.last)
+	}
+	t.last = now
+	switch {
+	case status == domain.Idle:
+		if !arrived && !first {
+			t.gateDelay += dt
+		}
+		return
+	case status == domain.Taxi && arrived:
+		t.taxiIn += dt
+	case status == domain.Taxi:
+		t.taxiOut += dt
+	}
+	if airborne {
+		t.airborne += dt
+	}
+	if holding {
+		t.holding += dt
+	}
+	t.status[status] += dt
+}
+
+// PhaseSummary is the record written when a flight arrives, giving the
+// minutes it spent in each phase. Like geofence events it is told apart
+// from position reports by its "event" field.
+type PhaseSummary struct {
+	Event       string                    `json:"event"`
+	TailNum     string                    `json:"plane"`
+	FlightID    string                    `json:"flight"`
+	Timestamp   int64                     `json:"time"` // unix milliseconds
+	Origin      string                    `json:"orig"`
+	Destination string                    `json:"dest"`
+	GateDelay   float64                   `json:"gateDelayMin"`
+	TaxiOut     float64                   `json:"taxiOutMin"`
+	Airborne    float64                   `json:"airborneMin"`
+	Holding     float64                   `json:"holdingMin"`
+	TaxiIn      float64                   `json:"taxiInMin"`
+	Phases      map[domain.Status]float64 `json:"phases"`
+}
+
+// Phases returns the summary of a flight whose last report is r.
+func (f *Flight) Phases(r domain.FlightRecord) PhaseSummary {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s := PhaseSummary{
+		Event:       "phases",
+		TailNum:     r.TailNum,
+		FlightID:    r.FlightID,
+		Timestamp:   r.Timestamp,
+		Origin:      r.Origin,
+		Destination: r.Destination,
+		GateDelay:   minutes(f.phases.gateDelay),
+		TaxiOut:     minutes(f.phases.taxiOut),
+		Airborne:    minutes(f.phases.airborne),
+		Holding:     minutes(f.phases.holding),
+		TaxiIn:      minutes(f.phases.taxiIn),
+		Phases:      make(map[domain.Status]float64, len(f.phases.status)),
+	}
+	for status, d := range f.phases.status {
+		s.Phases[status] = minutes(d)
+	}
+	return s
+}
+
+// minutes gives d in minutes to the hundredth.
+func minutes(d time.Duration) float64 {
+	return math.Round(d.Minutes()*100) / 100
+}
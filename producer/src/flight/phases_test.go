@@ -0,0 +1,57 @@
+package flight
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPhaseSummary holds a flight at the gate for ten minutes and in the
+// air for half an hour and checks the summary tells the two apart.
+func TestPhaseSummary(t *testing.T) {
+	f := newTestFlight(t, "JFK", "BOS")
+	if err := f.SetTakeOffClearance(false); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.SetLandingClearance(false); err != nil {
+		t.Fatal(err)
+	}
+
+	c := newClock(t)
+	for c.elapsed() < 10*time.Minute {
+		c.step(f)
+	}
+	if err := f.SetTakeOffClearance(true); err != nil {
+		t.Fatal(err)
+	}
+	for f.hold == nil {
+		if c.elapsed() > 3*time.Hour {
+			t.Fatal("never started holding")
+		}
+		c.step(f)
+	}
+	for held := c.now; c.now.Sub(held) < 30*time.Minute; {
+		c.step(f)
+	}
+	if err := f.SetLandingClearance(true); err != nil {
+		t.Fatal(err)
+	}
+	for !f.Done() {
+		if c.elapsed() > 5*time.Hour {
+			t.Fatal("never arrived")
+		}
+		c.step(f)
+	}
+
+	s := f.Phases(f.Report())
+	if s.GateDelay != 10 {
+		t.Errorf("gate delay = %v min, want 10", s.GateDelay)
+	}
+	// Cleared, the aircraft flies on round the hold to the fix, at most one
+	// more four-minute circuit of one-minute legs and standard rate turns.
+	if s.Holding < 30 || s.Holding > 34 {
+		t.Errorf("holding = %v min, want 30 and at most one more circuit", s.Holding)
+	}
+	if s.Holding > s.Airborne {
+		t.Errorf("held %v min of %v airborne", s.Holding, s.Airborne)
+	}
+}
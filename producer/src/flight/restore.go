@@ -0,0 +1,78 @@
+package flight
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"plane-producer/src/airports"
+	"plane-producer/src/domain"
+	"plane-producer/src/geo"
+	"plane-producer/src/ground"
+)
+
+// Restore resumes a flight from its last report, e.g. a checkpoint taken
+// before the producer stopped. The aircraft is put back where the report
+// has it, in the same phase, and how far along the route it has flown is
+// worked out from its position. What the report does not carry starts
+// afresh: taxiing takes its full time again and a hold is entered anew at
+// the final approach fix. To carry on numbering the aircraft's reports
+// from the report's, resume its sequence with reporting.Sequences.Resume.
+func Restore(r domain.FlightRecord, origin, destination airports.Airport, taxi ground.TaxiModel, rng *rand.Rand, opts ...Option) (*Flight, error) {
+	plane, err := domain.Restore(r)
+	if err != nil {
+		return nil, err
+	}
+	pos := geo.Position{Latitude: geo.Degrees(r.Latitude), Longitude: geo.Degrees(r.Longitude)}
+	if err := pos.Validate(); err != nil {
+		return nil, fmt.Errorf("restoring flight %s: %w", r.FlightID, err)
+	}
+	f, err := plan(plane, origin, destination, taxi, rng, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	switch r.Status {
+	case domain.Idle, domain.Taxi:
+		f.arrived = f.earth.Distance(pos, destination.Position()) < f.earth.Distance(pos, origin.Position())
+		switch {
+		case f.arrived:
+			f.flown = f.track.Length()
+			f.taxiRemaining = f.taxiIn
+		case r.Status == domain.Idle:
+			f.takeGate(plane.Timestamp())
+		default:
+			f.taxiRemaining = f.taxiOut
+		}
+	default:
+		// The route from here is the rest of the planned one, give or take
+		// how far off it the aircraft is.
+		remaining := f.navigation.NewRoute(f.earth, pos, destination.Position()).Length()
+		f.flown = math.Max(f.track.Length()-remaining, 0)
+		_, f.crossTrack = f.track.Deviation(pos, geo.Degrees(r.Heading))
+		f.descentFrom = f.cruiseAltitude
+		if len(f.steps) > 0 {
+			f.descentFrom = f.steps[len(f.steps)-1].Altitude
+		}
+		f.descentFrom = math.Max(f.descentFrom, r.Altitude)
+		f.touchdown = r.Status == domain.Landing && f.remaining() <= 0
+	}
+
+	// An aircraft taking off or landing holds the runway. If another has
+	// it now, one still in the air comes round for it again.
+	if r.Status == domain.TakeOff && !f.airborne(plane.Motion()) || r.Status == domain.Landing {
+		airport := origin.IATA
+		if r.Status == domain.Landing {
+			airport = destination.IATA
+		}
+		if !f.requestRunway(airport) {
+			f.withdrawRunway()
+			if r.Status == domain.Landing && !f.touchdown {
+				if err := plane.Transition(domain.AwaitingLanding); err != nil {
+					return nil, fmt.Errorf("restoring flight %s: %w", r.FlightID, err)
+				}
+			}
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,82 @@
+package flight
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"plane-producer/src/domain"
+	"plane-producer/src/ground"
+	"plane-producer/src/performance"
+	"plane-producer/src/reporting"
+)
+
+// TestRestore restores a flight from its report in each phase and checks
+// it is put back where it was, as far along the route, numbering its
+// reports on from the last, and flies on from there to arrive.
+func TestRestore(t *testing.T) {
+	f := newTestFlight(t, "JFK", "BOS")
+	restored := make(map[domain.Status]bool)
+	c := newClock(t)
+	for i := 0; !f.Done(); i++ {
+		if c.elapsed() > 6*time.Hour {
+			t.Fatal("flight did not finish in six hours")
+		}
+		r := c.step(f)
+		if restored[r.Status] || i%60 != 0 {
+			continue
+		}
+		restored[r.Status] = true
+
+		r.Sequence = uint64(i)
+		g, err := Restore(r, f.origin, f.destination, ground.DefaultTaxiModel, rand.New(rand.NewSource(2)), WithPerformanceProfile(performance.Defaults["A320"]))
+		if err != nil {
+			t.Fatalf("%s: %v", r.Status, err)
+		}
+		got := g.Report()
+		if got.Status != r.Status || got.Latitude != r.Latitude || got.Longitude != r.Longitude || got.Altitude != r.Altitude {
+			t.Errorf("%s: restored at %s %.4f,%.4f %.0fft, want %.4f,%.4f %.0fft",
+				r.Status, got.Status, got.Latitude, got.Longitude, got.Altitude, r.Latitude, r.Longitude, r.Altitude)
+		}
+		sequences := reporting.NewSequences()
+		sequences.Resume(got.TailNum, r.Sequence)
+		if seq := sequences.Next(got.TailNum); seq != r.Sequence+1 {
+			t.Errorf("%s: next report numbered %d, want %d", r.Status, seq, r.Sequence+1)
+		}
+		if d := math.Abs(g.Remaining() - f.Remaining()); d > 0.5 {
+			t.Errorf("%s: restored with %.1fnm to go, want %.1fnm", r.Status, g.Remaining(), f.Remaining())
+		}
+
+		then := &clock{t: t, now: c.now}
+		for !g.Done() {
+			if then.now.Sub(c.now) > 6*time.Hour {
+				t.Fatalf("%s: restored flight did not finish in six hours", r.Status)
+			}
+			then.step(g)
+		}
+		if got := g.Report(); got.Status != domain.Idle || g.Remaining() != 0 {
+			t.Errorf("%s: restored flight ended %s with %.1fnm to go", r.Status, got.Status, g.Remaining())
+		}
+	}
+	for _, s := range []domain.Status{domain.Taxi, domain.TakeOff, domain.Cruising, domain.AwaitingLanding, domain.Landing} {
+		if !restored[s] {
+			t.Errorf("never restored while %s", s)
+		}
+	}
+}
+
+// TestRestoreInvalid rejects reports that cannot be flown on from.
+func TestRestoreInvalid(t *testing.T) {
+	f := newTestFlight(t, "JFK", "BOS")
+	r := f.Report()
+	r.Latitude = 91
+	if _, err := Restore(r, f.origin, f.destination, ground.DefaultTaxiModel, rand.New(rand.NewSource(1)), WithPerformanceProfile(performance.Defaults["A320"])); err == nil {
+		t.Error("restored a flight north of the pole")
+	}
+	r = f.Report()
+	r.FlightID = ""
+	if _, err := Restore(r, f.origin, f.destination, ground.DefaultTaxiModel, rand.New(rand.NewSource(1)), WithPerformanceProfile(performance.Defaults["A320"])); err == nil {
+		t.Error("restored a flight without a flight ID")
+	}
+}
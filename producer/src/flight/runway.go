@@ -0,0 +1,41 @@
+package flight
+
+import "plane-producer/src/ground"
+
+// WithRunways sequences the flight's take-off and landing with other
+// aircraft's on the runways of r: it holds short of the runway, or flies a
+// hold at the final approach fix, until the runway is its to use.
+func WithRunways(r *ground.Runways) Option {
+	return func(o *options) { o.runways = r }
+}
+
+// requestRunway asks for the runway at airport, reporting whether the
+// aircraft may use it. Without runways modelled it always may.
+func (f *Flight) requestRunway(airport string) bool {
+	if f.runways == nil {
+		return true
+	}
+	f.runway = airport
+	f.onRunway = f.runways.Request(airport, f.plane.TailNum())
+	return f.onRunway
+}
+
+// leaveRunway frees the runway once the aircraft has lifted off or turned
+// off it after landing.
+func (f *Flight) leaveRunway() {
+	if f.runways == nil || !f.onRunway {
+		return
+	}
+	f.runways.Release(f.runway, f.plane.TailNum())
+	f.runway, f.onRunway = "", false
+}
+
+// withdrawRunway takes the aircraft off the runway it holds or is queued
+// for, if any.
+func (f *Flight) withdrawRunway() {
+	if f.runways == nil || f.runway == "" {
+		return
+	}
+	f.runways.Withdraw(f.runway, f.plane.TailNum())
+	f.runway, f.onRunway = "", false
+}
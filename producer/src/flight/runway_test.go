@@ -0,0 +1,56 @@
+package flight
+
+import (
+	"testing"
+	"time"
+
+	"plane-producer/src/domain"
+	"plane-producer/src/ground"
+)
+
+// TestRunwaySequencing flies two identical flights at once over a shared
+// set of runways and checks they take it in turns to take off and land.
+func TestRunwaySequencing(t *testing.T) {
+	runways := ground.NewRunways()
+	first := newTestAircraft(t, "N1UT", "JFK", "BOS", WithRunways(runways))
+	second := newTestAircraft(t, "N2UT", "JFK", "BOS", WithRunways(runways))
+
+	using := func(f *Flight) bool {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		return f.onRunway
+	}
+	var firstOff, secondOff, firstLanding, secondLanding time.Time
+	c := newClock(t)
+	for !first.Done() || !second.Done() {
+		if c.elapsed() > 6*time.Hour {
+			t.Fatal("flights did not finish")
+		}
+		c.step(first, second)
+		if using(first) && using(second) {
+			t.Fatalf("both aircraft on the runway at %v", c.elapsed())
+		}
+		for _, m := range []struct {
+			f         *Flight
+			off, land *time.Time
+		}{{first, &firstOff, &firstLanding}, {second, &secondOff, &secondLanding}} {
+			switch s := m.f.plane.Status(); {
+			case s == domain.TakeOff && m.off.IsZero():
+				*m.off = c.now
+			case s == domain.Landing && m.land.IsZero():
+				*m.land = c.now
+			}
+		}
+	}
+	if !secondOff.After(firstOff) {
+		t.Errorf("second took off at %v, not after the first at %v", secondOff.Sub(epoch), firstOff.Sub(epoch))
+	}
+	if !secondLanding.After(firstLanding) {
+		t.Errorf("second landed at %v, not after the first at %v", secondLanding.Sub(epoch), firstLanding.Sub(epoch))
+	}
+	for _, code := range []string{"JFK", "BOS"} {
+		if holder, queued := runways.Occupancy(code); holder != "" || queued != 0 {
+			t.Errorf("%s runway left with %q on it and %d queued", code, holder, queued)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package flight
+
+import (
+	"math"
+
+	"plane-producer/src/domain"
+	"plane-producer/src/geo"
+	"plane-producer/src/weather"
+)
+
+const (
+	// cellSampleNm is how finely a route is checked for weather cells.
+	cellSampleNm = 2
+	// detourMarginNm is how far before and after a severe cell a detour
+	// keeps clear of it.
+	detourMarginNm = 10
+	// detourStepNm and maxDetourNm are the offsets tried to either side of
+	// the route to find one clear of a severe cell.
+	detourStepNm = 5
+	maxDetourNm  = 150
+	// minDetourNm is how far from either airport a cell must be to be flown
+	// around; one over an airport has to be flown through.
+	minDetourNm = 30
+)
+
+// WithWeather flies the flight through the given wind and weather cells.
+func WithWeather(w weather.Weather) Option {
+	return func(o *options) { o.weather = w }
+}
+
+// detour is a lateral offset from the route, to the right of it or to the
+// left if negative, flown to keep clear of a severe cell.
+type detour struct {
+	cell         string
+	fromNm, toNm float64 // along the route, where the offset is held
+	offsetNm     float64
+}
+
+// planDetours finds where the route passes through severe cells, and the
+// smallest offset to either side of it that keeps clear of them all the way
+// past. It is started early enough to reach the offset at 45 degrees.
+func (f *Flight) planDetours() {
+	f.detours = nil
+	var severe weather.Zones
+	for _, c := range f.weather.Cells.Crossed(f.track, cellSampleNm) {
+		if c.Severity.Response().Avoid {
+			severe = append(severe, c)
+		}
+	}
+	length := f.track.Length()
+	for _, c := range severe {
+		from, to := math.Inf(1), math.Inf(-1)
+		for d := 0.0; d <= length; d += cellSampleNm {
+			if c.Area.Contains(f.track.PositionAt(d)) {
+				from, to = math.Min(from, d), math.Max(to, d)
+			}
+		}
+		from, to = from-detourMarginNm, to+detourMarginNm
+		if from < minDetourNm || to > length-minDetourNm {
+			continue
+		}
+		if offset, ok := clearOffset(f.track, severe, from, to); ok {
+			f.detours = append(f.detours, detour{cell: c.ID, fromNm: from - math.Abs(offset), toNm: to, offsetNm: offset})
+		}
+	}
+}
+
+// clearOffset returns the smallest offset from the route between from and
+// to that is outside every one of cells.
+func clearOffset(r geo.Route, cells weather.Zones, from, to float64) (float64, bool) {
+	clear := func(offset float64) bool {
+		for d := from; d <= to; d += cellSampleNm {
+			p := geo.Destination(r.PositionAt(d), r.CourseAt(d)+90, offset)
+			if _, in := cells.At(p); in {
+				return false
+			}
+		}
+		return true
+	}
+	for off := float64(detourStepNm); off <= maxDetourNm; off += detourStepNm {
+		for _, offset := range []float64{off, -off} {
+			if clear(offset) {
+				return offset, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// offsetAt returns how far to the side of the route to fly after flownNm.
+func (f *Flight) offsetAt(flownNm float64) float64 {
+	for _, d := range f.detours {
+		if flownNm >= d.fromNm && flownNm < d.toNm {
+			return d.offsetNm
+		}
+	}
+	return 0
+}
+
+// encounter notes the weather cell the aircraft is in, reporting turbulence
+// on entering one.
+func (f *Flight) encounter(m domain.Motion) {
+	p := geo.Position{Latitude: geo.Degrees(m.Latitude), Longitude: geo.Degrees(m.Longitude)}
+	c, in := f.weather.Cells.At(p)
+	if !in || !f.airborne(m) {
+		f.cell = weather.Cell{}
+		return
+	}
+	if c.ID != f.cell.ID {
+		f.encounters = append(f.encounters, c)
+	}
+	f.cell = c
+}
+
+// speedFactor returns how much to slow down for the cell the aircraft is
+// in, to turbulence penetration speed in moderate turbulence.
+func (f *Flight) speedFactor() float64 {
+	if f.cell.ID == "" {
+		return 1
+	}
+	return f.cell.Severity.Response().SpeedFactor
+}
+
+// TurbulenceEvent is the record written when a flight flies into a weather
+// cell, reporting turbulence of the cell's severity.
+type TurbulenceEvent struct {
+	Event     string           `json:"event"`
+	TailNum   string           `json:"plane"`
+	FlightID  string           `json:"flight"`
+	Timestamp int64            `json:"time"` // unix milliseconds
+	Cell      string           `json:"cell"`
+	Severity  weather.Severity `json:"severity"`
+	Latitude  float64          `json:"lat"`
+	Longitude float64          `json:"long"`
+	Altitude  float64          `json:"alt"`
+}
+
+// TurbulenceOf returns the event for flying into c at the report r.
+func TurbulenceOf(c weather.Cell, r domain.FlightRecord) TurbulenceEvent {
+	return TurbulenceEvent{
+		Event:     "turbulence",
+		TailNum:   r.TailNum,
+		FlightID:  r.FlightID,
+		Timestamp: r.Timestamp,
+		Cell:      c.ID,
+		Severity:  c.Severity,
+		Latitude:  r.Latitude,
+		Longitude: r.Longitude,
+		Altitude:  r.Altitude,
+	}
+}
+
+// Encounters returns the weather cells flown into since it was last called,
+// in order.
+func (f *Flight) Encounters() []weather.Cell {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	cells := f.encounters
+	f.encounters = nil
+	return cells
+}
@@ -0,0 +1,116 @@
+package flight
+
+import (
+	"math"
+	"testing"
+
+	"plane-producer/src/domain"
+	"plane-producer/src/geo"
+	"plane-producer/src/weather"
+)
+
+// square returns a cell sideNm across centred on the point halfway along
+// the great circle between two airports.
+func square(t *testing.T, id string, severity weather.Severity, from, to string, sideNm float64) weather.Cell {
+	t.Helper()
+	f := newTestFlight(t, from, to)
+	track := geo.NewTrack(f.origin.Position(), f.destination.Position())
+	mid := track.PositionAt(track.Length() / 2)
+	var area geo.Polygon
+	for _, bearing := range []geo.Degrees{45, 135, 225, 315} {
+		area = append(area, geo.Destination(mid, bearing, sideNm/math.Sqrt2))
+	}
+	return weather.Cell{ID: id, Severity: severity, Area: area}
+}
+
+func cruiseTime(records []domain.FlightRecord) int {
+	n := 0
+	for _, r := range records {
+		if r.Status == domain.Cruising {
+			n++
+		}
+	}
+	return n
+}
+
+func TestWind(t *testing.T) {
+	still := fly(t, newTestFlight(t, "JFK", "LHR"))
+	tail := fly(t, newTestFlight(t, "JFK", "LHR", WithWeather(weather.Weather{Wind: weather.Wind{From: 270, Knots: 100}})))
+	if cruiseTime(tail) >= cruiseTime(still)*9/10 {
+		t.Errorf("cruised %ds with a 100 knot tailwind, %ds in still air", cruiseTime(tail), cruiseTime(still))
+	}
+
+	// A crosswind from the north has the aircraft crab into it while its
+	// track stays on the route.
+	f := newTestFlight(t, "JFK", "LHR", WithWeather(weather.Weather{Wind: weather.Wind{From: 0, Knots: 80}}))
+	track := geo.NewTrack(f.origin.Position(), f.destination.Position())
+	for _, r := range fly(t, f) {
+		if r.Status != domain.Cruising {
+			continue
+		}
+		p := geo.Position{Latitude: geo.Degrees(r.Latitude), Longitude: geo.Degrees(r.Longitude)}
+		along := geo.AlongTrackDistance(track.Origin, track.Destination, p)
+		course := track.CourseAt(along)
+		if xt := math.Abs(geo.CrossTrackDistance(track.Origin, track.Destination, p)); xt > 0.5 {
+			t.Fatalf("%.2f nm off the route in a crosswind", xt)
+		}
+		if crab := (geo.Degrees(r.Heading) - course).Signed(); crab > -2 {
+			t.Fatalf("heading %.1f° on a course of %.1f° into a wind from the north", r.Heading, course)
+		}
+		if math.Abs(r.DeviationDegrees) > 1 {
+			t.Fatalf("track error %.2f° includes the wind correction", r.DeviationDegrees)
+		}
+	}
+}
+
+// TestSevereCell checks that a flight flies around a severe cell on its
+// route and then back onto the route.
+func TestSevereCell(t *testing.T) {
+	cell := square(t, "CB1", weather.Severe, "JFK", "LHR", 60)
+	f := newTestFlight(t, "JFK", "LHR", WithWeather(weather.Weather{Cells: weather.Zones{cell}}))
+	if len(f.detours) != 1 {
+		t.Fatalf("planned %d detours, want 1", len(f.detours))
+	}
+	var widest float64
+	for _, r := range fly(t, f) {
+		if cell.Area.Contains(geo.Position{Latitude: geo.Degrees(r.Latitude), Longitude: geo.Degrees(r.Longitude)}) {
+			t.Fatalf("flew into the cell at %v, %v", r.Latitude, r.Longitude)
+		}
+		widest = math.Max(widest, math.Abs(r.DeviationMiles))
+	}
+	if widest < 30 {
+		t.Errorf("deviated at most %.1f nm around a 60 nm cell", widest)
+	}
+	if cells := f.Encounters(); len(cells) != 0 {
+		t.Errorf("reported turbulence in %v", cells)
+	}
+	last := f.Report()
+	if d := geo.Distance(geo.Position{Latitude: geo.Degrees(last.Latitude), Longitude: geo.Degrees(last.Longitude)}, f.destination.Position()); d > 0.01 {
+		t.Errorf("finished %.3f nm from LHR", d)
+	}
+}
+
+// TestModerateCell checks that a flight through moderate turbulence reports
+// it once and slows down while in it.
+func TestModerateCell(t *testing.T) {
+	cell := square(t, "TB1", weather.Moderate, "JFK", "LHR", 60)
+	f := newTestFlight(t, "JFK", "LHR", WithWeather(weather.Weather{Cells: weather.Zones{cell}}))
+	var slowest, fastest float64 = math.Inf(1), 0
+	for _, r := range fly(t, f) {
+		if r.Status != domain.Cruising {
+			continue
+		}
+		if cell.Area.Contains(geo.Position{Latitude: geo.Degrees(r.Latitude), Longitude: geo.Degrees(r.Longitude)}) {
+			slowest = math.Min(slowest, r.Airspeed)
+		} else {
+			fastest = math.Max(fastest, r.Airspeed)
+		}
+	}
+	if want := fastest * 0.9; math.Abs(slowest-want) > 1 {
+		t.Errorf("slowed to %.1f knots in the cell from %.1f, want %.1f", slowest, fastest, want)
+	}
+	cells := f.Encounters()
+	if len(cells) != 1 || cells[0].ID != "TB1" {
+		t.Errorf("reported turbulence in %v, want TB1 once", cells)
+	}
+}
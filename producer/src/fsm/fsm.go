@@ -0,0 +1,82 @@
+package fsm
+
+import "fmt"
+
+// Guard decides whether a subject may take a transition. A non-nil error
+// blocks it and says why.
+type Guard[T any] func(subject T) error
+
+// Action runs when a subject enters a state.
+type Action[S comparable, T any] func(subject T, from S)
+
+// Machine is a finite state machine over states S for subjects T. States and
+// the transitions between them are registered up front; the machine itself
+// holds no per-subject state, so one machine can drive a whole fleet.
+type Machine[S comparable, T any] struct {
+	onEnter     map[S]Action[S, T]
+	transitions map[S]map[S]Guard[T]
+	order       map[S][]S
+}
+
+// New creates an empty machine.
+func New[S comparable, T any]() *Machine[S, T] {
+	return &Machine[S, T]{
+		onEnter:     make(map[S]Action[S, T]),
+		transitions: make(map[S]map[S]Guard[T]),
+		order:       make(map[S][]S),
+	}
+}
+
+// OnEnter sets the action run when a subject enters state s, replacing any
+// earlier one.
+func (m *Machine[S, T]) OnEnter(s S, action Action[S, T]) *Machine[S, T] {
+	m.onEnter[s] = action
+	return m
+}
+
+// Allow permits the transition from one state to another, optionally
+// guarded. Registering the same transition again replaces its guard.
+func (m *Machine[S, T]) Allow(from, to S, guard Guard[T]) *Machine[S, T] {
+	targets := m.transitions[from]
+	if targets == nil {
+		targets = make(map[S]Guard[T])
+		m.transitions[from] = targets
+	}
+	if _, ok := targets[to]; !ok {
+		m.order[from] = append(m.order[from], to)
+	}
+	targets[to] = guard
+	return m
+}
+
+// Can reports whether subject may move from one state to another.
+func (m *Machine[S, T]) Can(subject T, from, to S) error {
+	guard, ok := m.transitions[from][to]
+	if !ok {
+		return fmt.Errorf("no transition from %v to %v", from, to)
+	}
+	if guard != nil {
+		if err := guard(subject); err != nil {
+			return fmt.Errorf("%v to %v: %w", from, to, err)
+		}
+	}
+	return nil
+}
+
+// Fire checks the transition and runs the entry action of the new state.
+// The caller records the new state once Fire returns nil.
+func (m *Machine[S, T]) Fire(subject T, from, to S) error {
+	if err := m.Can(subject, from, to); err != nil {
+		return err
+	}
+	if action := m.onEnter[to]; action != nil {
+		action(subject, from)
+	}
+	return nil
+}
+
+// Next returns the states reachable from s, in registration order,
+// regardless of guards.
+func (m *Machine[S, T]) Next(s S) []S {
+	return append([]S(nil), m.order[s]...)
+}
@@ -0,0 +1,39 @@
+package geo
+
+import "math"
+
+// Degrees is an angle in degrees. Positions, bearings and courses are all
+// expressed in degrees; convert with Radians before doing trigonometry.
+type Degrees float64
+
+// Radians is an angle in radians.
+type Radians float64
+
+func (d Degrees) Radians() Radians {
+	return Radians(d * math.Pi / 180)
+}
+
+func (r Radians) Degrees() Degrees {
+	return Degrees(r * 180 / math.Pi)
+}
+
+// Normalized returns the angle as a bearing in [0, 360).
+func (d Degrees) Normalized() Degrees {
+	n := Degrees(math.Mod(float64(d), 360))
+	if n < 0 {
+		n += 360
+	}
+	return n
+}
+
+// Signed returns the angle in (-180, 180], e.g. to tell left from right.
+func (d Degrees) Signed() Degrees {
+	n := d.Normalized()
+	if n > 180 {
+		n -= 360
+	}
+	return n
+}
+
+func sin(r Radians) float64 { return math.Sin(float64(r)) }
+func cos(r Radians) float64 { return math.Cos(float64(r)) }
@@ -0,0 +1,87 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// anyPosition generates positions anywhere on the Earth, poles included.
+type anyPosition Position
+
+func (anyPosition) Generate(rng *rand.Rand, _ int) reflect.Value {
+	lat := Degrees(rng.Float64()*180 - 90)
+	switch rng.Intn(20) {
+	case 0:
+		lat = 90
+	case 1:
+		lat = -90
+	}
+	return reflect.ValueOf(anyPosition{Latitude: lat, Longitude: Degrees(rng.Float64()*360 - 180)})
+}
+
+func TestAngleConversions(t *testing.T) {
+	roundTrips := func(d float64) bool {
+		d = math.Mod(d, 1e6)
+		back := Degrees(d).Radians().Degrees()
+		return math.Abs(float64(back)-d) <= 1e-9*math.Max(1, math.Abs(d))
+	}
+	if err := quick.Check(roundTrips, nil); err != nil {
+		t.Error(err)
+	}
+
+	inRange := func(d float64) bool {
+		d = math.Mod(d, 1e6)
+		n, s := Degrees(d).Normalized(), Degrees(d).Signed()
+		return n >= 0 && n < 360 && s > -180 && s <= 180 &&
+			math.Abs(math.Remainder(float64(n)-d, 360)) < 1e-9 &&
+			math.Abs(math.Remainder(float64(s)-d, 360)) < 1e-9
+	}
+	if err := quick.Check(inRange, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestVectorRoundTrip flies from one position towards another on the
+// initial bearing for the distance between them, which must arrive there:
+// bearing and distance are the inverse of Destination. Rounding near the
+// poles costs up to a few centimetres, well inside the tolerance of about
+// two metres.
+func TestVectorRoundTrip(t *testing.T) {
+	arrives := func(a, b anyPosition) bool {
+		from, to := Position(a), Position(b)
+		// Near-antipodal pairs have no well-defined bearing.
+		if Distance(from, to) > math.Pi*EarthRadiusNm-1 {
+			return true
+		}
+		got := Destination(from, InitialBearing(from, to), Distance(from, to))
+		if d := Distance(got, to); d > 1e-3 {
+			t.Logf("from %v to %v arrived %.3g nm away at %v", from, to, d, got)
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(arrives, &quick.Config{MaxCount: 5000}); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestDestinationDistance checks that flying distanceNm on any bearing ends
+// that far from the start.
+func TestDestinationDistance(t *testing.T) {
+	travels := func(a anyPosition, bearing, distance float64) bool {
+		from := Position(a)
+		nm := math.Mod(math.Abs(distance), math.Pi*EarthRadiusNm)
+		got := Destination(from, Degrees(math.Mod(bearing, 360)), nm)
+		if err := got.Validate(); err != nil {
+			t.Log(err)
+			return false
+		}
+		return math.Abs(Distance(from, got)-nm) <= 1e-6
+	}
+	if err := quick.Check(travels, &quick.Config{MaxCount: 5000}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,45 @@
+package geo
+
+import "math"
+
+// CrossTrackDistance returns how far p lies from the great circle through
+// from and to, in nautical miles. It is positive when p is to the right of
+// the direction of travel and negative to the left.
+func CrossTrackDistance(from, to, p Position) float64 {
+	δ13 := centralAngle(from, p)
+	θ13 := InitialBearing(from, p).Radians()
+	θ12 := InitialBearing(from, to).Radians()
+	return EarthRadiusNm * math.Asin(sin(δ13)*sin(θ13-θ12))
+}
+
+// AlongTrackDistance returns how far along the great circle from from
+// towards to the point closest to p lies, in nautical miles. It is negative
+// when that point is behind from.
+func AlongTrackDistance(from, to, p Position) float64 {
+	δ13 := centralAngle(from, p)
+	δxt := CrossTrackDistance(from, to, p) / EarthRadiusNm
+	along := EarthRadiusNm * math.Acos(math.Max(-1, math.Min(1, cos(δ13)/math.Cos(δxt))))
+
+	θ13 := InitialBearing(from, p).Radians()
+	θ12 := InitialBearing(from, to).Radians()
+	if cos(θ13-θ12) < 0 {
+		return -along
+	}
+	return along
+}
+
+// Deviation measures how far an aircraft at p flying heading has strayed
+// from the track: the cross-track distance in nautical miles, positive right
+// of track, and the track angle error in (-180, 180], positive when heading
+// right of the planned course at the nearest point on the track.
+func (t Track) Deviation(p Position, heading Degrees) (trackErrorDeg Degrees, crossTrackNm float64) {
+	if t.length == 0 {
+		return 0, 0
+	}
+	along := math.Max(0, math.Min(AlongTrackDistance(t.Origin, t.Destination, p), t.length))
+	trackErrorDeg = (heading - t.CourseAt(along)).Normalized()
+	if trackErrorDeg > 180 {
+		trackErrorDeg -= 360
+	}
+	return trackErrorDeg, CrossTrackDistance(t.Origin, t.Destination, p)
+}
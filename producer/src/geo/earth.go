@@ -0,0 +1,58 @@
+package geo
+
+import "fmt"
+
+// EarthModel is the shape of the Earth distances and routes are computed
+// on. The sphere is simpler and faster; WGS84 is accurate to within a few
+// millimetres where the sphere can be off by up to 0.5%.
+type EarthModel string
+
+const (
+	Sphere EarthModel = "sphere"
+	WGS84  EarthModel = "wgs84"
+)
+
+// ParseEarthModel validates a model name, defaulting to Sphere when it is
+// empty.
+func ParseEarthModel(name string) (EarthModel, error) {
+	switch m := EarthModel(name); m {
+	case "":
+		return Sphere, nil
+	case Sphere, WGS84:
+		return m, nil
+	default:
+		return "", fmt.Errorf("unknown earth model %q", name)
+	}
+}
+
+// Distance returns the shortest distance between two positions in nautical
+// miles.
+func (m EarthModel) Distance(from, to Position) float64 {
+	if m == WGS84 {
+		if d, _, _, ok := VincentyInverse(from, to); ok {
+			return d
+		}
+	}
+	return Distance(from, to)
+}
+
+// InitialBearing returns the initial true course of the shortest path from
+// one position to another.
+func (m EarthModel) InitialBearing(from, to Position) Degrees {
+	if m == WGS84 {
+		if _, b, _, ok := VincentyInverse(from, to); ok {
+			return b
+		}
+	}
+	return InitialBearing(from, to)
+}
+
+// Destination returns the position reached by following the shortest path
+// from start on the given initial true course for distanceNm.
+func (m EarthModel) Destination(start Position, bearing Degrees, distanceNm float64) Position {
+	if m == WGS84 {
+		p, _ := VincentyDirect(start, bearing, distanceNm)
+		return p
+	}
+	return Destination(start, bearing, distanceNm)
+}
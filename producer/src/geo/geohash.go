@@ -0,0 +1,107 @@
+package geo
+
+import "math"
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Geohash encodes p as a geohash of the given number of characters. Nearby
+// positions share a prefix, so geohashes make a simple spatial index key.
+func Geohash(p Position, precision int) string {
+	latLo, latHi := -90.0, 90.0
+	lonLo, lonHi := -180.0, 180.0
+	lat, lon := float64(p.Latitude), float64(normalizeLongitude(p.Longitude))
+
+	hash := make([]byte, 0, precision)
+	bits, ch, even := 0, 0, true
+	for len(hash) < precision {
+		if even {
+			mid := (lonLo + lonHi) / 2
+			if lon >= mid {
+				ch = ch<<1 | 1
+				lonLo = mid
+			} else {
+				ch <<= 1
+				lonHi = mid
+			}
+		} else {
+			mid := (latLo + latHi) / 2
+			if lat >= mid {
+				ch = ch<<1 | 1
+				latLo = mid
+			} else {
+				ch <<= 1
+				latHi = mid
+			}
+		}
+		even = !even
+
+		if bits++; bits == 5 {
+			hash = append(hash, geohashAlphabet[ch])
+			bits, ch = 0, 0
+		}
+	}
+	return string(hash)
+}
+
+// GeohashCellSize returns the height and width in degrees of a geohash cell
+// of the given precision.
+func GeohashCellSize(precision int) (lat, lon Degrees) {
+	lonBits := (5*precision + 1) / 2
+	latBits := 5 * precision / 2
+	return Degrees(180 / math.Pow(2, float64(latBits))), Degrees(360 / math.Pow(2, float64(lonBits)))
+}
+
+// GeohashesCovering returns the geohash cells of the given precision that
+// cover a latitude/longitude box. A box whose minLon is greater than its
+// maxLon crosses the antimeridian. It gives up and returns false if more
+// than limit cells would be needed.
+func GeohashesCovering(minLat, minLon, maxLat, maxLon Degrees, precision, limit int) ([]string, bool) {
+	if minLon > maxLon {
+		west, ok := GeohashesCovering(minLat, minLon, maxLat, 180, precision, limit)
+		if !ok {
+			return nil, false
+		}
+		east, ok := GeohashesCovering(minLat, -180, maxLat, maxLon, precision, limit)
+		if !ok {
+			return nil, false
+		}
+
+		// Both halves include the cells on the antimeridian itself.
+		cells := west
+		seen := make(map[string]bool, len(west))
+		for _, c := range west {
+			seen[c] = true
+		}
+		for _, c := range east {
+			if !seen[c] {
+				cells = append(cells, c)
+			}
+		}
+		if len(cells) > limit {
+			return nil, false
+		}
+		return cells, true
+	}
+
+	dLat, dLon := GeohashCellSize(precision)
+	rows := int(math.Floor(float64(maxLat)/float64(dLat))-math.Floor(float64(minLat)/float64(dLat))) + 1
+	cols := int(math.Floor(float64(maxLon)/float64(dLon))-math.Floor(float64(minLon)/float64(dLon))) + 1
+	if rows*cols > limit {
+		return nil, false
+	}
+
+	seen := make(map[string]bool, rows*cols)
+	var cells []string
+	for r := 0; r < rows; r++ {
+		lat := math.Min(float64(minLat)+float64(r)*float64(dLat), float64(maxLat))
+		for c := 0; c < cols; c++ {
+			lon := math.Min(float64(minLon)+float64(c)*float64(dLon), float64(maxLon))
+			cell := Geohash(Position{Latitude: Degrees(lat), Longitude: Degrees(lon)}, precision)
+			if !seen[cell] {
+				seen[cell] = true
+				cells = append(cells, cell)
+			}
+		}
+	}
+	return cells, true
+}
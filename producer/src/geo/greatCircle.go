@@ -0,0 +1,152 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+)
+
+// EarthRadiusNm is the mean radius of the Earth in nautical miles.
+const EarthRadiusNm = 3440.065
+
+// Position is a point on the Earth's surface.
+type Position struct {
+	Latitude  Degrees `json:"lat"`
+	Longitude Degrees `json:"long"`
+}
+
+// Validate checks the latitude is within [-90, 90] and the longitude within
+// [-180, 180].
+func (p Position) Validate() error {
+	lat, long := float64(p.Latitude), float64(p.Longitude)
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return fmt.Errorf("latitude %v out of range [-90, 90]", lat)
+	}
+	if math.IsNaN(long) || long < -180 || long > 180 {
+		return fmt.Errorf("longitude %v out of range [-180, 180]", long)
+	}
+	return nil
+}
+
+// Distance returns the great-circle distance between two positions in
+// nautical miles, using the haversine formula.
+func Distance(from, to Position) float64 {
+	return EarthRadiusNm * float64(centralAngle(from, to))
+}
+
+// centralAngle returns the angle subtended at the Earth's centre by two
+// positions.
+func centralAngle(from, to Position) Radians {
+	φ1, φ2 := from.Latitude.Radians(), to.Latitude.Radians()
+	Δφ := φ2 - φ1
+	Δλ := (to.Longitude - from.Longitude).Radians()
+
+	a := sin(Δφ/2)*sin(Δφ/2) + cos(φ1)*cos(φ2)*sin(Δλ/2)*sin(Δλ/2)
+	return Radians(2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a)))
+}
+
+// InitialBearing returns the true course in [0, 360) to fly from one
+// position towards another along the great circle.
+func InitialBearing(from, to Position) Degrees {
+	φ1, φ2 := from.Latitude.Radians(), to.Latitude.Radians()
+	Δλ := (to.Longitude - from.Longitude).Radians()
+
+	y := sin(Δλ) * cos(φ2)
+	x := cos(φ1)*sin(φ2) - sin(φ1)*cos(φ2)*cos(Δλ)
+	return Radians(math.Atan2(y, x)).Degrees().Normalized()
+}
+
+// Destination returns the position reached by flying distanceNm from start
+// on the given initial true course.
+func Destination(start Position, bearing Degrees, distanceNm float64) Position {
+	φ1, λ1 := start.Latitude.Radians(), start.Longitude.Radians()
+	θ := bearing.Radians()
+	δ := Radians(distanceNm / EarthRadiusNm)
+
+	φ2 := Radians(math.Asin(sin(φ1)*cos(δ) + cos(φ1)*sin(δ)*cos(θ)))
+	λ2 := λ1 + Radians(math.Atan2(sin(θ)*sin(δ)*cos(φ1), cos(δ)-sin(φ1)*sin(φ2)))
+	// From a pole every course runs down a meridian, which the formula above
+	// loses to rounding.
+	if cos(φ1) < 1e-12 {
+		if φ1 > 0 {
+			λ2 = λ1 + math.Pi - θ
+		} else {
+			λ2 = λ1 + θ
+		}
+	}
+
+	return Position{Latitude: φ2.Degrees(), Longitude: normalizeLongitude(λ2.Degrees())}
+}
+
+// Interpolate returns the position a given fraction of the way along the
+// great circle from one position to another.
+func Interpolate(from, to Position, fraction float64) Position {
+	δ := centralAngle(from, to)
+	if δ == 0 {
+		return from
+	}
+	// Every great circle joins antipodal positions; follow the one on the
+	// initial bearing rather than dividing by sin δ ≈ 0.
+	if sin(δ) < 1e-9 {
+		return Destination(from, InitialBearing(from, to), fraction*float64(δ)*EarthRadiusNm)
+	}
+
+	φ1, λ1 := from.Latitude.Radians(), from.Longitude.Radians()
+	φ2, λ2 := to.Latitude.Radians(), to.Longitude.Radians()
+
+	a := sin(Radians(1-fraction)*δ) / sin(δ)
+	b := sin(Radians(fraction)*δ) / sin(δ)
+
+	x := a*cos(φ1)*cos(λ1) + b*cos(φ2)*cos(λ2)
+	y := a*cos(φ1)*sin(λ1) + b*cos(φ2)*sin(λ2)
+	z := a*sin(φ1) + b*sin(φ2)
+
+	return Position{
+		Latitude:  Radians(math.Atan2(z, math.Sqrt(x*x+y*y))).Degrees(),
+		Longitude: normalizeLongitude(Radians(math.Atan2(y, x)).Degrees()),
+	}
+}
+
+// normalizeLongitude wraps a longitude into [-180, 180).
+func normalizeLongitude(long Degrees) Degrees {
+	return (long + 180).Normalized() - 180
+}
+
+// Track is the planned great-circle route between two positions. Following
+// a track by distance flown, rather than repeatedly re-aiming at the
+// destination from the current position, keeps the flown path on the route.
+type Track struct {
+	Origin      Position
+	Destination Position
+	length      float64
+}
+
+// NewTrack plans the great-circle track from origin to destination.
+func NewTrack(origin, destination Position) Track {
+	return Track{Origin: origin, Destination: destination, length: Distance(origin, destination)}
+}
+
+// Length returns the length of the track in nautical miles.
+func (t Track) Length() float64 {
+	return t.length
+}
+
+// PositionAt returns the position after flying distanceNm along the track,
+// clamped to its ends.
+func (t Track) PositionAt(distanceNm float64) Position {
+	switch {
+	case t.length == 0 || distanceNm <= 0:
+		return t.Origin
+	case distanceNm >= t.length:
+		return t.Destination
+	}
+	return Interpolate(t.Origin, t.Destination, distanceNm/t.length)
+}
+
+// CourseAt returns the true course being flown after distanceNm along the
+// track.
+func (t Track) CourseAt(distanceNm float64) Degrees {
+	if distanceNm >= t.length {
+		return InitialBearing(t.PositionAt(t.length-1), t.Destination)
+	}
+	return InitialBearing(t.PositionAt(distanceNm), t.Destination)
+}
@@ -0,0 +1,132 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+// The reference track is the LAX–JFK example worked in Ed Williams'
+// Aviation Formulary, whose results are given as angles: distances there
+// are in minutes of arc, so they are converted to this package's radius.
+var (
+	lax = Position{Latitude: 33 + 57.0/60, Longitude: -(118 + 24.0/60)}
+	jfk = Position{Latitude: 40 + 38.0/60, Longitude: -(73 + 47.0/60)}
+)
+
+// arcNm converts nautical miles measured as minutes of arc to nautical
+// miles on a sphere of EarthRadiusNm.
+func arcNm(nm float64) float64 {
+	return nm / (180 * 60 / math.Pi) * EarthRadiusNm
+}
+
+func near(got, want Position, tolDeg float64) bool {
+	return math.Abs(float64(got.Latitude-want.Latitude)) <= tolDeg &&
+		math.Abs(float64((got.Longitude-want.Longitude).Signed())) <= tolDeg
+}
+
+func TestReferenceTrack(t *testing.T) {
+	track := NewTrack(lax, jfk)
+
+	// d = 0.623585 rad, initial course 66°.
+	if got, want := track.Length(), 0.623585*EarthRadiusNm; math.Abs(got-want) > 0.01 {
+		t.Errorf("length = %.3f nm, want %.3f", got, want)
+	}
+	if got := track.CourseAt(0); math.Abs(float64(got-65.892)) > 0.001 {
+		t.Errorf("initial course = %.4f°, want 65.892°", got)
+	}
+
+	for _, tc := range []struct {
+		name  string
+		along float64
+		want  Position
+	}{
+		// 100 nm from LAX: 34°37'N 116°33'W.
+		{"100nm", arcNm(100), Position{Latitude: 34.616973, Longitude: -116.551391}},
+		// 40% of the way: 38°40.167'N 101°37.570'W.
+		{"f0.4", 0.4 * track.Length(), Position{Latitude: 38 + 40.167/60, Longitude: -(101 + 37.570/60)}},
+		{"origin", 0, lax},
+		{"destination", track.Length(), jfk},
+		{"past destination", track.Length() + 50, jfk},
+	} {
+		if got := track.PositionAt(tc.along); !near(got, tc.want, 1e-4) {
+			t.Errorf("%s: position = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+// TestTrackFollowing steps along tracks the way a flight does, flying each
+// step on the course the track gives, and checks the path stays on the
+// great circle rather than drifting as re-aiming from the current position
+// would.
+func TestTrackFollowing(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		from, to Position
+	}{
+		{"LAX-JFK", lax, jfk},
+		{"JFK-LHR", jfk, Position{Latitude: 51.4706, Longitude: -0.461941}},
+		{"SYD-SCL", Position{Latitude: -33.946098, Longitude: 151.177002}, Position{Latitude: -33.393002, Longitude: -70.785797}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			track := NewTrack(tc.from, tc.to)
+			const step = 5.0 // nm, about 20 seconds of cruise
+			var worst float64
+			for d := 0.0; d+step <= track.Length(); d += step {
+				next := Destination(track.PositionAt(d), track.CourseAt(d), step)
+				if off := Distance(next, track.PositionAt(d+step)); off > worst {
+					worst = off
+				}
+				if xt := math.Abs(CrossTrackDistance(tc.from, tc.to, track.PositionAt(d))); xt > 1e-6 {
+					t.Fatalf("%.0f nm along, point is %.2g nm off the great circle", d, xt)
+				}
+			}
+			if worst > 0.01 {
+				t.Errorf("a %.0f nm step on the track's course lands up to %.4f nm from the track", step, worst)
+			}
+		})
+	}
+}
+
+func TestInterpolateEnds(t *testing.T) {
+	for _, f := range []float64{0, 0.25, 0.5, 1} {
+		got := Interpolate(lax, jfk, f)
+		want := Distance(lax, jfk) * f
+		if d := Distance(lax, got); math.Abs(d-want) > 1e-6 {
+			t.Errorf("fraction %v: %.6f nm from origin, want %.6f", f, d, want)
+		}
+	}
+}
+
+func BenchmarkDistance(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Distance(lax, jfk)
+	}
+}
+
+func BenchmarkDestination(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Destination(lax, 65.892, 100)
+	}
+}
+
+// BenchmarkTrack measures what a flight asks of its route on every step.
+func BenchmarkTrack(b *testing.B) {
+	track := NewTrack(lax, jfk)
+	along := track.Length() / 3
+	p := Destination(track.PositionAt(along), track.CourseAt(along)+90, 0.2)
+	b.Run("PositionAt", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			track.PositionAt(along)
+		}
+	})
+	b.Run("CourseAt", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			track.CourseAt(along)
+		}
+	})
+	b.Run("Deviation", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			track.Deviation(p, 70)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package geo
+
+import "math"
+
+// StandardRateRadius returns the radius in nautical miles of a standard
+// rate (3° per second) turn at the given true airspeed in knots.
+func StandardRateRadius(speedKnots float64) float64 {
+	return speedKnots / (60 * math.Pi)
+}
+
+// Hold is a racetrack holding pattern: an inbound leg ending at the fix,
+// a 180° turn, an outbound leg parallel to it, and a turn back onto the
+// inbound leg.
+type Hold struct {
+	Fix           Position
+	InboundCourse Degrees
+	LegNm         float64
+	TurnRadiusNm  float64
+	LeftTurns     bool
+}
+
+// NewHold returns a standard hold at fix for an aircraft flying at
+// speedKnots: right turns at standard rate and one-minute legs.
+func NewHold(fix Position, inboundCourse Degrees, speedKnots float64) Hold {
+	return Hold{
+		Fix:           fix,
+		InboundCourse: inboundCourse,
+		LegNm:         speedKnots / 60,
+		TurnRadiusNm:  StandardRateRadius(speedKnots),
+	}
+}
+
+// CircuitNm returns the distance flown in one full circuit of the hold.
+func (h Hold) CircuitNm() float64 {
+	return 2*h.LegNm + 2*math.Pi*h.TurnRadiusNm
+}
+
+// PositionAt returns the position and course after flying distanceNm
+// around the hold, starting at the beginning of the inbound leg. The
+// pattern repeats every CircuitNm.
+func (h Hold) PositionAt(distanceNm float64) (Position, Degrees) {
+	l, r := h.LegNm, h.TurnRadiusNm
+	turn := math.Pi * r
+	d := math.Mod(distanceNm, h.CircuitNm())
+	if d < 0 {
+		d += h.CircuitNm()
+	}
+
+	// x runs along the inbound course with the fix at the origin, and y is
+	// offset towards the side the turns are made on.
+	var x, y float64
+	var course Degrees
+	switch {
+	case d < l:
+		x, y = -l+d, 0
+		course = 0
+	case d < l+turn:
+		a := (d - l) / r
+		x, y = r*math.Sin(a), r-r*math.Cos(a)
+		course = Radians(a).Degrees()
+	case d < 2*l+turn:
+		x, y = -(d - l - turn), 2*r
+		course = 180
+	default:
+		a := (d - 2*l - turn) / r
+		x, y = -l-r*math.Sin(a), r+r*math.Cos(a)
+		course = 180 + Radians(a).Degrees()
+	}
+
+	if h.LeftTurns {
+		y = -y
+		course = -course
+	}
+
+	pos := h.Fix
+	if dist := math.Hypot(x, y); dist > 0 {
+		pos = Destination(h.Fix, h.InboundCourse+Radians(math.Atan2(y, x)).Degrees(), dist)
+	}
+	return pos, (h.InboundCourse + course).Normalized()
+}
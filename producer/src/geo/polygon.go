@@ -0,0 +1,21 @@
+package geo
+
+// Polygon is a closed ring of positions; the last vertex connects back to
+// the first. Edges are treated as straight lines in latitude/longitude,
+// which is accurate enough for the small areas it is used for.
+type Polygon []Position
+
+// Contains reports whether p lies inside the polygon, using ray casting.
+func (poly Polygon) Contains(p Position) bool {
+	inside := false
+	for i, j := 0, len(poly)-1; i < len(poly); j, i = i, i+1 {
+		a, b := poly[i], poly[j]
+		if (a.Latitude > p.Latitude) != (b.Latitude > p.Latitude) {
+			crossing := a.Longitude + (p.Latitude-a.Latitude)*(b.Longitude-a.Longitude)/(b.Latitude-a.Latitude)
+			if p.Longitude < crossing {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
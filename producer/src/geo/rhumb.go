@@ -0,0 +1,134 @@
+package geo
+
+import "math"
+
+// rhumbStretch returns the difference in Mercator-projected latitude between
+// two latitudes and the q factor that converts longitude differences into
+// distances along the rhumb line between them.
+func rhumbStretch(φ1, φ2 Radians) (Δψ, q float64) {
+	// The poles are infinitely far up the projection, which tan only
+	// approximates: every rhumb line to or from one runs due north or south.
+	if cos(φ1) < 1e-12 || cos(φ2) < 1e-12 {
+		return math.Copysign(math.Inf(1), float64(φ2-φ1)), 0
+	}
+	Δψ = math.Log(math.Tan(math.Pi/4+float64(φ2)/2) / math.Tan(math.Pi/4+float64(φ1)/2))
+	if math.Abs(Δψ) > 1e-12 {
+		return Δψ, float64(φ2-φ1) / Δψ
+	}
+	// Due east or west, where the ratio is ill-conditioned.
+	return Δψ, cos(φ1)
+}
+
+// shortestΔλ returns the longitude difference from one position to another,
+// taking the shorter way around.
+func shortestΔλ(from, to Position) Radians {
+	return normalizeLongitude(to.Longitude - from.Longitude).Radians()
+}
+
+// RhumbDistance returns the distance in nautical miles along the rhumb line
+// (loxodrome) between two positions: the path of constant true course.
+func RhumbDistance(from, to Position) float64 {
+	φ1, φ2 := from.Latitude.Radians(), to.Latitude.Radians()
+	_, q := rhumbStretch(φ1, φ2)
+	Δφ, Δλ := float64(φ2-φ1), float64(shortestΔλ(from, to))
+	return EarthRadiusNm * math.Sqrt(Δφ*Δφ+q*q*Δλ*Δλ)
+}
+
+// RhumbBearing returns the constant true course in [0, 360) of the rhumb
+// line from one position to another.
+func RhumbBearing(from, to Position) Degrees {
+	Δψ, _ := rhumbStretch(from.Latitude.Radians(), to.Latitude.Radians())
+	return Radians(math.Atan2(float64(shortestΔλ(from, to)), Δψ)).Degrees().Normalized()
+}
+
+// RhumbDestination returns the position reached by flying distanceNm from
+// start on a constant true course.
+func RhumbDestination(start Position, bearing Degrees, distanceNm float64) Position {
+	φ1, λ1 := start.Latitude.Radians(), start.Longitude.Radians()
+	θ := bearing.Radians()
+	δ := distanceNm / EarthRadiusNm
+
+	φ2 := φ1 + Radians(δ*cos(θ))
+	// Flying over a pole comes back down the other side.
+	if math.Abs(float64(φ2)) > math.Pi/2 {
+		if φ2 > 0 {
+			φ2 = math.Pi - φ2
+		} else {
+			φ2 = -math.Pi - φ2
+		}
+	}
+	// Due north or south, or to or from a pole, the longitude never changes,
+	// which the stretch cannot say when it is zero.
+	λ2 := λ1
+	if _, q := rhumbStretch(φ1, φ2); q != 0 && math.Abs(sin(θ)) > 1e-15 {
+		λ2 += Radians(δ * sin(θ) / q)
+	}
+
+	return Position{Latitude: φ2.Degrees(), Longitude: normalizeLongitude(λ2.Degrees())}
+}
+
+// RhumbTrack is a planned route flown on a constant true course. It is
+// longer than the great circle except along the equator or a meridian, but
+// needs no heading changes.
+type RhumbTrack struct {
+	Origin      Position
+	Destination Position
+	length      float64
+	course      Degrees
+}
+
+// NewRhumbTrack plans the rhumb line from origin to destination.
+func NewRhumbTrack(origin, destination Position) RhumbTrack {
+	return RhumbTrack{
+		Origin:      origin,
+		Destination: destination,
+		length:      RhumbDistance(origin, destination),
+		course:      RhumbBearing(origin, destination),
+	}
+}
+
+// Length returns the length of the track in nautical miles.
+func (t RhumbTrack) Length() float64 {
+	return t.length
+}
+
+// PositionAt returns the position after flying distanceNm along the track,
+// clamped to its ends.
+func (t RhumbTrack) PositionAt(distanceNm float64) Position {
+	switch {
+	case t.length == 0 || distanceNm <= 0:
+		return t.Origin
+	case distanceNm >= t.length:
+		return t.Destination
+	}
+	// Every rhumb line from a pole is a meridian, and the origin's
+	// longitude says nothing about which: it is the destination's.
+	start := t.Origin
+	if math.Abs(float64(start.Latitude)) == 90 {
+		start.Longitude = t.Destination.Longitude
+	}
+	return RhumbDestination(start, t.course, distanceNm)
+}
+
+// CourseAt returns the true course, which is the same everywhere on a rhumb
+// line.
+func (t RhumbTrack) CourseAt(float64) Degrees {
+	return t.course
+}
+
+// Deviation is Track.Deviation for a rhumb line. Distances off the line are
+// measured along the rhumb line from the origin, which is accurate for the
+// small deviations it is used for.
+func (t RhumbTrack) Deviation(p Position, heading Degrees) (trackErrorDeg Degrees, crossTrackNm float64) {
+	if t.length == 0 {
+		return 0, 0
+	}
+	d := RhumbDistance(t.Origin, p)
+	angle := (RhumbBearing(t.Origin, p) - t.course).Radians()
+
+	trackErrorDeg = (heading - t.course).Normalized()
+	if trackErrorDeg > 180 {
+		trackErrorDeg -= 360
+	}
+	return trackErrorDeg, d * sin(angle)
+}
@@ -0,0 +1,63 @@
+package geo
+
+import "fmt"
+
+// Route is a planned path from origin to destination that a flight follows
+// by distance flown.
+type Route interface {
+	Length() float64
+	PositionAt(distanceNm float64) Position
+	CourseAt(distanceNm float64) Degrees
+	Deviation(p Position, heading Degrees) (trackErrorDeg Degrees, crossTrackNm float64)
+}
+
+// Navigation is how a route between two points is planned.
+type Navigation string
+
+const (
+	// GreatCircle flies the shortest path, with a continually changing
+	// course.
+	GreatCircle Navigation = "greatCircle"
+	// Rhumb flies a constant true course.
+	Rhumb Navigation = "rhumb"
+)
+
+// ParseNavigation validates a navigation mode name, defaulting to
+// GreatCircle when it is empty.
+func ParseNavigation(name string) (Navigation, error) {
+	switch n := Navigation(name); n {
+	case "":
+		return GreatCircle, nil
+	case GreatCircle, Rhumb:
+		return n, nil
+	default:
+		return "", fmt.Errorf("unknown navigation mode %q", name)
+	}
+}
+
+// NewRoute plans a route from origin to destination on the given Earth
+// model. Rhumb lines are always planned on the sphere.
+func (n Navigation) NewRoute(model EarthModel, origin, destination Position) Route {
+	switch {
+	case n == Rhumb:
+		return NewRhumbTrack(origin, destination)
+	case model == WGS84:
+		return NewGeodesicTrack(origin, destination)
+	}
+	return NewTrack(origin, destination)
+}
+
+// Sample returns n evenly spaced positions along a route, from its origin
+// to its destination inclusive. n is at least 2.
+func Sample(r Route, n int) []Position {
+	if n < 2 {
+		n = 2
+	}
+	points := make([]Position, n)
+	for i := range points {
+		// Scaling the fraction, not the length, makes the last point exactly
+		// the destination.
+		points[i] = r.PositionAt(r.Length() * (float64(i) / float64(n-1)))
+	}
+	return points
+}
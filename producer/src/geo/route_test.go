@@ -0,0 +1,193 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+var (
+	sea = Position{Latitude: 47.449001, Longitude: -122.308998}
+	icn = Position{Latitude: 37.469101, Longitude: 126.450996}
+)
+
+// awkwardRoutes are the cases planar arithmetic on latitude and longitude
+// gets wrong: crossing the antimeridian, joining antipodes and starting at a
+// pole.
+var awkwardRoutes = []struct {
+	name     string
+	from, to Position
+}{
+	{"SEA-ICN", sea, icn},
+	{"ICN-SEA", icn, sea},
+	{"NRT-SFO", Position{Latitude: 35.764702, Longitude: 140.386002}, Position{Latitude: 37.618999, Longitude: -122.375}},
+	{"SYD-SCL", Position{Latitude: -33.946098, Longitude: 151.177002}, Position{Latitude: -33.393002, Longitude: -70.785797}},
+	{"antimeridian east", Position{Latitude: 10, Longitude: 179.5}, Position{Latitude: 12, Longitude: -179.5}},
+	{"antimeridian west", Position{Latitude: -10, Longitude: -179.5}, Position{Latitude: -12, Longitude: 179.5}},
+	{"along the antimeridian", Position{Latitude: -40, Longitude: 180}, Position{Latitude: 40, Longitude: -180}},
+	{"antipodes", Position{Latitude: 0, Longitude: 0}, Position{Latitude: 0, Longitude: 180}},
+	{"antipodes off the equator", Position{Latitude: 30, Longitude: 20}, Position{Latitude: -30, Longitude: -160}},
+	{"from the north pole", Position{Latitude: 90, Longitude: 0}, jfk},
+	{"from the south pole", Position{Latitude: -90, Longitude: 45}, Position{Latitude: -33.946098, Longitude: 151.177002}},
+	{"to the north pole", jfk, Position{Latitude: 90, Longitude: 0}},
+	{"pole to pole", Position{Latitude: 90, Longitude: 0}, Position{Latitude: -90, Longitude: 0}},
+}
+
+// TestAwkwardRoutes samples every awkward route with each planner and checks
+// the path is a valid, continuous line between the right ends: a step of
+// the wrong sign in longitude or a wrapped angle shows up as a jump between
+// consecutive samples.
+func TestAwkwardRoutes(t *testing.T) {
+	planners := []struct {
+		name string
+		plan func(from, to Position) Route
+	}{
+		{"great circle", func(from, to Position) Route { return NewTrack(from, to) }},
+		{"rhumb", func(from, to Position) Route { return NewRhumbTrack(from, to) }},
+		{"geodesic", NewGeodesicTrack},
+	}
+	for _, tc := range awkwardRoutes {
+		for _, p := range planners {
+			t.Run(tc.name+"/"+p.name, func(t *testing.T) {
+				route := p.plan(tc.from, tc.to)
+				if math.IsNaN(route.Length()) || route.Length() <= 0 {
+					t.Fatalf("length = %v", route.Length())
+				}
+
+				const samples = 400
+				step := route.Length() / samples
+				points := Sample(route, samples+1)
+				for i, pt := range points {
+					if err := pt.Validate(); err != nil {
+						t.Fatalf("sample %d: %v", i, err)
+					}
+					if i == 0 {
+						continue
+					}
+					// Distances on the sphere differ from the ellipsoid's
+					// by well under 1%.
+					if d := Distance(points[i-1], pt); math.Abs(d-step) > 0.01*step {
+						t.Fatalf("sample %d: %.2f nm from the last, want %.2f", i, d, step)
+					}
+					if c := route.CourseAt(step * float64(i)); math.IsNaN(float64(c)) || c < 0 || c >= 360 {
+						t.Fatalf("sample %d: course %v", i, c)
+					}
+				}
+				if points[0] != tc.from || points[samples] != tc.to {
+					t.Errorf("ends = %v, %v, want %v, %v", points[0], points[samples], tc.from, tc.to)
+				}
+			})
+		}
+	}
+}
+
+// TestSeattleIncheon checks the great circle from SEA to ICN against values
+// computed independently of the track: the distance, the westbound initial
+// course, a single crossing of the antimeridian, and the highest latitude
+// given by Clairaut's relation, cos φmax = |sin θ cos φ|.
+func TestSeattleIncheon(t *testing.T) {
+	track := NewTrack(sea, icn)
+	// 8 362 km, as published for the route.
+	if got := track.Length() * metresPerNm / 1000; math.Abs(got-8362) > 0.01*8362 {
+		t.Errorf("length = %.0f km, want about 8362", got)
+	}
+	course := track.CourseAt(0)
+	if course < 270 || course > 360 {
+		t.Errorf("initial course = %.1f°, want north-west", course)
+	}
+
+	vertex := Radians(math.Acos(math.Abs(sin(course.Radians()) * cos(sea.Latitude.Radians())))).Degrees()
+	var highest Degrees
+	crossings := 0
+	points := Sample(track, 2001)
+	for i, p := range points {
+		highest = max(highest, p.Latitude)
+		if i > 0 && points[i-1].Longitude < 0 && p.Longitude > 0 {
+			crossings++
+		}
+	}
+	if crossings != 1 {
+		t.Errorf("crossed the antimeridian %d times, want once", crossings)
+	}
+	if math.Abs(float64(highest-vertex)) > 0.01 {
+		t.Errorf("highest latitude = %.3f°, want %.3f°", highest, vertex)
+	}
+}
+
+// TestAntipodes checks that the great circle between antipodes, which is
+// any of infinitely many, is a single consistent one: halfway along is a
+// quarter of the way round the Earth from both ends.
+func TestAntipodes(t *testing.T) {
+	for _, tc := range awkwardRoutes {
+		if Distance(tc.from, tc.to) < math.Pi*EarthRadiusNm-1e-6 {
+			continue
+		}
+		t.Run(tc.name, func(t *testing.T) {
+			track := NewTrack(tc.from, tc.to)
+			mid := track.PositionAt(track.Length() / 2)
+			quarter := math.Pi / 2 * EarthRadiusNm
+			if a, b := Distance(tc.from, mid), Distance(mid, tc.to); math.Abs(a-quarter) > 1e-6 || math.Abs(b-quarter) > 1e-6 {
+				t.Errorf("midpoint %v is %.6f and %.6f nm from the ends, want %.6f", mid, a, b, quarter)
+			}
+		})
+	}
+}
+
+// TestPolarOrigins checks that every course from a pole runs down a single
+// meridian, the one it names.
+func TestPolarOrigins(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		pole   Position
+		course Degrees
+		want   Degrees
+	}{
+		// From the north pole, course θ runs down longitude λ + 180 - θ.
+		{"north due south", Position{Latitude: 90, Longitude: 0}, 180, 0},
+		{"north course 90", Position{Latitude: 90, Longitude: 0}, 90, 90},
+		{"north course 254", Position{Latitude: 90, Longitude: 0}, 254, -74},
+		// From the south pole, course θ runs up longitude λ + θ.
+		{"south due north", Position{Latitude: -90, Longitude: 45}, 0, 45},
+		{"south course 106", Position{Latitude: -90, Longitude: 45}, 106, 151},
+		{"south course 180", Position{Latitude: -90, Longitude: 0}, 180, -180},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, nm := range []float64{1, 100, 3000} {
+				got := Destination(tc.pole, tc.course, nm)
+				if d := math.Abs(float64((got.Longitude - tc.want).Signed())); d > 1e-9 {
+					t.Errorf("%v nm: longitude = %v, want %v", nm, got.Longitude, tc.want)
+				}
+				if d := math.Abs(Distance(tc.pole, got) - nm); d > 1e-6 {
+					t.Errorf("%v nm: %.6f nm from the pole", nm, Distance(tc.pole, got))
+				}
+			}
+		})
+	}
+
+	// A track from the pole therefore follows the destination's meridian.
+	track := NewTrack(Position{Latitude: 90, Longitude: 0}, jfk)
+	for _, p := range Sample(track, 50)[1:] {
+		if d := math.Abs(float64((p.Longitude - jfk.Longitude).Signed())); d > 1e-6 {
+			t.Fatalf("%v is off the meridian of %v", p, jfk.Longitude)
+		}
+	}
+}
+
+// BenchmarkRoutes compares the cost of following each kind of route.
+func BenchmarkRoutes(b *testing.B) {
+	for _, tc := range []struct {
+		name  string
+		route Route
+	}{
+		{"great circle", NewTrack(sea, icn)},
+		{"rhumb", NewRhumbTrack(sea, icn)},
+		{"geodesic", NewGeodesicTrack(sea, icn)},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			along := tc.route.Length() / 3
+			for i := 0; i < b.N; i++ {
+				tc.route.PositionAt(along)
+				tc.route.CourseAt(along)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package geo
+
+import "math"
+
+// Simplify reduces a track with the Douglas–Peucker algorithm, returning the
+// indices of the points to keep: the first and last, and every point needed
+// to stay within toleranceNm of the original track. Distances to each
+// segment are measured on a flat projection centred on it, which is accurate
+// for the closely spaced points of a recorded track.
+func Simplify(track []Position, toleranceNm float64) []int {
+	if len(track) <= 2 {
+		keep := make([]int, len(track))
+		for i := range keep {
+			keep[i] = i
+		}
+		return keep
+	}
+
+	keep := make([]bool, len(track))
+	keep[0], keep[len(track)-1] = true, true
+
+	// An explicit stack avoids deep recursion on long tracks.
+	type span struct{ first, last int }
+	stack := []span{{0, len(track) - 1}}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		farthest, maxDist := -1, toleranceNm
+		for i := s.first + 1; i < s.last; i++ {
+			if d := segmentDistance(track[i], track[s.first], track[s.last]); d > maxDist {
+				farthest, maxDist = i, d
+			}
+		}
+		if farthest < 0 {
+			continue
+		}
+		keep[farthest] = true
+		stack = append(stack, span{s.first, farthest}, span{farthest, s.last})
+	}
+
+	var indices []int
+	for i, k := range keep {
+		if k {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// segmentDistance returns the distance in nautical miles from p to the
+// segment a–b, on an equirectangular projection around a.
+func segmentDistance(p, a, b Position) float64 {
+	scale := math.Cos(float64(a.Latitude.Radians()))
+	project := func(q Position) (x, y float64) {
+		Δλ := normalizeLongitude(q.Longitude - a.Longitude)
+		return float64(Δλ) * scale * 60, float64(q.Latitude-a.Latitude) * 60
+	}
+
+	px, py := project(p)
+	bx, by := project(b)
+
+	t := 0.0
+	if l2 := bx*bx + by*by; l2 > 0 {
+		t = math.Max(0, math.Min(1, (px*bx+py*by)/l2))
+	}
+	return math.Hypot(px-t*bx, py-t*by)
+}
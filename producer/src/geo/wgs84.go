@@ -0,0 +1,179 @@
+package geo
+
+import "math"
+
+// WGS84 ellipsoid parameters.
+const (
+	wgs84A = 6378137.0
+	wgs84F = 1 / 298.257223563
+	wgs84B = (1 - wgs84F) * wgs84A
+
+	metresPerNm = 1852.0
+
+	vincentyTolerance     = 1e-12
+	vincentyMaxIterations = 200
+)
+
+// VincentyInverse returns the distance in nautical miles along the WGS84
+// geodesic between two positions and the initial and final true courses,
+// using Vincenty's inverse formula. It reports false if the iteration fails
+// to converge, which happens for nearly antipodal positions.
+func VincentyInverse(from, to Position) (distanceNm float64, initial, final Degrees, ok bool) {
+	L := float64((to.Longitude - from.Longitude).Radians())
+	U1 := math.Atan((1 - wgs84F) * math.Tan(float64(from.Latitude.Radians())))
+	U2 := math.Atan((1 - wgs84F) * math.Tan(float64(to.Latitude.Radians())))
+	sinU1, cosU1 := math.Sincos(U1)
+	sinU2, cosU2 := math.Sincos(U2)
+
+	var sinσ, cosσ, σ, cos2α, cos2σm, sinλ, cosλ float64
+	λ := L
+	for i := 0; ; i++ {
+		if i == vincentyMaxIterations {
+			return 0, 0, 0, false
+		}
+		sinλ, cosλ = math.Sincos(λ)
+		sinσ = math.Hypot(cosU2*sinλ, cosU1*sinU2-sinU1*cosU2*cosλ)
+		if sinσ == 0 {
+			return 0, 0, 0, true
+		}
+		cosσ = sinU1*sinU2 + cosU1*cosU2*cosλ
+		σ = math.Atan2(sinσ, cosσ)
+		sinα := cosU1 * cosU2 * sinλ / sinσ
+		cos2α = 1 - sinα*sinα
+		cos2σm = 0
+		if cos2α != 0 {
+			// Zero on equatorial lines.
+			cos2σm = cosσ - 2*sinU1*sinU2/cos2α
+		}
+		C := wgs84F / 16 * cos2α * (4 + wgs84F*(4-3*cos2α))
+		prev := λ
+		λ = L + (1-C)*wgs84F*sinα*(σ+C*sinσ*(cos2σm+C*cosσ*(-1+2*cos2σm*cos2σm)))
+		if math.Abs(λ-prev) < vincentyTolerance {
+			break
+		}
+	}
+
+	A, B := vincentyAB(cos2α)
+	Δσ := vincentyΔσ(B, sinσ, cosσ, cos2σm)
+	s := wgs84B * A * (σ - Δσ)
+
+	α1 := math.Atan2(cosU2*sinλ, cosU1*sinU2-sinU1*cosU2*cosλ)
+	α2 := math.Atan2(cosU1*sinλ, -sinU1*cosU2+cosU1*sinU2*cosλ)
+	return s / metresPerNm, Radians(α1).Degrees().Normalized(), Radians(α2).Degrees().Normalized(), true
+}
+
+// VincentyDirect returns the position reached by following the WGS84
+// geodesic from start on the given initial true course for distanceNm, and
+// the true course on arrival, using Vincenty's direct formula.
+func VincentyDirect(start Position, bearing Degrees, distanceNm float64) (Position, Degrees) {
+	s := distanceNm * metresPerNm
+	sinα1, cosα1 := math.Sincos(float64(bearing.Radians()))
+
+	tanU1 := (1 - wgs84F) * math.Tan(float64(start.Latitude.Radians()))
+	cosU1 := 1 / math.Sqrt(1+tanU1*tanU1)
+	sinU1 := tanU1 * cosU1
+	σ1 := math.Atan2(tanU1, cosα1)
+	sinα := cosU1 * sinα1
+	cos2α := 1 - sinα*sinα
+	A, B := vincentyAB(cos2α)
+
+	σ := s / (wgs84B * A)
+	var sinσ, cosσ, cos2σm float64
+	for i := 0; i < vincentyMaxIterations; i++ {
+		cos2σm = math.Cos(2*σ1 + σ)
+		sinσ, cosσ = math.Sincos(σ)
+		prev := σ
+		σ = s/(wgs84B*A) + vincentyΔσ(B, sinσ, cosσ, cos2σm)
+		if math.Abs(σ-prev) < vincentyTolerance {
+			break
+		}
+	}
+	cos2σm = math.Cos(2*σ1 + σ)
+	sinσ, cosσ = math.Sincos(σ)
+
+	x := sinU1*sinσ - cosU1*cosσ*cosα1
+	φ2 := math.Atan2(sinU1*cosσ+cosU1*sinσ*cosα1, (1-wgs84F)*math.Hypot(sinα, x))
+	λ := math.Atan2(sinσ*sinα1, cosU1*cosσ-sinU1*sinσ*cosα1)
+	C := wgs84F / 16 * cos2α * (4 + wgs84F*(4-3*cos2α))
+	L := λ - (1-C)*wgs84F*sinα*(σ+C*sinσ*(cos2σm+C*cosσ*(-1+2*cos2σm*cos2σm)))
+
+	end := Position{
+		Latitude:  Radians(φ2).Degrees(),
+		Longitude: normalizeLongitude(start.Longitude + Radians(L).Degrees()),
+	}
+	return end, Radians(math.Atan2(sinα, -x)).Degrees().Normalized()
+}
+
+func vincentyAB(cos2α float64) (A, B float64) {
+	u2 := cos2α * (wgs84A*wgs84A - wgs84B*wgs84B) / (wgs84B * wgs84B)
+	A = 1 + u2/16384*(4096+u2*(-768+u2*(320-175*u2)))
+	B = u2 / 1024 * (256 + u2*(-128+u2*(74-47*u2)))
+	return A, B
+}
+
+func vincentyΔσ(B, sinσ, cosσ, cos2σm float64) float64 {
+	return B * sinσ * (cos2σm + B/4*(cosσ*(-1+2*cos2σm*cos2σm)-
+		B/6*cos2σm*(-3+4*sinσ*sinσ)*(-3+4*cos2σm*cos2σm)))
+}
+
+// GeodesicTrack is the shortest route between two positions on the WGS84
+// ellipsoid, the ellipsoidal counterpart of Track.
+type GeodesicTrack struct {
+	Origin      Position
+	Destination Position
+	length      float64
+	course      Degrees
+}
+
+// NewGeodesicTrack plans the WGS84 geodesic from origin to destination,
+// falling back to the great circle for nearly antipodal positions where
+// Vincenty's formula does not converge.
+func NewGeodesicTrack(origin, destination Position) Route {
+	length, course, _, ok := VincentyInverse(origin, destination)
+	if !ok {
+		return NewTrack(origin, destination)
+	}
+	return GeodesicTrack{Origin: origin, Destination: destination, length: length, course: course}
+}
+
+// Length returns the length of the track in nautical miles.
+func (t GeodesicTrack) Length() float64 {
+	return t.length
+}
+
+// PositionAt returns the position after flying distanceNm along the track,
+// clamped to its ends.
+func (t GeodesicTrack) PositionAt(distanceNm float64) Position {
+	switch {
+	case t.length == 0 || distanceNm <= 0:
+		return t.Origin
+	case distanceNm >= t.length:
+		return t.Destination
+	}
+	p, _ := VincentyDirect(t.Origin, t.course, distanceNm)
+	return p
+}
+
+// CourseAt returns the true course being flown after distanceNm along the
+// track.
+func (t GeodesicTrack) CourseAt(distanceNm float64) Degrees {
+	if t.length == 0 {
+		return t.course
+	}
+	_, course := VincentyDirect(t.Origin, t.course, math.Max(0, math.Min(distanceNm, t.length)))
+	return course
+}
+
+// Deviation is Track.Deviation for the geodesic, with the cross-track
+// distance measured on the sphere, which is accurate for small deviations.
+func (t GeodesicTrack) Deviation(p Position, heading Degrees) (trackErrorDeg Degrees, crossTrackNm float64) {
+	if t.length == 0 {
+		return 0, 0
+	}
+	along := math.Max(0, math.Min(AlongTrackDistance(t.Origin, t.Destination, p), t.length))
+	trackErrorDeg = (heading - t.CourseAt(along)).Normalized()
+	if trackErrorDeg > 180 {
+		trackErrorDeg -= 360
+	}
+	return trackErrorDeg, CrossTrackDistance(t.Origin, t.Destination, p)
+}
@@ -0,0 +1,171 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func dms(d, m, s float64) Degrees {
+	return Degrees(d + m/60 + s/3600)
+}
+
+// geodesics are published WGS84 solutions: the Flinders Peak–Buninyong line
+// Geoscience Australia works with Vincenty's formulae (on GRS80, which is
+// the same ellipsoid to well under a millimetre here), and lines from the
+// GeographicLib documentation, computed with Karney's algorithm to
+// nanometre accuracy.
+var geodesics = []struct {
+	name           string
+	from, to       Position
+	metres         float64
+	initial, final Degrees
+	// tolerances for distance in metres and courses in degrees.
+	tolM, tolDeg float64
+}{
+	{
+		name:    "Flinders Peak-Buninyong",
+		from:    Position{Latitude: -dms(37, 57, 3.72030), Longitude: dms(144, 25, 29.52440)},
+		to:      Position{Latitude: -dms(37, 39, 10.15610), Longitude: dms(143, 55, 35.38390)},
+		metres:  54972.271,
+		initial: dms(306, 52, 5.37),
+		final:   dms(127, 10, 25.07) + 180, // the published reverse azimuth
+		tolM:    0.001, tolDeg: 0.01 / 3600,
+	},
+	{
+		name:    "JFK-LHR",
+		from:    Position{Latitude: 40.6, Longitude: -73.8},
+		to:      Position{Latitude: 51.6, Longitude: -0.5},
+		metres:  5551759.400319,
+		initial: 51.198882845579824,
+		final:   107.821776735514248,
+		tolM:    0.0005, tolDeg: 1e-8,
+	},
+	{
+		// Nearly antipodal, where Vincenty needs over a hundred iterations.
+		name:    "Wellington-Salamanca",
+		from:    Position{Latitude: -41.32, Longitude: 174.81},
+		to:      Position{Latitude: 40.96, Longitude: -5.50},
+		metres:  19959679.267353,
+		initial: 161.067669986160,
+		final:   18.825195123247,
+		tolM:    0.001, tolDeg: 1e-7,
+	},
+	{
+		// A quarter of the equator is πa/2.
+		name:    "equator quadrant",
+		from:    Position{Latitude: 0, Longitude: 0},
+		to:      Position{Latitude: 0, Longitude: 90},
+		metres:  10018754.171394,
+		initial: 90,
+		final:   90,
+		tolM:    0.0005, tolDeg: 1e-9,
+	},
+	{
+		// The meridian quadrant, equator to pole.
+		name:    "meridian quadrant",
+		from:    Position{Latitude: 0, Longitude: 10},
+		to:      Position{Latitude: 90, Longitude: 10},
+		metres:  10001965.729,
+		initial: 0,
+		final:   0,
+		tolM:    0.001, tolDeg: 1e-9,
+	},
+}
+
+func TestVincentyInverse(t *testing.T) {
+	for _, g := range geodesics {
+		t.Run(g.name, func(t *testing.T) {
+			nm, initial, final, ok := VincentyInverse(g.from, g.to)
+			if !ok {
+				t.Fatal("did not converge")
+			}
+			if got := nm * metresPerNm; math.Abs(got-g.metres) > g.tolM {
+				t.Errorf("distance = %.6f m, want %.6f ± %g", got, g.metres, g.tolM)
+			}
+			if d := math.Abs(float64((initial - g.initial).Signed())); d > g.tolDeg {
+				t.Errorf("initial course = %.10f°, want %.10f°", initial, g.initial)
+			}
+			if d := math.Abs(float64((final - g.final).Signed())); d > g.tolDeg {
+				t.Errorf("final course = %.10f°, want %.10f°", final, g.final)
+			}
+		})
+	}
+}
+
+func TestVincentyDirect(t *testing.T) {
+	for _, g := range geodesics {
+		t.Run(g.name, func(t *testing.T) {
+			got, final := VincentyDirect(g.from, g.initial, g.metres/metresPerNm)
+			// A millimetre is about 1e-8 degrees of latitude.
+			if d := Distance(got, g.to) * metresPerNm; d > 0.001 {
+				t.Errorf("arrived at %v, %.4f m from %v", got, d, g.to)
+			}
+			if g.to.Latitude == 90 {
+				// The course at the pole is only defined by the meridian
+				// flown up.
+				return
+			}
+			if d := math.Abs(float64((final - g.final).Signed())); d > g.tolDeg {
+				t.Errorf("final course = %.10f°, want %.10f°", final, g.final)
+			}
+		})
+	}
+}
+
+// TestVincentyAntipodal checks that lines where the inverse formula does
+// not converge are reported, and that the WGS84 model then falls back to
+// the sphere rather than returning nonsense.
+func TestVincentyAntipodal(t *testing.T) {
+	from, to := Position{Latitude: 0, Longitude: 0}, Position{Latitude: 0.5, Longitude: 179.7}
+	if _, _, _, ok := VincentyInverse(from, to); ok {
+		t.Fatal("converged on a nearly antipodal line")
+	}
+	if got, want := WGS84.Distance(from, to), Distance(from, to); got != want {
+		t.Errorf("WGS84 distance = %v, want the sphere's %v", got, want)
+	}
+	if _, ok := NewGeodesicTrack(from, to).(Track); !ok {
+		t.Error("geodesic track did not fall back to the great circle")
+	}
+}
+
+// TestSphereError measures the spherical model against the ellipsoid on the
+// reference lines: it is off by no more than the 0.5% the model allows.
+func TestSphereError(t *testing.T) {
+	for _, g := range geodesics {
+		sphere := Distance(g.from, g.to) * metresPerNm
+		if e := math.Abs(sphere-g.metres) / g.metres; e > 0.005 {
+			t.Errorf("%s: sphere is off by %.3f%%", g.name, e*100)
+		}
+	}
+}
+
+func TestGeodesicTrack(t *testing.T) {
+	g := geodesics[1]
+	track := NewGeodesicTrack(g.from, g.to)
+	if got := track.Length() * metresPerNm; math.Abs(got-g.metres) > g.tolM {
+		t.Errorf("length = %.6f m, want %.6f", got, g.metres)
+	}
+	if got := track.PositionAt(track.Length()); got != g.to {
+		t.Errorf("end = %v, want %v", got, g.to)
+	}
+	if d := Distance(track.PositionAt(track.Length()-1e-9), g.to) * metresPerNm; d > 0.001 {
+		t.Errorf("flying the whole track ends %.4f m from the destination", d)
+	}
+	if d := math.Abs(float64((track.CourseAt(track.Length()) - g.final).Signed())); d > g.tolDeg {
+		t.Errorf("final course = %v, want %v", track.CourseAt(track.Length()), g.final)
+	}
+}
+
+func BenchmarkVincentyInverse(b *testing.B) {
+	g := geodesics[1]
+	for i := 0; i < b.N; i++ {
+		VincentyInverse(g.from, g.to)
+	}
+}
+
+func BenchmarkVincentyDirect(b *testing.B) {
+	g := geodesics[1]
+	for i := 0; i < b.N; i++ {
+		VincentyDirect(g.from, g.initial, 1000)
+	}
+}
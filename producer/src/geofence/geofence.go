@@ -0,0 +1,189 @@
+package geofence
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"plane-producer/src/airports"
+	"plane-producer/src/domain"
+	"plane-producer/src/geo"
+)
+
+// Fence is an area aircraft are watched entering and leaving: either a
+// circle of RadiusNm around Center, or a polygon. A circle can be centred on
+// an airport by giving its code instead of a center.
+type Fence struct {
+	ID       string        `json:"id"`
+	Airport  string        `json:"airport,omitempty"`
+	Center   *geo.Position `json:"center,omitempty"`
+	RadiusNm float64       `json:"radiusNm,omitempty"`
+	Area     geo.Polygon   `json:"area,omitempty"`
+}
+
+// Contains reports whether p lies inside the fence.
+func (f Fence) Contains(p geo.Position) bool {
+	if f.Center != nil {
+		return geo.Distance(*f.Center, p) <= f.RadiusNm
+	}
+	return f.Area.Contains(p)
+}
+
+// Load reads fences from a JSON file, resolving airport-centred circles
+// against db.
+func Load(path string, db *airports.Database) ([]Fence, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fences []Fence
+	if err := json.Unmarshal(b, &fences); err != nil {
+		return nil, fmt.Errorf("geofence: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for i := range fences {
+		f := &fences[i]
+		switch {
+		case f.ID == "":
+			return nil, fmt.Errorf("geofence: fence %d has no id", i+1)
+		case seen[f.ID]:
+			return nil, fmt.Errorf("geofence: duplicate fence %q", f.ID)
+		}
+		seen[f.ID] = true
+
+		if f.Airport != "" {
+			a, ok := db.Lookup(f.Airport)
+			if !ok {
+				return nil, fmt.Errorf("geofence: fence %q: unknown airport %q", f.ID, f.Airport)
+			}
+			f.Center = &geo.Position{Latitude: geo.Degrees(a.Latitude), Longitude: geo.Degrees(a.Longitude)}
+		}
+
+		circle, polygon := f.Center != nil, len(f.Area) > 0
+		switch {
+		case circle == polygon:
+			return nil, fmt.Errorf("geofence: fence %q needs either a center or airport and radius, or an area", f.ID)
+		case circle && f.RadiusNm <= 0:
+			return nil, fmt.Errorf("geofence: fence %q needs a positive radiusNm", f.ID)
+		case polygon && len(f.Area) < 3:
+			return nil, fmt.Errorf("geofence: fence %q needs at least 3 vertices", f.ID)
+		}
+	}
+	return fences, nil
+}
+
+// Kind is the type of a geofence event.
+type Kind string
+
+const (
+	Enter Kind = "geofenceEnter"
+	Exit  Kind = "geofenceExit"
+)
+
+// Event records an aircraft crossing a fence boundary. It uses the same
+// abbreviated field names as position reports, with an "event" field that
+// marks it as something other than a report.
+type Event struct {
+	Kind      Kind    `json:"event"`
+	Fence     string  `json:"fence"`
+	TailNum   string  `json:"plane"`
+	FlightID  string  `json:"flight"`
+	Timestamp int64   `json:"time"` // unix milliseconds
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"long"`
+	Altitude  float64 `json:"alt"`
+}
+
+// Time returns the event's timestamp.
+func (e Event) Time() time.Time {
+	return time.UnixMilli(e.Timestamp).UTC()
+}
+
+// recentEvents is how many events a monitor keeps for the API.
+const recentEvents = 1000
+
+// Monitor tracks which fences each flight is inside and turns its reports
+// into enter and exit events. A flight first seen inside a fence enters it.
+type Monitor struct {
+	fences []Fence
+
+	mu     sync.Mutex
+	inside map[string]map[string]bool
+	recent []Event
+}
+
+// NewMonitor creates a monitor for the given fences.
+func NewMonitor(fences []Fence) *Monitor {
+	return &Monitor{fences: fences, inside: make(map[string]map[string]bool)}
+}
+
+// Fences returns the monitored fences.
+func (m *Monitor) Fences() []Fence {
+	return m.fences
+}
+
+// Check updates a flight's position and returns the fences it has entered or
+// left since its previous report.
+func (m *Monitor) Check(r domain.FlightRecord) []Event {
+	if len(m.fences) == 0 {
+		return nil
+	}
+	p := geo.Position{Latitude: geo.Degrees(r.Latitude), Longitude: geo.Degrees(r.Longitude)}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	inside := m.inside[r.FlightID]
+	if inside == nil {
+		inside = make(map[string]bool)
+		m.inside[r.FlightID] = inside
+	}
+
+	var events []Event
+	for _, f := range m.fences {
+		now := f.Contains(p)
+		if now == inside[f.ID] {
+			continue
+		}
+		inside[f.ID] = now
+
+		kind := Exit
+		if now {
+			kind = Enter
+		}
+		events = append(events, Event{
+			Kind:      kind,
+			Fence:     f.ID,
+			TailNum:   r.TailNum,
+			FlightID:  r.FlightID,
+			Timestamp: r.Timestamp,
+			Latitude:  r.Latitude,
+			Longitude: r.Longitude,
+			Altitude:  r.Altitude,
+		})
+	}
+
+	m.recent = append(m.recent, events...)
+	if over := len(m.recent) - recentEvents; over > 0 {
+		m.recent = append(m.recent[:0], m.recent[over:]...)
+	}
+	return events
+}
+
+// Recent returns the retained events after since, oldest first.
+func (m *Monitor) Recent(since time.Time) []Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var events []Event
+	for _, e := range m.recent {
+		if e.Time().After(since) {
+			events = append(events, e)
+		}
+	}
+	return events
+}
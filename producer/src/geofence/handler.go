@@ -0,0 +1,41 @@
+package geofence
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// Handler serves the monitor's fences and recent events:
+//
+//	GET /geofences                 the configured fences
+//	GET /geofences/events?since=T  events after the RFC 3339 time T
+func Handler(m *Monitor) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /geofences", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, m.Fences())
+	})
+	mux.HandleFunc("GET /geofences/events", func(w http.ResponseWriter, r *http.Request) {
+		var since time.Time
+		if s := r.URL.Query().Get("since"); s != "" {
+			t, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "since must be an RFC 3339 time"})
+				return
+			}
+			since = t
+		}
+		events := m.Recent(since)
+		if events == nil {
+			events = []Event{}
+		}
+		writeJSON(w, http.StatusOK, events)
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,150 @@
+package ground
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultGateCount is how many gates an airport without a configured
+// layout gets.
+const DefaultGateCount = 10
+
+// ErrNoGate is returned when every gate at an airport is occupied.
+var ErrNoGate = errors.New("no free gate")
+
+// GateAssignment records an aircraft parked at a gate. The aircraft may not
+// depart on its next leg before ReadyAt.
+type GateAssignment struct {
+	Airport string
+	Gate    string
+	TailNum string
+	Arrived time.Time
+	ReadyAt time.Time
+}
+
+// Gates assigns arriving aircraft to gates and tracks their turnaround.
+type Gates struct {
+	turnaround time.Duration
+
+	mu       sync.Mutex
+	layout   map[string][]string
+	occupied map[string]map[string]GateAssignment // airport -> gate -> assignment
+	byTail   map[string]GateAssignment
+}
+
+// NewGates creates gate tracking for the given layout of gate names per
+// airport, with every aircraft needing turnaround at the gate before its
+// next departure.
+func NewGates(layout map[string][]string, turnaround time.Duration) *Gates {
+	g := &Gates{
+		turnaround: turnaround,
+		layout:     make(map[string][]string),
+		occupied:   make(map[string]map[string]GateAssignment),
+		byTail:     make(map[string]GateAssignment),
+	}
+	for airport, gates := range layout {
+		g.layout[strings.ToUpper(airport)] = gates
+	}
+	return g
+}
+
+func (g *Gates) gatesAt(airport string) []string {
+	gates, ok := g.layout[airport]
+	if !ok {
+		gates = make([]string, DefaultGateCount)
+		for i := range gates {
+			gates[i] = fmt.Sprintf("G%d", i+1)
+		}
+		g.layout[airport] = gates
+	}
+	return gates
+}
+
+// Assign parks an arriving aircraft at the first free gate.
+func (g *Gates) Assign(airport, tailNum string, arrived time.Time) (GateAssignment, error) {
+	return g.assign(airport, tailNum, arrived, arrived.Add(g.turnaround))
+}
+
+// Occupy parks an aircraft that starts out at airport at the first free
+// gate, ready to leave at once.
+func (g *Gates) Occupy(airport, tailNum string, now time.Time) (GateAssignment, error) {
+	return g.assign(airport, tailNum, now, now)
+}
+
+func (g *Gates) assign(airport, tailNum string, arrived, readyAt time.Time) (GateAssignment, error) {
+	airport = strings.ToUpper(airport)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if a, ok := g.byTail[tailNum]; ok {
+		return GateAssignment{}, fmt.Errorf("%s is already at gate %s at %s", tailNum, a.Gate, a.Airport)
+	}
+
+	occupied := g.occupied[airport]
+	if occupied == nil {
+		occupied = make(map[string]GateAssignment)
+		g.occupied[airport] = occupied
+	}
+
+	for _, gate := range g.gatesAt(airport) {
+		if _, taken := occupied[gate]; taken {
+			continue
+		}
+		a := GateAssignment{
+			Airport: airport,
+			Gate:    gate,
+			TailNum: tailNum,
+			Arrived: arrived,
+			ReadyAt: readyAt,
+		}
+		occupied[gate] = a
+		g.byTail[tailNum] = a
+		return a, nil
+	}
+	return GateAssignment{}, fmt.Errorf("%s: %w", airport, ErrNoGate)
+}
+
+// Lookup returns the gate an aircraft is parked at.
+func (g *Gates) Lookup(tailNum string) (GateAssignment, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	a, ok := g.byTail[tailNum]
+	return a, ok
+}
+
+// Release frees the aircraft's gate as it pushes back. It fails if the
+// turnaround is not complete by now.
+func (g *Gates) Release(tailNum string, now time.Time) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	a, ok := g.byTail[tailNum]
+	if !ok {
+		return fmt.Errorf("%s is not at a gate", tailNum)
+	}
+	if now.Before(a.ReadyAt) {
+		return fmt.Errorf("%s is still turning around at %s gate %s until %s",
+			tailNum, a.Airport, a.Gate, a.ReadyAt.Format(time.RFC3339))
+	}
+
+	delete(g.occupied[a.Airport], a.Gate)
+	delete(g.byTail, tailNum)
+	return nil
+}
+
+// Vacate frees the aircraft's gate at once, turned around or not, as when
+// its flight is called off.
+func (g *Gates) Vacate(tailNum string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if a, ok := g.byTail[tailNum]; ok {
+		delete(g.occupied[a.Airport], a.Gate)
+		delete(g.byTail, tailNum)
+	}
+}
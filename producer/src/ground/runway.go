@@ -0,0 +1,97 @@
+package ground
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Runways tracks runway occupancy at every airport. Each airport has a
+// single runway that one aircraft at a time may use to take off or land;
+// other aircraft queue for it in the order they asked. Nothing blocks: an
+// aircraft asks again each step until it is its turn, and holds where it is
+// meanwhile.
+type Runways struct {
+	mu       sync.Mutex
+	airports map[string]*runway
+}
+
+type runway struct {
+	holder string
+	queue  []string
+}
+
+// NewRunways returns an empty set of runways.
+func NewRunways() *Runways {
+	return &Runways{airports: make(map[string]*runway)}
+}
+
+func (r *Runways) runway(airport string) *runway {
+	code := strings.ToUpper(airport)
+	rw, ok := r.airports[code]
+	if !ok {
+		rw = &runway{}
+		r.airports[code] = rw
+	}
+	return rw
+}
+
+// Request asks for the runway at airport, joining the queue for it the
+// first time, and reports whether the aircraft now has it. It gets the
+// runway once the runway is free and everyone who asked before it has had
+// it.
+func (r *Runways) Request(airport, tailNum string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rw := r.runway(airport)
+	if rw.holder == tailNum {
+		return true
+	}
+	if !slices.Contains(rw.queue, tailNum) {
+		rw.queue = append(rw.queue, tailNum)
+	}
+	if rw.holder != "" || rw.queue[0] != tailNum {
+		return false
+	}
+	rw.holder, rw.queue = tailNum, rw.queue[1:]
+	return true
+}
+
+// Release frees the runway at airport for the next aircraft in the queue.
+// It is an error to release a runway the aircraft does not hold.
+func (r *Runways) Release(airport, tailNum string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rw := r.runway(airport)
+	if rw.holder != tailNum {
+		return fmt.Errorf("runway at %s is not held by %s", strings.ToUpper(airport), tailNum)
+	}
+	rw.holder = ""
+	return nil
+}
+
+// Withdraw takes the aircraft off the runway at airport, whether it holds
+// it or is queued for it, as when it is cancelled or diverted.
+func (r *Runways) Withdraw(airport, tailNum string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rw := r.runway(airport)
+	if rw.holder == tailNum {
+		rw.holder = ""
+	}
+	rw.queue = slices.DeleteFunc(rw.queue, func(t string) bool { return t == tailNum })
+}
+
+// Occupancy returns the aircraft holding the runway at airport, if any, and
+// how many aircraft are queued for it.
+func (r *Runways) Occupancy(airport string) (holder string, queued int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rw := r.runway(airport)
+	return rw.holder, len(rw.queue)
+}
@@ -0,0 +1,49 @@
+package ground
+
+import "testing"
+
+func TestRunwaySequencing(t *testing.T) {
+	r := NewRunways()
+	if !r.Request("jfk", "N1") {
+		t.Fatal("N1 did not get a free runway")
+	}
+	if !r.Request("JFK", "N1") {
+		t.Error("N1 lost the runway it holds on asking again")
+	}
+	// N2 and N3 queue in the order they asked, however often they ask.
+	for i := 0; i < 3; i++ {
+		if r.Request("JFK", "N2") || r.Request("JFK", "N3") {
+			t.Fatal("granted a runway in use")
+		}
+	}
+	if holder, queued := r.Occupancy("JFK"); holder != "N1" || queued != 2 {
+		t.Errorf("occupancy = %s, %d queued, want N1, 2", holder, queued)
+	}
+	if !r.Request("LGA", "N4") {
+		t.Error("another airport's runway is busy")
+	}
+
+	if err := r.Release("JFK", "N2"); err == nil {
+		t.Error("released a runway N2 does not hold")
+	}
+	if err := r.Release("JFK", "N1"); err != nil {
+		t.Fatal(err)
+	}
+	if r.Request("JFK", "N3") {
+		t.Error("N3 jumped the queue")
+	}
+	if !r.Request("JFK", "N2") {
+		t.Error("N2 did not get the runway once it was free")
+	}
+
+	// Withdrawing from the queue or the runway lets the next one on.
+	r.Withdraw("JFK", "N2")
+	if !r.Request("JFK", "N3") {
+		t.Error("N3 did not get the runway after N2 withdrew")
+	}
+	r.Request("JFK", "N5")
+	r.Withdraw("JFK", "N5")
+	if holder, queued := r.Occupancy("JFK"); holder != "N3" || queued != 0 {
+		t.Errorf("occupancy = %s, %d queued, want N3, 0", holder, queued)
+	}
+}
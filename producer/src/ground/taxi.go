@@ -0,0 +1,126 @@
+package ground
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+)
+
+// Duration is a time.Duration that reads and writes as a string such as
+// "8m30s" in config files.
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// TaxiTimes describes taxiing between the gate and the runway at one
+// airport. Actual taxi times are drawn from a normal distribution around
+// Mean with standard deviation StdDev.
+type TaxiTimes struct {
+	DistanceNm float64  `json:"distanceNm"`
+	Mean       Duration `json:"mean"`
+	StdDev     Duration `json:"stdDev"`
+}
+
+// TaxiModel holds taxi-out and taxi-in times per airport, falling back to
+// defaults for airports without their own entry.
+type TaxiModel struct {
+	DefaultOut TaxiTimes            `json:"defaultOut"`
+	DefaultIn  TaxiTimes            `json:"defaultIn"`
+	Out        map[string]TaxiTimes `json:"out"`
+	In         map[string]TaxiTimes `json:"in"`
+}
+
+// DefaultTaxiModel uses typical US hub taxi times for every airport.
+var DefaultTaxiModel = TaxiModel{
+	DefaultOut: TaxiTimes{DistanceNm: 2, Mean: Duration(15 * time.Minute), StdDev: Duration(5 * time.Minute)},
+	DefaultIn:  TaxiTimes{DistanceNm: 2, Mean: Duration(8 * time.Minute), StdDev: Duration(3 * time.Minute)},
+}
+
+// LoadTaxiModel reads a taxi model from JSON. Unset defaults are taken from
+// DefaultTaxiModel.
+func LoadTaxiModel(path string) (TaxiModel, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return TaxiModel{}, err
+	}
+
+	model := DefaultTaxiModel
+	if err := json.Unmarshal(b, &model); err != nil {
+		return TaxiModel{}, fmt.Errorf("taxi model: %w", err)
+	}
+
+	check := func(name string, t TaxiTimes) error {
+		if t.DistanceNm <= 0 || t.Mean <= 0 || t.StdDev < 0 {
+			return fmt.Errorf("taxi model: %s needs a positive distance and mean", name)
+		}
+		return nil
+	}
+	if err := check("defaultOut", model.DefaultOut); err != nil {
+		return TaxiModel{}, err
+	}
+	if err := check("defaultIn", model.DefaultIn); err != nil {
+		return TaxiModel{}, err
+	}
+	for code, t := range model.Out {
+		if err := check("out."+code, t); err != nil {
+			return TaxiModel{}, err
+		}
+	}
+	for code, t := range model.In {
+		if err := check("in."+code, t); err != nil {
+			return TaxiModel{}, err
+		}
+	}
+	return model, nil
+}
+
+// TaxiOut draws a taxi distance and duration from the gate to the runway at
+// the given airport.
+func (m TaxiModel) TaxiOut(airport string, rng *rand.Rand) (float64, time.Duration) {
+	t, ok := m.Out[strings.ToUpper(airport)]
+	if !ok {
+		t = m.DefaultOut
+	}
+	return t.DistanceNm, t.sample(rng)
+}
+
+// TaxiIn draws a taxi distance and duration from the runway to the gate at
+// the given airport.
+func (m TaxiModel) TaxiIn(airport string, rng *rand.Rand) (float64, time.Duration) {
+	t, ok := m.In[strings.ToUpper(airport)]
+	if !ok {
+		t = m.DefaultIn
+	}
+	return t.DistanceNm, t.sample(rng)
+}
+
+// sample draws a duration, never less than a third of the mean so a long
+// tail of the distribution can't produce an instant taxi.
+func (t TaxiTimes) sample(rng *rand.Rand) time.Duration {
+	mean, sd := float64(t.Mean), float64(t.StdDev)
+	return time.Duration(math.Max(mean+rng.NormFloat64()*sd, mean/3))
+}
+
+// TaxiSpeed returns the speed in knots needed to cover the distance in the
+// given time.
+func TaxiSpeed(distanceNm float64, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return distanceNm / d.Hours()
+}
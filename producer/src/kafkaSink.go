@@ -0,0 +1,12 @@
+//go:build kafka
+
+package main
+
+import (
+	"plane-producer/src/config"
+	"plane-producer/src/sink"
+)
+
+func newKafkaSink(cfg config.Kafka) (sink.Sink, error) {
+	return sink.NewKafka(sink.KafkaConfig{Brokers: cfg.Brokers, Topic: cfg.Topic})
+}
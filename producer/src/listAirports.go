@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"plane-producer/src/airports"
+	"plane-producer/src/config"
+)
+
+func runListAirports(args []string) error {
+	fs := flag.NewFlagSet("list-airports", flag.ExitOnError)
+	configPath := fs.String("config", "", "YAML configuration file")
+	fs.Parse(args)
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return err
+	}
+
+	db := airports.Default()
+	if cfg.Airports != "" {
+		if db, err = airports.Load(cfg.Airports); err != nil {
+			return err
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "IATA\tICAO\tNAME\tCITY\tCOUNTRY\tLAT\tLONG\tELEV")
+	for _, a := range db.All() {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%.4f\t%.4f\t%.0f\n",
+			a.IATA, a.ICAO, a.Name, a.City, a.Country, a.Latitude, a.Longitude, a.Elevation)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,14 @@
+//go:build !kafka
+
+package main
+
+import (
+	"errors"
+
+	"plane-producer/src/config"
+	"plane-producer/src/sink"
+)
+
+func newKafkaSink(config.Kafka) (sink.Sink, error) {
+	return nil, errors.New("kafka support is not compiled in; rebuild with -tags kafka")
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"plane-producer/src/config"
+	"plane-producer/src/encoder"
+	"plane-producer/src/sink"
+	"plane-producer/src/units"
+)
+
+// output encodes reports and writes them to the configured sink.
+type output struct {
+	encoder   encoder.Encoder
+	partition sink.PartitionStrategy
+	sink      sink.Sink
+	retry     interface{ Stats() sink.RetryStats }
+	queue     *sink.Queue
+	chaos     *sink.Chaos
+}
+
+// newOutput creates the configured output. Encodings that write times as
+// text give them in loc.
+func newOutput(ctx context.Context, cfg config.Sink, loc *time.Location) (*output, error) {
+	enc, err := encoder.New(cfg.Format)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := enc.(encoder.SBS); ok {
+		enc = encoder.SBS{Location: loc}
+	}
+	partition, err := sink.ParsePartitionStrategy(cfg.Partition)
+	if err != nil {
+		return nil, err
+	}
+	system, err := units.ParseSystem(cfg.Units)
+	if err != nil {
+		return nil, err
+	}
+	vs, err := units.ParseVerticalSpeedUnit(cfg.VerticalSpeedUnit)
+	if err != nil {
+		return nil, err
+	}
+	o := &output{encoder: enc, partition: partition}
+	if o.sink, err = o.newSink(ctx, cfg); err != nil {
+		return nil, err
+	}
+	// The size is checked on the payload as written, after any conversion.
+	o.encoder = encoder.SizeGuard{Encoder: enc, MaxBytes: cfg.MaxRecordBytes}
+	if system != units.Imperial || vs != units.FPM {
+		o.encoder = encoder.Units{Encoder: o.encoder, System: system, VerticalSpeed: vs}
+	}
+	return o, nil
+}
+
+func (o *output) newSink(ctx context.Context, cfg config.Sink) (sink.Sink, error) {
+	enc := o.encoder
+	var s sink.Sink
+	var err error
+
+	switch cfg.Type {
+	case "file":
+		fileCfg := sink.FileConfig{Path: cfg.File.Path, MaxBytes: cfg.File.MaxBytes, MaxAge: cfg.File.MaxAge}
+		if csv, ok := enc.(encoder.CSV); ok && !cfg.Gzip {
+			fileCfg.Header = csv.Header()
+		}
+		s, err = sink.NewFile(fileCfg)
+	case "kinesis":
+		s, err = sink.NewKinesis(ctx, sink.KinesisConfig{
+			StreamName:   cfg.Kinesis.Stream,
+			AWSOptions:   awsOptions(cfg.Kinesis.AWS),
+			PartitionKey: cfg.Kinesis.PartitionKey,
+			Aggregate:    cfg.Kinesis.Aggregate,
+			MaxRetries:   cfg.Kinesis.MaxRetries,
+		})
+	case "sqs":
+		s, err = sink.NewSQS(ctx, sink.SQSConfig{QueueURL: cfg.SQS.QueueURL, AWSOptions: awsOptions(cfg.SQS.AWS)})
+	case "kafka":
+		s, err = newKafkaSink(cfg.Kafka)
+	case "mqtt":
+		s, err = sink.NewMQTT(ctx, sink.MQTTConfig{
+			Broker:   cfg.MQTT.Broker,
+			ClientID: cfg.MQTT.ClientID,
+			Username: cfg.MQTT.Username,
+			Password: cfg.MQTT.Password,
+			Topic:    cfg.MQTT.Topic,
+			QoS:      byte(cfg.MQTT.QoS),
+			Retained: cfg.MQTT.Retained,
+		})
+	case "webhook":
+		s, err = sink.NewWebhook(ctx, sink.WebhookConfig{
+			URL:          cfg.Webhook.URL,
+			ContentType:  enc.ContentType(),
+			Headers:      cfg.Webhook.Headers,
+			BearerToken:  cfg.Webhook.BearerToken,
+			SigV4Service: cfg.Webhook.SigV4Service,
+			SigV4Region:  cfg.Webhook.SigV4Region,
+		})
+	case "tcp":
+		s, err = sink.NewTCPServer(cfg.TCP.Addr, 0)
+	default:
+		return nil, fmt.Errorf("unknown sink type %q", cfg.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The webhook sink already retries with its own backoff.
+	if cfg.Retry.MaxAttempts > 1 && cfg.Type != "webhook" {
+		s = sink.NewRetry(s, sink.RetryPolicy{
+			MaxAttempts:    cfg.Retry.MaxAttempts,
+			InitialBackoff: cfg.Retry.InitialBackoff,
+			MaxBackoff:     cfg.Retry.MaxBackoff,
+			Jitter:         cfg.Retry.Jitter,
+		})
+		o.retry = s.(interface{ Stats() sink.RetryStats })
+	}
+
+	if cfg.Gzip {
+		s = sink.NewGzipBatcher(s, sink.GzipBatchConfig{BatchSize: cfg.BatchSize, FlushInterval: cfg.FlushInterval})
+	} else if batch, ok := s.(sink.BatchSink); ok && cfg.BatchSize > 0 {
+		s = sink.NewBuffer(batch, cfg.BatchSize, cfg.FlushInterval)
+	}
+
+	if cfg.Queue.Size > 0 {
+		policy, err := sink.ParseOverflowPolicy(cfg.Queue.Overflow)
+		if err != nil {
+			return nil, err
+		}
+		o.queue = sink.NewQueue(s, cfg.Queue.Size, cfg.Queue.Batch, policy)
+		s = o.queue
+	}
+
+	if cfg.Chaos != (config.Chaos{}) {
+		o.chaos = sink.NewChaos(s, sink.ChaosConfig(cfg.Chaos))
+		s = o.chaos
+	}
+	return s, nil
+}
+
+func awsOptions(cfg config.AWS) sink.AWSOptions {
+	return sink.AWSOptions{
+		Region:             cfg.Region,
+		Endpoint:           cfg.Endpoint,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+		Profile:            cfg.Profile,
+		RoleARN:            cfg.RoleARN,
+		ExternalID:         cfg.ExternalID,
+		SessionName:        cfg.SessionName,
+	}
+}
+
+// write writes a single encoded record, logging rather than returning
+// failures so one bad report does not stop the run.
+func (o *output) write(ctx context.Context, record sink.Record) {
+	if err := o.sink.Put(ctx, record); err != nil {
+		log.Printf("writing report for %s: %v", record.FlightID, err)
+	}
+}
+
+// flush hands over the reports queued so far, once a tick's worth have
+// been written.
+func (o *output) flush(ctx context.Context) {
+	if o.queue == nil {
+		return
+	}
+	if err := o.queue.Flush(ctx); err != nil {
+		log.Print(err)
+	}
+}
+
+func (o *output) Close() error {
+	err := o.sink.Close()
+	if o.retry != nil {
+		stats := o.retry.Stats()
+		log.Printf("sink retries: %d, permanent failures: %d", stats.Retries, stats.PermanentFailures)
+	}
+	if o.queue != nil && o.queue.Dropped() > 0 {
+		log.Printf("sink queue overflowed, dropped %d reports", o.queue.Dropped())
+	}
+	if o.chaos != nil {
+		stats := o.chaos.Stats()
+		log.Printf("chaos: duplicated %d, reordered %d and dropped %d reports", stats.Duplicated, stats.Reordered, stats.Dropped)
+	}
+	return err
+}
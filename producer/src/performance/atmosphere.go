@@ -0,0 +1,39 @@
+package performance
+
+import "math"
+
+const (
+	// seaLevelSoundKt is the speed of sound at sea level in the ISA.
+	seaLevelSoundKt = 661.4786
+	// tropopauseFt is where the ISA temperature stops falling.
+	tropopauseFt = 36089.24
+)
+
+// isa returns the International Standard Atmosphere temperature and
+// pressure ratios to sea level at altitudeFt.
+func isa(altitudeFt float64) (θ, δ float64) {
+	if altitudeFt <= tropopauseFt {
+		θ = 1 - 6.875586e-6*altitudeFt
+		return θ, math.Pow(θ, 5.255876)
+	}
+	return 0.751865, 0.223361 * math.Exp(-(altitudeFt-tropopauseFt)/20805.8)
+}
+
+// IndicatedAirspeed converts a true airspeed in knots at altitudeFt to the
+// airspeed an instrument without position error would show, i.e. calibrated
+// airspeed, in the standard atmosphere.
+func IndicatedAirspeed(trueAirspeed, altitudeFt float64) float64 {
+	θ, δ := isa(altitudeFt)
+	mach := trueAirspeed / (seaLevelSoundKt * math.Sqrt(θ))
+	impact := δ * (math.Pow(1+0.2*mach*mach, 3.5) - 1)
+	return seaLevelSoundKt * math.Sqrt(5*(math.Pow(impact+1, 2.0/7)-1))
+}
+
+// TrueAirspeed is the inverse of IndicatedAirspeed.
+func TrueAirspeed(indicatedAirspeed, altitudeFt float64) float64 {
+	θ, δ := isa(altitudeFt)
+	cas := indicatedAirspeed / seaLevelSoundKt
+	impact := math.Pow(1+0.2*cas*cas, 3.5) - 1
+	mach := math.Sqrt(5 * (math.Pow(impact/δ+1, 2.0/7) - 1))
+	return mach * seaLevelSoundKt * math.Sqrt(θ)
+}
@@ -0,0 +1,93 @@
+package performance
+
+import (
+	"math"
+
+	"plane-producer/src/geo"
+)
+
+const (
+	// stepClimbMinNm is the route length above which aircraft start low and
+	// step climb as they burn fuel.
+	stepClimbMinNm = 2500
+	// stepClimbIntervalNm is the distance flown between step climbs.
+	stepClimbIntervalNm = 1000
+)
+
+// Step is a step climb to Altitude once AtNm nautical miles have been flown.
+type Step struct {
+	AtNm     float64
+	Altitude float64
+}
+
+// CruisePlan picks the initial cruise altitude for a route of distanceNm
+// flown on the given course, and any step climbs along the way.
+//
+// Short routes cruise lower, since there is no point climbing to a level
+// that would have to be left straight away. Levels follow the semicircular
+// rule: odd thousands eastbound, even thousands westbound. The course is
+// true rather than magnetic, which is close enough for simulation. Long
+// routes start two levels low and step up every 1000nm while staying below
+// the service ceiling.
+func (p Profile) CruisePlan(distanceNm float64, course geo.Degrees) (float64, []Step) {
+	eastbound := course.Normalized() < 180
+
+	ceiling := p.CruiseAltitude
+	switch {
+	case distanceNm < 150:
+		ceiling = math.Min(ceiling, 20000)
+	case distanceNm < 300:
+		ceiling = math.Min(ceiling, 28000)
+	case distanceNm < 500:
+		ceiling = math.Min(ceiling, 33000)
+	}
+	altitude := FlightLevel(ceiling, eastbound)
+
+	if distanceNm < stepClimbMinNm {
+		return altitude, nil
+	}
+
+	altitude = FlightLevel(altitude-4000, eastbound)
+	var steps []Step
+	next := altitude
+	for at := float64(stepClimbIntervalNm); at < distanceNm-stepClimbIntervalNm; at += stepClimbIntervalNm {
+		next = FlightLevel(next+2000, eastbound)
+		if next > p.ServiceCeiling-2000 {
+			break
+		}
+		steps = append(steps, Step{AtNm: at, Altitude: next})
+	}
+	return altitude, steps
+}
+
+// FlightLevel returns the highest altitude at or below maxAltitude that is
+// valid for the direction of flight: odd thousands of feet eastbound and
+// even thousands westbound, with the 4000ft separation used above FL410.
+// Altitudes below the lowest level return that level.
+func FlightLevel(maxAltitude float64, eastbound bool) float64 {
+	thousands := int(math.Floor(maxAltitude / 1000))
+
+	if thousands > 41 {
+		// Above RVSM airspace: 45, 49... eastbound and 43, 47... westbound.
+		base := 41
+		if !eastbound {
+			base = 43
+		}
+		if thousands >= base {
+			return float64(base+(thousands-base)/4*4) * 1000
+		}
+		thousands = 41
+	}
+
+	odd := thousands%2 != 0
+	if odd != eastbound {
+		thousands--
+	}
+	if thousands < 1 {
+		thousands = 1
+		if !eastbound {
+			thousands = 2
+		}
+	}
+	return float64(thousands) * 1000
+}
@@ -0,0 +1,44 @@
+package performance
+
+import (
+	"math"
+
+	"plane-producer/src/geo"
+)
+
+// descentSpeed is the average ground speed in knots over a descent, which
+// starts at cruise speed and ends at approach speed.
+func (p Profile) descentSpeed() float64 {
+	return (p.CruiseSpeed + p.ApproachSpeed) / 2
+}
+
+// DescentDistance returns how many nautical miles before the destination a
+// descent from cruiseAltitude must begin to arrive at fieldElevation, at
+// the profile's descent rate.
+func (p Profile) DescentDistance(cruiseAltitude, fieldElevation float64) float64 {
+	minutes := math.Max(cruiseAltitude-fieldElevation, 0) / p.DescentRate
+	return minutes * p.descentSpeed() / 60
+}
+
+// TopOfDescent returns how far along the track, and where, the descent
+// from cruiseAltitude to the destination's fieldElevation must begin. On
+// tracks too short to reach cruise altitude it is the start of the track.
+func (p Profile) TopOfDescent(track geo.Route, cruiseAltitude, fieldElevation float64) (float64, geo.Position) {
+	at := math.Max(track.Length()-p.DescentDistance(cruiseAltitude, fieldElevation), 0)
+	return at, track.PositionAt(at)
+}
+
+// DescentAltitude returns the altitude the aircraft should be at with
+// remainingNm left to fly on a constant-gradient descent from
+// cruiseAltitude to fieldElevation. Before top of descent it is
+// cruiseAltitude.
+func (p Profile) DescentAltitude(remainingNm, cruiseAltitude, fieldElevation float64) float64 {
+	total := p.DescentDistance(cruiseAltitude, fieldElevation)
+	if total == 0 || remainingNm >= total {
+		return cruiseAltitude
+	}
+	if remainingNm <= 0 {
+		return fieldElevation
+	}
+	return fieldElevation + (cruiseAltitude-fieldElevation)*remainingNm/total
+}
@@ -0,0 +1,122 @@
+package performance
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Profile describes how an aircraft type performs in each phase of flight.
+// Speeds are in knots, altitudes in feet and rates in feet per minute.
+// Acceleration and Deceleration are in knots per second and
+// VerticalAcceleration in feet per minute per second.
+type Profile struct {
+	Type string `json:"type"`
+
+	TaxiSpeed     float64 `json:"taxiSpeed"`
+	TakeOffSpeed  float64 `json:"takeOffSpeed"`
+	ClimbSpeed    float64 `json:"climbSpeed"`
+	CruiseSpeed   float64 `json:"cruiseSpeed"`
+	ApproachSpeed float64 `json:"approachSpeed"`
+
+	CruiseAltitude float64 `json:"cruiseAltitude"`
+	ServiceCeiling float64 `json:"serviceCeiling"`
+	ClimbRate      float64 `json:"climbRate"`
+	DescentRate    float64 `json:"descentRate"`
+
+	Acceleration         float64 `json:"acceleration"`
+	Deceleration         float64 `json:"deceleration"`
+	VerticalAcceleration float64 `json:"verticalAcceleration"`
+}
+
+// Validate checks that every value needed to fly the profile is set and
+// consistent.
+func (p Profile) Validate() error {
+	switch {
+	case p.Type == "":
+		return fmt.Errorf("profile has no aircraft type")
+	case p.TaxiSpeed <= 0, p.TakeOffSpeed <= 0, p.ClimbSpeed <= 0, p.CruiseSpeed <= 0, p.ApproachSpeed <= 0:
+		return fmt.Errorf("profile %s: all speeds must be positive", p.Type)
+	case p.ClimbRate <= 0, p.DescentRate <= 0:
+		return fmt.Errorf("profile %s: climb and descent rates must be positive", p.Type)
+	case p.Acceleration <= 0, p.Deceleration <= 0, p.VerticalAcceleration <= 0:
+		return fmt.Errorf("profile %s: acceleration rates must be positive", p.Type)
+	case p.CruiseAltitude <= 0 || p.CruiseAltitude > p.ServiceCeiling:
+		return fmt.Errorf("profile %s: cruise altitude must be between 0 and the service ceiling", p.Type)
+	}
+	return nil
+}
+
+// Profiles is a set of performance profiles keyed by ICAO type designator.
+type Profiles map[string]Profile
+
+// Defaults are built-in profiles for common airliners.
+var Defaults = Profiles{
+	"B738": {Type: "B738", TaxiSpeed: 15, TakeOffSpeed: 150, ClimbSpeed: 280, CruiseSpeed: 453, ApproachSpeed: 145,
+		CruiseAltitude: 35000, ServiceCeiling: 41000, ClimbRate: 2500, DescentRate: 2000,
+		Acceleration: 2.5, Deceleration: 2, VerticalAcceleration: 400},
+	"A320": {Type: "A320", TaxiSpeed: 15, TakeOffSpeed: 145, ClimbSpeed: 280, CruiseSpeed: 447, ApproachSpeed: 137,
+		CruiseAltitude: 35000, ServiceCeiling: 39000, ClimbRate: 2500, DescentRate: 2000,
+		Acceleration: 2.5, Deceleration: 2, VerticalAcceleration: 400},
+	"CRJ9": {Type: "CRJ9", TaxiSpeed: 15, TakeOffSpeed: 140, ClimbSpeed: 290, CruiseSpeed: 447, ApproachSpeed: 140,
+		CruiseAltitude: 33000, ServiceCeiling: 41000, ClimbRate: 2500, DescentRate: 2000,
+		Acceleration: 2.5, Deceleration: 2, VerticalAcceleration: 400},
+	"E175": {Type: "E175", TaxiSpeed: 15, TakeOffSpeed: 130, ClimbSpeed: 270, CruiseSpeed: 430, ApproachSpeed: 125,
+		CruiseAltitude: 33000, ServiceCeiling: 41000, ClimbRate: 2500, DescentRate: 2000,
+		Acceleration: 2.5, Deceleration: 2, VerticalAcceleration: 400},
+	"B77W": {Type: "B77W", TaxiSpeed: 15, TakeOffSpeed: 165, ClimbSpeed: 300, CruiseSpeed: 490, ApproachSpeed: 149,
+		CruiseAltitude: 37000, ServiceCeiling: 43100, ClimbRate: 2000, DescentRate: 2000,
+		Acceleration: 2, Deceleration: 1.5, VerticalAcceleration: 300},
+	"A359": {Type: "A359", TaxiSpeed: 15, TakeOffSpeed: 155, ClimbSpeed: 300, CruiseSpeed: 488, ApproachSpeed: 140,
+		CruiseAltitude: 39000, ServiceCeiling: 43100, ClimbRate: 2200, DescentRate: 2000,
+		Acceleration: 2, Deceleration: 1.5, VerticalAcceleration: 300},
+}
+
+// Load reads profiles from a JSON array and merges them over the defaults,
+// so a file only needs to list the types it adds or changes.
+func Load(path string) (Profiles, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Profile
+	if err := json.Unmarshal(b, &list); err != nil {
+		return nil, fmt.Errorf("performance: %w", err)
+	}
+
+	profiles := make(Profiles, len(Defaults)+len(list))
+	for t, p := range Defaults {
+		profiles[t] = p
+	}
+	for _, p := range list {
+		p.Type = strings.ToUpper(p.Type)
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("performance: %w", err)
+		}
+		profiles[p.Type] = p
+	}
+	return profiles, nil
+}
+
+// Get returns the profile for an aircraft type.
+func (ps Profiles) Get(aircraftType string) (Profile, error) {
+	p, ok := ps[strings.ToUpper(aircraftType)]
+	if !ok {
+		return Profile{}, fmt.Errorf("no performance profile for aircraft type %q (known: %s)",
+			aircraftType, strings.Join(ps.Types(), ", "))
+	}
+	return p, nil
+}
+
+// Types returns the known aircraft types in sorted order.
+func (ps Profiles) Types() []string {
+	types := make([]string, 0, len(ps))
+	for t := range ps {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
@@ -0,0 +1,37 @@
+package performance
+
+import (
+	"math"
+	"time"
+
+	"plane-producer/src/units"
+)
+
+// Ramp moves current towards target by at most rate units per second over
+// dt, so speeds and vertical speeds change smoothly between phases instead
+// of jumping to their new values.
+func Ramp(current, target, rate float64, dt time.Duration) float64 {
+	step := rate * dt.Seconds()
+	if math.Abs(target-current) <= step {
+		return target
+	}
+	if target > current {
+		return current + step
+	}
+	return current - step
+}
+
+// RampSpeed ramps a speed in knots towards target using the profile's
+// acceleration when speeding up and deceleration when slowing down.
+func (p Profile) RampSpeed(current, target float64, dt time.Duration) float64 {
+	if target > current {
+		return Ramp(current, target, p.Acceleration, dt)
+	}
+	return Ramp(current, target, p.Deceleration, dt)
+}
+
+// RampVerticalSpeed ramps a vertical speed towards target using the
+// profile's vertical acceleration.
+func (p Profile) RampVerticalSpeed(current, target units.VerticalSpeed, dt time.Duration) units.VerticalSpeed {
+	return units.FeetPerMinute(Ramp(current.FeetPerMinute(), target.FeetPerMinute(), p.VerticalAcceleration, dt))
+}
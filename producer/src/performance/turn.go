@@ -0,0 +1,58 @@
+package performance
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// StandardRate is the rate of turn airliners normally fly, in degrees
+	// per second.
+	StandardRate = 3.0
+	// MaxBank is the steepest bank angle flown in normal operations, in
+	// degrees. At high speed it limits turns to less than standard rate.
+	MaxBank = 25.0
+	// RollRate is how fast the bank angle changes, in degrees per second.
+	RollRate = 5.0
+
+	// turnGain is the rate of turn asked for per degree of heading still to
+	// turn, per second.
+	turnGain = 0.2
+
+	gravity         = 9.80665 // m/s²
+	metresPerSecond = 1852.0 / 3600
+)
+
+// RateOfTurn returns the rate of turn in degrees per second that bank
+// degrees of bank gives at trueAirspeed knots. Positive banks are to the
+// right and turn clockwise.
+func RateOfTurn(bank, trueAirspeed float64) float64 {
+	if trueAirspeed <= 0 {
+		return 0
+	}
+	ω := gravity * math.Tan(bank*math.Pi/180) / (trueAirspeed * metresPerSecond)
+	return ω * 180 / math.Pi
+}
+
+// BankFor returns the bank angle in degrees needed to turn at rate degrees
+// per second at trueAirspeed knots.
+func BankFor(rate, trueAirspeed float64) float64 {
+	ω := rate * math.Pi / 180
+	return math.Atan(ω*trueAirspeed*metresPerSecond/gravity) * 180 / math.Pi
+}
+
+// Turn rolls towards the bank that turns through headingError degrees,
+// positive to the right, without exceeding standard rate or MaxBank. It
+// returns the new bank angle and the heading change over dt, which never
+// overshoots headingError.
+func Turn(bank, headingError, trueAirspeed float64, dt time.Duration) (newBank, turned float64) {
+	rate := math.Max(-StandardRate, math.Min(headingError*turnGain, StandardRate))
+	target := math.Max(-MaxBank, math.Min(BankFor(rate, trueAirspeed), MaxBank))
+	newBank = Ramp(bank, target, RollRate, dt)
+
+	turned = RateOfTurn(newBank, trueAirspeed) * dt.Seconds()
+	if math.Abs(turned) > math.Abs(headingError) {
+		turned = headingError
+	}
+	return newBank, turned
+}
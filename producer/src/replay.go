@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"plane-producer/src/capture"
+	"plane-producer/src/config"
+	"plane-producer/src/domain"
+	"plane-producer/src/sim"
+)
+
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	configPath := fs.String("config", "", "YAML configuration file")
+	sinkType := fs.String("sink", "", "sink type, overriding the configuration")
+	speed := fs.Float64("speed", 1, "replay this many times faster than recorded (0 sends as fast as the sink takes them)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: replay [flags] file.ndjson...\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return err
+	}
+	if *sinkType != "" {
+		cfg.Sink.Type = *sinkType
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	loc, err := cfg.Simulation.Location()
+	if err != nil {
+		return err
+	}
+	out, err := newOutput(ctx, cfg.Sink, loc)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := out.Close(); err != nil {
+			log.Printf("closing sink: %v", err)
+		}
+	}()
+
+	pace := &pacer{speed: *speed}
+	var total int
+	for _, path := range fs.Args() {
+		n, err := replayFile(ctx, path, out, pace)
+		total += n
+		if err != nil {
+			return err
+		}
+	}
+	log.Printf("replayed %d reports", total)
+	return nil
+}
+
+// pacer spaces records out as their timestamps were, sped up by speed.
+// Time starts at the first record, and a speed of zero does not wait.
+type pacer struct {
+	speed float64
+	clock *sim.Clock
+}
+
+func (p *pacer) wait(ctx context.Context, t time.Time) error {
+	switch {
+	case p.speed <= 0:
+		return nil
+	case p.clock == nil:
+		p.clock = sim.NewClock(t, p.speed)
+		return nil
+	}
+	return p.clock.SleepUntil(ctx, t)
+}
+
+// replayFile re-encodes every report in a newline-delimited JSON file and
+// writes it to out when pace says, returning how many were written. Event
+// records, such as geofence crossings, are written as they are, and the
+// header of a capture file is logged.
+func replayFile(ctx context.Context, path string, out *output, pace *pacer) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var n int
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if err := ctx.Err(); err != nil {
+			return n, err
+		}
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		if meta, ok := capture.ParseHeader(scanner.Bytes()); ok && line == 1 {
+			log.Printf("%s: captured %s at %gx with seed %d, config %.12s", path, meta.Start.Format(time.RFC3339), meta.Speed, meta.Seed, meta.ConfigHash)
+			continue
+		}
+
+		record, err := domain.ParseFlightRecord(scanner.Bytes())
+		if err != nil {
+			return n, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		// What is queued goes out before waiting for the next record.
+		if pace.speed > 0 {
+			out.flush(ctx)
+		}
+		if err := pace.wait(ctx, record.Time()); err != nil {
+			return n, err
+		}
+
+		var event struct {
+			Event string `json:"event"`
+		}
+		data := bytes.Clone(scanner.Bytes())
+		if err := json.Unmarshal(data, &event); err != nil || event.Event == "" {
+			if data, err = out.encoder.Encode(record); err != nil {
+				return n, fmt.Errorf("%s:%d: %w", path, line, err)
+			}
+		}
+		out.write(ctx, out.partition.NewRecord(record, data))
+		n++
+	}
+	if err := scanner.Err(); err != nil {
+		return n, fmt.Errorf("%s: %w", path, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,140 @@
+package reporting
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"plane-producer/src/domain"
+)
+
+// Policy sets how often each aircraft's reports are written. Interval
+// applies to every aircraft not named in Aircraft by tail number or in
+// Airlines by the airline prefix of its flight ID, such as UTP. Zero
+// writes every report.
+//
+// With Adaptive set, an aircraft taking off, approaching or landing
+// reports on every step whatever its interval, so the intervals mostly
+// thin out cruise and time at the gate.
+type Policy struct {
+	Interval time.Duration
+	Airlines map[string]time.Duration
+	Aircraft map[string]time.Duration
+	Adaptive bool
+}
+
+// IntervalFor returns the interval between the reports of the aircraft a
+// record is from, given what it is doing.
+func (p Policy) IntervalFor(r domain.FlightRecord) time.Duration {
+	if p.Adaptive && critical(r.Status) {
+		return 0
+	}
+	if d, ok := p.Aircraft[r.TailNum]; ok {
+		return d
+	}
+	if d, ok := p.Airlines[Airline(r.FlightID)]; ok {
+		return d
+	}
+	return p.Interval
+}
+
+func critical(s domain.Status) bool {
+	switch s {
+	case domain.TakeOff, domain.AwaitingLanding, domain.Landing:
+		return true
+	}
+	return false
+}
+
+// Airline returns the airline prefix of a flight ID, e.g. UTP for UTP123.
+func Airline(flightID string) string {
+	return strings.TrimRight(flightID, "0123456789")
+}
+
+// Gate passes on the reports due under a policy. An aircraft's first
+// report is always due, as is any report whose status or emergency differs
+// from the last one written, so milestones are never thinned out. It is
+// safe for concurrent use.
+type Gate struct {
+	policy Policy
+
+	mu   sync.Mutex
+	last map[string]written
+}
+
+type written struct {
+	time      int64
+	status    domain.Status
+	emergency domain.Emergency
+}
+
+// NewGate creates a gate applying policy.
+func NewGate(policy Policy) *Gate {
+	return &Gate{policy: policy, last: make(map[string]written)}
+}
+
+// Due reports whether a record should be written, noting it as its
+// flight's last if so.
+func (g *Gate) Due(r domain.FlightRecord) bool {
+	return g.due(r, g.policy.IntervalFor(r))
+}
+
+// DueEvery is Due for an aircraft reporting every interval in place of
+// the policy's interval for it. Adaptive policies still pass on every
+// report of an aircraft taking off, approaching or landing.
+func (g *Gate) DueEvery(r domain.FlightRecord, interval time.Duration) bool {
+	if g.policy.Adaptive && critical(r.Status) {
+		interval = 0
+	}
+	return g.due(r, interval)
+}
+
+func (g *Gate) due(r domain.FlightRecord, interval time.Duration) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	last, ok := g.last[r.FlightID]
+	due := !ok || r.Status != last.status || r.Emergency != last.emergency ||
+		r.Timestamp-last.time >= interval.Milliseconds()
+	if due {
+		g.last[r.FlightID] = written{time: r.Timestamp, status: r.Status, emergency: r.Emergency}
+	}
+	return due
+}
+
+// Forget drops what the gate knows of a flight, so that its next report,
+// if any, is due whatever the policy.
+func (g *Gate) Forget(flightID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.last, flightID)
+}
+
+// Sequences numbers each aircraft's written reports from 1. The numbers
+// start again when the producer does, or when the aircraft moves to
+// another producer. It is safe for concurrent use.
+type Sequences struct {
+	mu   sync.Mutex
+	last map[string]uint64
+}
+
+// NewSequences creates an empty set of sequences.
+func NewSequences() *Sequences {
+	return &Sequences{last: make(map[string]uint64)}
+}
+
+// Next returns the next sequence number for an aircraft.
+func (s *Sequences) Next(tailNum string) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.last[tailNum]++
+	return s.last[tailNum]
+}
+
+// Resume carries on an aircraft's sequence from last, the sequence number
+// of the last report written before, e.g., a restart.
+func (s *Sequences) Resume(tailNum string, last uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.last[tailNum] = last
+}
@@ -0,0 +1,122 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxFlightNumber is the highest flight number a callsign is given.
+const maxFlightNumber = 9999
+
+// Callsigns hands out flight IDs made of an airline code and a flight
+// number, such as UTP123, never giving out one that is still in use. It is
+// safe for concurrent use.
+type Callsigns struct {
+	mu       sync.Mutex
+	airlines []string
+	rng      *rand.Rand
+	inUse    map[string]bool
+}
+
+// NewCallsigns creates a generator drawing from the given airline codes
+// with rng.
+func NewCallsigns(airlines []string, rng *rand.Rand) (*Callsigns, error) {
+	if err := ValidateAirlines(airlines); err != nil {
+		return nil, err
+	}
+	return &Callsigns{airlines: airlines, rng: rng, inUse: make(map[string]bool)}, nil
+}
+
+// ValidateAirlines checks that there is at least one airline code and that
+// each is two or three upper-case letters or digits, like the IATA and ICAO
+// designators.
+func ValidateAirlines(airlines []string) error {
+	if len(airlines) == 0 {
+		return errors.New("at least one airline code is required")
+	}
+	for _, code := range airlines {
+		if len(code) < 2 || len(code) > 3 {
+			return fmt.Errorf("airline code %q must be two or three characters", code)
+		}
+		for _, c := range code {
+			if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+				return fmt.Errorf("airline code %q must be upper-case letters or digits", code)
+			}
+		}
+	}
+	return nil
+}
+
+// Next returns an unused callsign and marks it in use until released. It
+// fails only once every callsign is taken.
+func (c *Callsigns) Next() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	total := len(c.airlines) * maxFlightNumber
+	for attempt := 0; attempt < 8; attempt++ {
+		if id := c.callsign(c.rng.Intn(total)); !c.inUse[id] {
+			c.inUse[id] = true
+			return id, nil
+		}
+	}
+	// The pool is nearly full, so walk it from a random point to find
+	// whatever is left.
+	start := c.rng.Intn(total)
+	for i := 0; i < total; i++ {
+		if id := c.callsign((start + i) % total); !c.inUse[id] {
+			c.inUse[id] = true
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("all %d callsigns are in use", total)
+}
+
+// callsign returns the nth callsign of the pool, counting through each
+// airline's flight numbers in turn.
+func (c *Callsigns) callsign(n int) string {
+	return c.airlines[n/maxFlightNumber] + strconv.Itoa(1+n%maxFlightNumber)
+}
+
+// Reserve marks a callsign chosen elsewhere, such as one from a schedule
+// file, as in use. It reports false if it already was.
+func (c *Callsigns) Reserve(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.inUse[id] {
+		return false
+	}
+	c.inUse[id] = true
+	return true
+}
+
+// InUse reports whether a callsign is taken.
+func (c *Callsigns) InUse(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.inUse[id]
+}
+
+// Release makes a callsign available again once its flight is over.
+func (c *Callsigns) Release(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.inUse, id)
+}
+
+// NextFlightNumber returns the flight ID following id, as flown by the next
+// leg of a rotation: UTP123 becomes UTP124, and flight 9999 wraps to 1. An
+// ID without a flight number gets 1.
+func NextFlightNumber(id string) string {
+	prefix := strings.TrimRight(id, "0123456789")
+	n, _ := strconv.Atoi(id[len(prefix):])
+	if n >= maxFlightNumber {
+		n = 0
+	}
+	return prefix + strconv.Itoa(n+1)
+}
@@ -0,0 +1,315 @@
+package schedule
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"plane-producer/src/geo"
+	"plane-producer/src/sim"
+)
+
+// Flight is a single scheduled departure. Navigation optionally overrides
+// how its route is planned. A blank FlightID is filled in from Callsigns
+// when the flight launches.
+//
+// Rotation lists airports the aircraft flies on to after Destination, one
+// leg each, so ATL→LAX→SEA→ATL is a flight from ATL to LAX with a rotation
+// of SEA and ATL. NextLeg works out each leg once the previous one is in.
+type Flight struct {
+	TailNum     string         `json:"tailNum"`
+	FlightID    string         `json:"flightId"`
+	Origin      string         `json:"origin"`
+	Destination string         `json:"destination"`
+	Departure   time.Time      `json:"departure"`
+	Navigation  geo.Navigation `json:"navigation,omitempty"`
+	Rotation    []string       `json:"rotation,omitempty"`
+}
+
+// NextLeg returns the flight's next leg in its rotation, departing at
+// departure with the next flight number, or false if this is the last.
+func (f Flight) NextLeg(departure time.Time) (Flight, bool) {
+	if len(f.Rotation) == 0 {
+		return Flight{}, false
+	}
+	next := f
+	next.FlightID = NextFlightNumber(f.FlightID)
+	next.Origin, next.Destination = f.Destination, f.Rotation[0]
+	next.Rotation = f.Rotation[1:]
+	next.Departure = departure
+	return next, true
+}
+
+// csvHeader is the column order expected in CSV schedules, which may be
+// followed by any of csvOptional.
+var csvHeader = []string{"tailNum", "flightId", "origin", "destination", "departure"}
+
+// csvOptional are the columns a CSV schedule may add, in any order. The
+// rotation column separates airports with spaces.
+var csvOptional = []string{"navigation", "rotation"}
+
+// Load reads a schedule from a .csv or .json file.
+func Load(path string) ([]Flight, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		return LoadCSV(f)
+	case ".json":
+		return LoadJSON(f)
+	default:
+		return nil, fmt.Errorf("schedule: unsupported file type %q", ext)
+	}
+}
+
+// LoadJSON reads a schedule from a JSON array of flights. Departure times
+// are RFC 3339.
+func LoadJSON(r io.Reader) ([]Flight, error) {
+	var flights []Flight
+	if err := json.NewDecoder(r).Decode(&flights); err != nil {
+		return nil, fmt.Errorf("schedule: %w", err)
+	}
+	return validate(flights)
+}
+
+// LoadCSV reads a schedule from CSV with a header row of tailNum, flightId,
+// origin, destination, departure and optionally navigation and rotation.
+// Departure times are RFC 3339, and flightId may be left empty.
+func LoadCSV(r io.Reader) ([]Flight, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("schedule: reading header: %w", err)
+	}
+	optional, ok := csvColumns(header)
+	if !ok {
+		return nil, fmt.Errorf("schedule: header must be %s followed by any of %s", strings.Join(csvHeader, ","), strings.Join(csvOptional, ","))
+	}
+
+	var flights []Flight
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("schedule: %w", err)
+		}
+
+		line, _ := cr.FieldPos(0)
+		departure, err := time.Parse(time.RFC3339, row[4])
+		if err != nil {
+			return nil, fmt.Errorf("schedule: line %d: invalid departure: %w", line, err)
+		}
+		f := Flight{
+			TailNum:     row[0],
+			FlightID:    row[1],
+			Origin:      row[2],
+			Destination: row[3],
+			Departure:   departure,
+		}
+		if i, ok := optional["navigation"]; ok {
+			f.Navigation = geo.Navigation(row[i])
+		}
+		if i, ok := optional["rotation"]; ok {
+			f.Rotation = strings.Fields(row[i])
+		}
+		flights = append(flights, f)
+	}
+	return validate(flights)
+}
+
+// csvColumns checks a CSV header and returns the index of each optional
+// column present.
+func csvColumns(header []string) (map[string]int, bool) {
+	if len(header) < len(csvHeader) || strings.Join(header[:len(csvHeader)], ",") != strings.Join(csvHeader, ",") {
+		return nil, false
+	}
+	optional := make(map[string]int)
+	for i, name := range header[len(csvHeader):] {
+		if _, dup := optional[name]; dup || !slices.Contains(csvOptional, name) {
+			return nil, false
+		}
+		optional[name] = len(csvHeader) + i
+	}
+	return optional, true
+}
+
+// validate checks every flight and returns them sorted by departure.
+func validate(flights []Flight) ([]Flight, error) {
+	for i, f := range flights {
+		name := f.FlightID
+		if name == "" {
+			name = strconv.Itoa(i + 1)
+		}
+		if err := f.check(name); err != nil {
+			return nil, fmt.Errorf("schedule: %w", err)
+		}
+	}
+
+	sort.SliceStable(flights, func(i, j int) bool {
+		return flights[i].Departure.Before(flights[j].Departure)
+	})
+	return flights, nil
+}
+
+// Validate checks a single flight, such as one added to a running
+// simulation.
+func (f Flight) Validate() error {
+	name := f.FlightID
+	if name == "" {
+		name = f.TailNum
+	}
+	return f.check(name)
+}
+
+func (f Flight) check(name string) error {
+	switch {
+	case f.TailNum == "":
+		return fmt.Errorf("flight %s: tail number is required", name)
+	case f.Origin == "" || f.Destination == "":
+		return fmt.Errorf("flight %s: origin and destination are required", name)
+	case f.Origin == f.Destination:
+		return fmt.Errorf("flight %s: origin and destination are the same", name)
+	case f.Departure.IsZero():
+		return fmt.Errorf("flight %s: departure time is required", name)
+	}
+	if f.Navigation != "" {
+		if _, err := geo.ParseNavigation(string(f.Navigation)); err != nil {
+			return fmt.Errorf("flight %s: %w", name, err)
+		}
+	}
+	from := f.Destination
+	for _, to := range f.Rotation {
+		if to == from {
+			return fmt.Errorf("flight %s: rotation flies from %s to itself", name, to)
+		}
+		from = to
+	}
+	return nil
+}
+
+// Run calls launch for each flight when the clock reaches its departure
+// time, in departure order, until every flight has launched or ctx is
+// cancelled. Flights whose departure has already passed are launched
+// immediately.
+func Run(ctx context.Context, clock *sim.Clock, flights []Flight, launch func(Flight)) error {
+	for _, f := range flights {
+		if err := clock.SleepUntil(ctx, f.Departure); err != nil {
+			return err
+		}
+		launch(f)
+	}
+	return nil
+}
+
+// Pending tracks the flights waiting to depart, by flight ID, so that they
+// can be cancelled before they launch. Flights without an ID cannot be, and
+// when several waiting flights share an ID, cancelling it cancels the next
+// to depart. Their departure clearance can be withheld in the same way.
+// It is safe for concurrent use.
+type Pending struct {
+	mu        sync.Mutex
+	waiting   map[string]int
+	cancelled map[string]int
+	held      map[string]bool
+}
+
+// NewPending starts tracking the given flights.
+func NewPending(flights []Flight) *Pending {
+	p := &Pending{waiting: make(map[string]int), cancelled: make(map[string]int), held: make(map[string]bool)}
+	for _, f := range flights {
+		p.Add(f)
+	}
+	return p
+}
+
+// Add tracks another flight, such as the next leg of a rotation.
+func (p *Pending) Add(f Flight) {
+	if f.FlightID == "" {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.waiting[f.FlightID]++
+}
+
+// CancelScheduled cancels a waiting flight, reporting false if no flight
+// with that ID is waiting.
+func (p *Pending) CancelScheduled(flightID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.waiting[flightID] <= p.cancelled[flightID] {
+		return false
+	}
+	p.cancelled[flightID]++
+	return true
+}
+
+// HoldScheduled withholds or grants the departure clearance of a waiting
+// flight, reporting false if no flight with that ID is waiting.
+func (p *Pending) HoldScheduled(flightID string, held bool) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.waiting[flightID] <= p.cancelled[flightID] {
+		return false
+	}
+	if held {
+		p.held[flightID] = true
+	} else {
+		delete(p.held, flightID)
+	}
+	return true
+}
+
+// Held reports whether a launching flight's clearance was withheld while
+// it waited, so it must stay at the gate until granted.
+func (p *Pending) Held(f Flight) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	held := p.held[f.FlightID]
+	delete(p.held, f.FlightID)
+	return held
+}
+
+// Launch stops tracking a flight as it departs, reporting false if it was
+// cancelled and must not launch.
+func (p *Pending) Launch(f Flight) bool {
+	if f.FlightID == "" {
+		return true
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	id := f.FlightID
+	cancelled := p.cancelled[id] > 0
+	if cancelled {
+		p.cancelled[id]--
+	}
+	p.waiting[id]--
+	if p.waiting[id] <= 0 {
+		delete(p.waiting, id)
+		delete(p.cancelled, id)
+	}
+	return !cancelled
+}
@@ -0,0 +1,209 @@
+package shard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"plane-producer/src/sink"
+)
+
+// DynamoDBConfig holds the settings for a DynamoDB lease table. The table
+// needs a string partition key "pk" and a string sort key "sk". Enabling
+// DynamoDB's time to live on the number attribute "ttl" clears out old
+// claims and instances.
+type DynamoDBConfig struct {
+	Table      string
+	AWSOptions sink.AWSOptions
+}
+
+// Partition keys of the three kinds of item in the table.
+const (
+	membersKey = "member"
+	leasesKey  = "shard"
+	claimsKey  = "claim"
+)
+
+type dynamoAPI interface {
+	PutItem(ctx context.Context, in *dynamodb.PutItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	DeleteItem(ctx context.Context, in *dynamodb.DeleteItemInput, opts ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	Query(ctx context.Context, in *dynamodb.QueryInput, opts ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+}
+
+// DynamoDB is a Table kept in DynamoDB, using conditional writes for leases
+// and claims.
+type DynamoDB struct {
+	client dynamoAPI
+	table  string
+}
+
+// NewDynamoDB creates a lease table client with the standard AWS credential
+// chain.
+func NewDynamoDB(ctx context.Context, cfg DynamoDBConfig) (*DynamoDB, error) {
+	if cfg.Table == "" {
+		return nil, errors.New("dynamodb: table is required")
+	}
+	awsCfg, err := sink.LoadAWSConfig(ctx, cfg.AWSOptions)
+	if err != nil {
+		return nil, fmt.Errorf("dynamodb: %w", err)
+	}
+	return &DynamoDB{client: dynamodb.NewFromConfig(awsCfg), table: cfg.Table}, nil
+}
+
+func (d *DynamoDB) Join(ctx context.Context, instance string, expires time.Time) error {
+	_, err := d.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(d.table),
+		Item:      entry(membersKey, instance, instance, expires, expires),
+	})
+	if err != nil {
+		return fmt.Errorf("dynamodb: joining as %s: %w", instance, err)
+	}
+	return nil
+}
+
+func (d *DynamoDB) Leave(ctx context.Context, instance string) error {
+	_, err := d.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(d.table),
+		Key:       key(membersKey, instance),
+	})
+	if err != nil {
+		return fmt.Errorf("dynamodb: leaving as %s: %w", instance, err)
+	}
+	return nil
+}
+
+func (d *DynamoDB) Members(ctx context.Context, now time.Time) ([]string, error) {
+	var members []string
+	err := d.query(ctx, membersKey, func(item map[string]types.AttributeValue) {
+		if _, expires := parseEntry(item); expires.After(now) {
+			members = append(members, stringAttr(item, "sk"))
+		}
+	})
+	return members, err
+}
+
+func (d *DynamoDB) Leases(ctx context.Context) (map[int]Lease, error) {
+	leases := make(map[int]Lease)
+	err := d.query(ctx, leasesKey, func(item map[string]types.AttributeValue) {
+		s, err := strconv.Atoi(stringAttr(item, "sk"))
+		if err != nil {
+			return
+		}
+		holder, expires := parseEntry(item)
+		leases[s] = Lease{Holder: holder, Expires: expires}
+	})
+	return leases, err
+}
+
+func (d *DynamoDB) Acquire(ctx context.Context, shard int, holder string, now, expires time.Time) (bool, error) {
+	_, err := d.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(d.table),
+		Item:                entry(leasesKey, shardKey(shard), holder, expires, expires.Add(claimRetention)),
+		ConditionExpression: aws.String("attribute_not_exists(pk) OR holder = :holder OR expires < :now"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":holder": str(holder),
+			":now":    millis(now),
+		},
+	})
+	return conditional(err, "acquiring shard %d", shard)
+}
+
+func (d *DynamoDB) Release(ctx context.Context, shard int, holder string) error {
+	_, err := d.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:                 aws.String(d.table),
+		Key:                       key(leasesKey, shardKey(shard)),
+		ConditionExpression:       aws.String("holder = :holder"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":holder": str(holder)},
+	})
+	_, err = conditional(err, "releasing shard %d", shard)
+	return err
+}
+
+func (d *DynamoDB) Claim(ctx context.Context, claim, holder string, keep time.Time) (bool, error) {
+	_, err := d.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(d.table),
+		Item:                entry(claimsKey, claim, holder, time.Now(), keep),
+		ConditionExpression: aws.String("attribute_not_exists(pk)"),
+	})
+	return conditional(err, "claiming %s", claim)
+}
+
+func (d *DynamoDB) query(ctx context.Context, pk string, fn func(map[string]types.AttributeValue)) error {
+	paginator := dynamodb.NewQueryPaginator(d.client, &dynamodb.QueryInput{
+		TableName:                 aws.String(d.table),
+		KeyConditionExpression:    aws.String("pk = :pk"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{":pk": str(pk)},
+		ConsistentRead:            aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("dynamodb: reading %s items: %w", pk, err)
+		}
+		for _, item := range page.Items {
+			fn(item)
+		}
+	}
+	return nil
+}
+
+// conditional turns a failed condition into false rather than an error.
+func conditional(err error, format string, args ...any) (bool, error) {
+	var failed *types.ConditionalCheckFailedException
+	switch {
+	case errors.As(err, &failed):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("dynamodb: %s: %w", fmt.Sprintf(format, args...), err)
+	}
+	return true, nil
+}
+
+func shardKey(shard int) string {
+	return fmt.Sprintf("%04d", shard)
+}
+
+func key(pk, sk string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{"pk": str(pk), "sk": str(sk)}
+}
+
+// entry is an item naming its holder and when it expires, which DynamoDB
+// may delete once ttl has passed.
+func entry(pk, sk, holder string, expires, ttl time.Time) map[string]types.AttributeValue {
+	item := key(pk, sk)
+	item["holder"] = str(holder)
+	item["expires"] = millis(expires)
+	item["ttl"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(ttl.Unix(), 10)}
+	return item
+}
+
+func parseEntry(item map[string]types.AttributeValue) (holder string, expires time.Time) {
+	n, _ := item["expires"].(*types.AttributeValueMemberN)
+	if n != nil {
+		if ms, err := strconv.ParseInt(n.Value, 10, 64); err == nil {
+			expires = time.UnixMilli(ms)
+		}
+	}
+	return stringAttr(item, "holder"), expires
+}
+
+func stringAttr(item map[string]types.AttributeValue, name string) string {
+	if s, ok := item[name].(*types.AttributeValueMemberS); ok {
+		return s.Value
+	}
+	return ""
+}
+
+func str(s string) types.AttributeValue {
+	return &types.AttributeValueMemberS{Value: s}
+}
+
+func millis(t time.Time) types.AttributeValue {
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(t.UnixMilli(), 10)}
+}
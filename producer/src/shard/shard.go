@@ -0,0 +1,260 @@
+package shard
+
+import (
+	"context"
+	"hash/fnv"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"plane-producer/src/schedule"
+)
+
+// Lease is a shard's holder and when its hold runs out unless renewed.
+type Lease struct {
+	Holder  string
+	Expires time.Time
+}
+
+// Table is the store producer instances share to split the schedule. Every
+// write is conditional, so two instances never both hold a shard or both
+// claim a departure.
+type Table interface {
+	// Join announces instance as running until expires, and Members lists
+	// the instances whose announcements have not run out by now.
+	Join(ctx context.Context, instance string, expires time.Time) error
+	Leave(ctx context.Context, instance string) error
+	Members(ctx context.Context, now time.Time) ([]string, error)
+
+	// Leases returns the current lease of every shard that has one.
+	Leases(ctx context.Context) (map[int]Lease, error)
+	// Acquire takes or renews a lease on shard for holder until expires,
+	// reporting false if another holder's lease has not run out by now.
+	Acquire(ctx context.Context, shard int, holder string, now, expires time.Time) (bool, error)
+	Release(ctx context.Context, shard int, holder string) error
+
+	// Claim records that holder is flying the departure named key,
+	// reporting false if another instance already did. The claim may be
+	// forgotten after keep.
+	Claim(ctx context.Context, key, holder string, keep time.Time) (bool, error)
+}
+
+// Config sets how the schedule is split. Shards is the number of slices
+// tail numbers are hashed into, which should be well above the number of
+// instances so they can be balanced; every instance must agree on it.
+// Leases last TTL and are renewed every third of it.
+type Config struct {
+	Instance string
+	Shards   int
+	TTL      time.Duration
+}
+
+// Coordinator keeps this instance holding its fair share of the shards:
+// the shard count over the number of running instances, rounded up. It
+// takes free and expired shards up to that share and gives back any above
+// it, so instances scaling up get shards from those already running and
+// the shards of an instance that stops are taken over once their leases
+// run out.
+type Coordinator struct {
+	table     Table
+	cfg       Config
+	onAcquire func(shard int)
+
+	mu   sync.Mutex
+	held map[int]time.Time
+}
+
+// NewCoordinator creates a coordinator for the instance named in cfg.
+func NewCoordinator(table Table, cfg Config) *Coordinator {
+	return &Coordinator{table: table, cfg: cfg, held: make(map[int]time.Time)}
+}
+
+// OnAcquire sets a function to be called with each shard this instance
+// takes, e.g. to launch departures that came due while nobody held it. It
+// must be set before Run.
+func (c *Coordinator) OnAcquire(fn func(shard int)) {
+	c.onAcquire = fn
+}
+
+// Of returns the shard a tail number belongs to, out of n. Whole aircraft
+// are sharded rather than flights, so the legs of a rotation stay together.
+func Of(tailNum string, n int) int {
+	h := fnv.New32a()
+	h.Write([]byte(tailNum))
+	return int(h.Sum32() % uint32(n))
+}
+
+// Shard returns the shard a tail number belongs to.
+func (c *Coordinator) Shard(tailNum string) int {
+	return Of(tailNum, c.cfg.Shards)
+}
+
+// Owns reports whether this instance holds an unexpired lease on the shard
+// a tail number belongs to.
+func (c *Coordinator) Owns(tailNum string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expires, ok := c.held[c.Shard(tailNum)]
+	return ok && time.Now().Before(expires)
+}
+
+// Held returns the shards this instance holds, in order.
+func (c *Coordinator) Held() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	shards := make([]int, 0, len(c.held))
+	for s := range c.held {
+		shards = append(shards, s)
+	}
+	sort.Ints(shards)
+	return shards
+}
+
+// Claim records that this instance is flying f, reporting false if it does
+// not own f's shard or another instance already flew it.
+func (c *Coordinator) Claim(ctx context.Context, f schedule.Flight) (bool, error) {
+	if !c.Owns(f.TailNum) {
+		return false, nil
+	}
+	key := f.TailNum + "#" + strconv.FormatInt(f.Departure.UnixMilli(), 10)
+	return c.table.Claim(ctx, key, c.cfg.Instance, f.Departure.Add(claimRetention))
+}
+
+// claimRetention is how long after departure a claim is kept, well past
+// any lease running out.
+const claimRetention = 24 * time.Hour
+
+// Run balances the shards every third of the lease TTL until ctx is done,
+// then gives them all back so other instances need not wait for the
+// leases to run out.
+func (c *Coordinator) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.cfg.TTL / 3)
+	defer ticker.Stop()
+	for {
+		if err := c.balance(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("shard: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			c.leave()
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Coordinator) balance(ctx context.Context) error {
+	now := time.Now()
+	expires := now.Add(c.cfg.TTL)
+	if err := c.table.Join(ctx, c.cfg.Instance, expires); err != nil {
+		return err
+	}
+	members, err := c.table.Members(ctx, now)
+	if err != nil {
+		return err
+	}
+	leases, err := c.table.Leases(ctx)
+	if err != nil {
+		return err
+	}
+	instances := len(members)
+	if !contains(members, c.cfg.Instance) {
+		instances++
+	}
+	fair := (c.cfg.Shards + instances - 1) / instances
+
+	// Renew the lowest shards held, up to the fair share, and give back
+	// the rest.
+	for i, s := range c.Held() {
+		if i >= fair {
+			c.drop(s)
+			if err := c.table.Release(ctx, s, c.cfg.Instance); err != nil {
+				return err
+			}
+			log.Printf("shard: released %d to rebalance", s)
+			continue
+		}
+		ok, err := c.table.Acquire(ctx, s, c.cfg.Instance, now, expires)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			c.drop(s)
+			log.Printf("shard: lost %d", s)
+			continue
+		}
+		c.hold(s, expires)
+	}
+
+	var acquired []int
+	for s := 0; s < c.cfg.Shards && len(c.Held()) < fair; s++ {
+		if l, ok := leases[s]; ok && l.Holder != c.cfg.Instance && l.Expires.After(now) {
+			continue
+		}
+		if c.holds(s) {
+			continue
+		}
+		ok, err := c.table.Acquire(ctx, s, c.cfg.Instance, now, expires)
+		if err != nil {
+			return err
+		}
+		if ok {
+			c.hold(s, expires)
+			acquired = append(acquired, s)
+		}
+	}
+	if len(acquired) > 0 {
+		log.Printf("shard: acquired %v, holding %d of %d with %d instances", acquired, len(c.Held()), c.cfg.Shards, instances)
+	}
+	for _, s := range acquired {
+		if c.onAcquire != nil {
+			c.onAcquire(s)
+		}
+	}
+	return nil
+}
+
+// leave gives back every shard held and withdraws this instance.
+func (c *Coordinator) leave() {
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.TTL/3)
+	defer cancel()
+	for _, s := range c.Held() {
+		c.drop(s)
+		if err := c.table.Release(ctx, s, c.cfg.Instance); err != nil {
+			log.Printf("shard: releasing %d: %v", s, err)
+		}
+	}
+	if err := c.table.Leave(ctx, c.cfg.Instance); err != nil {
+		log.Printf("shard: leaving: %v", err)
+	}
+}
+
+func (c *Coordinator) hold(s int, expires time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.held[s] = expires
+}
+
+func (c *Coordinator) holds(s int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.held[s]
+	return ok
+}
+
+func (c *Coordinator) drop(s int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.held, s)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
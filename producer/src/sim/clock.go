@@ -0,0 +1,216 @@
+package sim
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock maps wall-clock time onto simulated time. Simulated time starts at
+// the given instant and runs Speed times faster than the wall clock, so at
+// 60x a four hour flight takes four minutes to simulate while every report
+// still carries its simulated timestamp.
+//
+// A clock can be paused, freezing simulated time until it is resumed. Child
+// clocks follow their parent, so pausing the fleet clock freezes every
+// aircraft while pausing an aircraft's own clock freezes only that aircraft.
+type Clock struct {
+	parent *Clock
+	speed  float64
+
+	mu      sync.Mutex
+	anchor  time.Time // source time when base was taken
+	base    time.Time // simulated time at anchor
+	paused  bool
+	resumed chan struct{}
+}
+
+// NewClock starts a clock at the simulated instant start, running speed
+// times faster than real time. A speed of zero or less runs in real time.
+func NewClock(start time.Time, speed float64) *Clock {
+	if speed <= 0 {
+		speed = 1
+	}
+	return &Clock{speed: speed, anchor: time.Now(), base: start}
+}
+
+// RealTime returns a clock that follows the wall clock.
+func RealTime() *Clock {
+	return NewClock(time.Now(), 1)
+}
+
+// Child returns a clock that starts at the current simulated time and
+// advances with c, but can also be paused on its own.
+func (c *Clock) Child() *Clock {
+	return &Clock{parent: c, speed: 1, anchor: c.Now(), base: c.Now()}
+}
+
+func (c *Clock) source() time.Time {
+	if c.parent != nil {
+		return c.parent.Now()
+	}
+	return time.Now()
+}
+
+// Speed is the simulation speed multiplier relative to the wall clock.
+func (c *Clock) Speed() float64 {
+	if c.parent != nil {
+		return c.speed * c.parent.Speed()
+	}
+	return c.speed
+}
+
+// Now returns the current simulated time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.nowLocked()
+}
+
+func (c *Clock) nowLocked() time.Time {
+	if c.paused {
+		return c.base
+	}
+	elapsed := c.source().Sub(c.anchor)
+	return c.base.Add(time.Duration(float64(elapsed) * c.speed))
+}
+
+// At returns the simulated time on c when its root clock reads t. It lets
+// a tick of the fleet clock be turned into an aircraft's own time exactly,
+// rather than by reading the wall clock again.
+func (c *Clock) At(t time.Time) time.Time {
+	if c.parent == nil {
+		return t
+	}
+	t = c.parent.At(t)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.paused {
+		return c.base
+	}
+	return c.base.Add(time.Duration(float64(t.Sub(c.anchor)) * c.speed))
+}
+
+// Pause freezes simulated time.
+func (c *Clock) Pause() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.paused {
+		return
+	}
+	c.base = c.nowLocked()
+	c.paused = true
+	c.resumed = make(chan struct{})
+}
+
+// Resume restarts simulated time from where it was paused.
+func (c *Clock) Resume() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.paused {
+		return
+	}
+	c.anchor = c.source()
+	c.paused = false
+	close(c.resumed)
+}
+
+// Paused reports whether c or any clock it follows is paused.
+func (c *Clock) Paused() bool {
+	return c.pausedOn() != nil
+}
+
+// pausedOn returns the resume channel of the nearest paused clock, or nil if
+// time is running.
+func (c *Clock) pausedOn() <-chan struct{} {
+	for clock := c; clock != nil; clock = clock.parent {
+		clock.mu.Lock()
+		paused, resumed := clock.paused, clock.resumed
+		clock.mu.Unlock()
+		if paused {
+			return resumed
+		}
+	}
+	return nil
+}
+
+// Wall converts a simulated duration to the wall-clock time it takes.
+func (c *Clock) Wall(d time.Duration) time.Duration {
+	return time.Duration(float64(d) / c.Speed())
+}
+
+// SleepUntil blocks until simulated time reaches t or ctx is cancelled.
+// Time spent paused does not count.
+func (c *Clock) SleepUntil(ctx context.Context, t time.Time) error {
+	for {
+		if resumed := c.pausedOn(); resumed != nil {
+			select {
+			case <-resumed:
+				continue
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		remaining := t.Sub(c.Now())
+		if remaining <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(c.Wall(remaining))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Sleep blocks for the simulated duration d or until ctx is cancelled.
+func (c *Clock) Sleep(ctx context.Context, d time.Duration) error {
+	return c.SleepUntil(ctx, c.Now().Add(d))
+}
+
+// WithTimeout returns a copy of parent cancelled once d of simulated time
+// has passed, with context.DeadlineExceeded as its cause. Like Sleep, time
+// spent paused does not count.
+func (c *Clock) WithTimeout(parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	deadline := c.Now().Add(d)
+	go func() {
+		if c.SleepUntil(ctx, deadline) == nil {
+			cancel(context.DeadlineExceeded)
+		}
+	}()
+	return ctx, func() { cancel(context.Canceled) }
+}
+
+// Ticker sends the simulated time every step of simulated time until ctx is
+// cancelled, when the channel is closed. Ticks are exact multiples of step
+// from the time the ticker started, so timestamps do not drift however fast
+// the clock runs. Ticks are dropped if the receiver falls behind, so a
+// receiver must measure time since the tick it last received rather than
+// assume a tick is step after the previous one.
+func (c *Clock) Ticker(ctx context.Context, step time.Duration) <-chan time.Time {
+	ticks := make(chan time.Time, 1)
+	go func() {
+		defer close(ticks)
+
+		next := c.Now()
+		for {
+			next = next.Add(step)
+			if err := c.SleepUntil(ctx, next); err != nil {
+				return
+			}
+			select {
+			case ticks <- next:
+			default:
+			}
+		}
+	}()
+	return ticks
+}
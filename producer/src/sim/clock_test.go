@@ -0,0 +1,36 @@
+package sim_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"plane-producer/src/sim"
+)
+
+// TestWithTimeout pauses a clock for longer than its timeout takes in wall
+// time and checks the context is only cancelled once simulated time has run
+// on after resuming.
+func TestWithTimeout(t *testing.T) {
+	clock := sim.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), 3600)
+	ctx, cancel := clock.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	clock.Pause()
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out while paused")
+	case <-time.After(100 * time.Millisecond):
+	}
+	clock.Resume()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("never timed out after resuming")
+	}
+	if err := context.Cause(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("cancelled with %v, want %v", err, context.DeadlineExceeded)
+	}
+}
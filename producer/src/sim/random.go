@@ -0,0 +1,40 @@
+package sim
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+// Random is the source of every randomized behaviour in a simulation run,
+// such as taxi times, go-arounds and jitter. Each aircraft draws from its own
+// stream derived from the run's seed and its tail number, so a run is
+// reproducible from the seed alone regardless of the order aircraft are
+// scheduled in.
+type Random struct {
+	seed int64
+}
+
+// NewRandom creates the random source for a run. A seed of zero picks one
+// from the current time; Seed reports the seed actually used so it can be
+// logged and the run replayed.
+func NewRandom(seed int64) *Random {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Random{seed: seed}
+}
+
+// Seed returns the run's seed.
+func (r *Random) Seed() int64 {
+	return r.seed
+}
+
+// For returns a new generator for the named stream, usually a tail number.
+// The same seed and name always produce the same sequence. The generator is
+// not safe for concurrent use.
+func (r *Random) For(name string) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return rand.New(rand.NewSource(r.seed ^ int64(h.Sum64())))
+}
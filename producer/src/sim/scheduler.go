@@ -0,0 +1,267 @@
+package sim
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"plane-producer/src/domain"
+)
+
+// Stepper is anything the scheduler can advance, usually one aircraft. Step
+// moves it forward by dt to the simulated time now and returns its report.
+type Stepper interface {
+	Step(now time.Time, dt time.Duration) (domain.FlightRecord, error)
+}
+
+// Finisher is implemented by steppers that end, such as a flight that has
+// arrived. The scheduler removes them once Done reports true.
+type Finisher interface {
+	Done() bool
+}
+
+// Reporter is implemented by steppers that can report their current state
+// without advancing.
+type Reporter interface {
+	Report() domain.FlightRecord
+}
+
+// minBatch is the fewest steppers worth handing to a worker of their own.
+const minBatch = 64
+
+// Scheduler owns a single ticker and advances every registered Stepper on
+// each tick, instead of each flight running its own goroutine. The steppers
+// are split into contiguous batches across a fixed number of workers, and
+// their reports emitted in registration order once all are done.
+//
+// Each stepper runs on a child of the scheduler's clock, so pausing the
+// scheduler's clock freezes them all while pausing a stepper's own clock
+// freezes only that one: it is not stepped and reports nothing until it is
+// resumed, then carries on from where it stopped.
+type Scheduler struct {
+	clock   *Clock
+	step    time.Duration
+	workers int
+
+	mu        sync.Mutex
+	nextID    int
+	steppers  []*registered
+	onFinish  func(Stepper)
+	afterTick func()
+}
+
+type registered struct {
+	id    int
+	s     Stepper
+	clock *Clock
+	// last is the stepper's time on its previous step, so that a tick
+	// after the ticker dropped some, or after a pause, steps by all the
+	// time since.
+	last time.Time
+}
+
+// NewScheduler creates a scheduler ticking every step of simulated time on
+// clock.
+func NewScheduler(clock *Clock, step time.Duration) *Scheduler {
+	if step <= 0 {
+		step = time.Second
+	}
+	return &Scheduler{clock: clock, step: step, workers: runtime.GOMAXPROCS(0)}
+}
+
+// SetWorkers sets how many steppers are advanced at once; n < 1 uses one
+// per CPU. Steppers must be safe to step alongside each other. It must be
+// set before Run.
+func (s *Scheduler) SetWorkers(n int) {
+	if n < 1 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	s.workers = n
+}
+
+// Add registers s to be advanced from the next tick onwards. The returned
+// function removes it again.
+func (s *Scheduler) Add(stepper Stepper) (remove func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextID
+	s.nextID++
+	s.steppers = append(s.steppers, &registered{id: id, s: stepper, clock: s.clock.Child()})
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, r := range s.steppers {
+			if r.id == id {
+				s.steppers = append(s.steppers[:i:i], s.steppers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Clock returns the clock a registered stepper runs on, which pauses and
+// resumes it alone.
+func (s *Scheduler) Clock(stepper Stepper) (*Clock, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.steppers {
+		if r.s == stepper {
+			return r.clock, true
+		}
+	}
+	return nil, false
+}
+
+// OnFinish sets a function to be called with each stepper that has
+// finished, just before the scheduler removes it. It must be set before
+// Run.
+func (s *Scheduler) OnFinish(fn func(Stepper)) {
+	s.onFinish = fn
+}
+
+// AfterTick sets a function to be called once every stepper has been
+// advanced and its report emitted on each tick, e.g. to flush what was
+// written. It must be set before Run.
+func (s *Scheduler) AfterTick(fn func()) {
+	s.afterTick = fn
+}
+
+// Len returns the number of registered steppers.
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.steppers)
+}
+
+// Run advances every registered stepper on each tick until ctx is
+// cancelled. Reports are passed to emit in registration order; a stepper
+// that returns an error is passed to onError instead and stays registered.
+func (s *Scheduler) Run(ctx context.Context, emit func(domain.FlightRecord), onError func(Stepper, error)) error {
+	for now := range s.clock.Ticker(ctx, s.step) {
+		s.Tick(now, emit, onError)
+		if s.afterTick != nil {
+			s.afterTick()
+		}
+	}
+	return ctx.Err()
+}
+
+// Tick advances every registered stepper that is not paused once, to now
+// on its own clock, by the simulated time since its previous step, or by
+// step on its first. Steppers that have finished are removed after emitting
+// their final report.
+func (s *Scheduler) Tick(now time.Time, emit func(domain.FlightRecord), onError func(Stepper, error)) {
+	s.mu.Lock()
+	steppers := append([]*registered(nil), s.steppers...)
+	s.mu.Unlock()
+
+	results := s.advance(steppers, now)
+	finished := make(map[int]bool)
+	for i, r := range steppers {
+		res := results[i]
+		if res.paused {
+			continue
+		}
+		if res.err != nil {
+			if onError != nil {
+				onError(r.s, res.err)
+			}
+			continue
+		}
+		emit(res.record)
+		if res.done {
+			finished[r.id] = true
+		}
+	}
+	if len(finished) == 0 {
+		return
+	}
+
+	if s.onFinish != nil {
+		for _, r := range steppers {
+			if finished[r.id] {
+				s.onFinish(r.s)
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	kept := s.steppers[:0]
+	for _, r := range s.steppers {
+		if !finished[r.id] {
+			kept = append(kept, r)
+		}
+	}
+	s.steppers = kept
+}
+
+type stepResult struct {
+	record domain.FlightRecord
+	err    error
+	done   bool
+	paused bool
+}
+
+// advance steps every stepper to now, splitting them into one batch per
+// worker, and returns their results in the same order.
+func (s *Scheduler) advance(steppers []*registered, now time.Time) []stepResult {
+	results := make([]stepResult, len(steppers))
+	stepRange := func(from, to int) {
+		for i := from; i < to; i++ {
+			r, st := &results[i], steppers[i]
+			if st.clock.Paused() {
+				r.paused = true
+				continue
+			}
+			at := st.clock.At(now)
+			dt := s.step
+			if !st.last.IsZero() && at.After(st.last) {
+				dt = at.Sub(st.last)
+			}
+			st.last = at
+			r.record, r.err = st.s.Step(at, dt)
+			if f, ok := st.s.(Finisher); ok && r.err == nil {
+				r.done = f.Done()
+			}
+		}
+	}
+
+	workers := min(s.workers, len(steppers)/minBatch)
+	if workers <= 1 {
+		stepRange(0, len(steppers))
+		return results
+	}
+	var wg sync.WaitGroup
+	size := (len(steppers) + workers - 1) / workers
+	for from := 0; from < len(steppers); from += size {
+		wg.Add(1)
+		go func(from, to int) {
+			defer wg.Done()
+			stepRange(from, to)
+		}(from, min(from+size, len(steppers)))
+	}
+	wg.Wait()
+	return results
+}
+
+// Drain removes every registered stepper, passing a final report to emit
+// for each one that implements Reporter. It returns how many were reported.
+func (s *Scheduler) Drain(emit func(domain.FlightRecord)) int {
+	s.mu.Lock()
+	steppers := s.steppers
+	s.steppers = nil
+	s.mu.Unlock()
+
+	var n int
+	for _, r := range steppers {
+		if rep, ok := r.s.(Reporter); ok {
+			emit(rep.Report())
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,312 @@
+package sim_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"plane-producer/src/airports"
+	"plane-producer/src/domain"
+	"plane-producer/src/flight"
+	"plane-producer/src/geo"
+	"plane-producer/src/ground"
+	"plane-producer/src/performance"
+	"plane-producer/src/sim"
+)
+
+// cruisingFleet builds n flights between bundled airports at least 2,000 nm
+// apart and flies them for an hour, so that the ticks benchmarked are of a
+// fleet that is mostly airborne and will stay so.
+func cruisingFleet(b testing.TB, n int, start time.Time) []*flight.Flight {
+	b.Helper()
+	all := airports.Default().All()
+	rng := rand.New(rand.NewSource(1))
+	fleet := make([]*flight.Flight, 0, n)
+	for len(fleet) < n {
+		from, to := all[rng.Intn(len(all))], all[rng.Intn(len(all))]
+		if geo.Distance(from.Position(), to.Position()) < 2000 {
+			continue
+		}
+		tail := fmt.Sprintf("N%dUT", len(fleet)+1)
+		plane, err := domain.NewPlaneDetails(tail, "UT"+tail[1:], from.IATA, to.IATA, domain.WithTimestamp(start))
+		if err != nil {
+			b.Fatal(err)
+		}
+		f, err := flight.New(plane, from, to, ground.DefaultTaxiModel, rand.New(rand.NewSource(rng.Int63())), flight.WithPerformanceProfile(performance.Defaults["A320"]))
+		if err != nil {
+			b.Fatal(err)
+		}
+		fleet = append(fleet, f)
+	}
+
+	const warmup, step = time.Hour, 30 * time.Second
+	for _, f := range fleet {
+		for t := start.Add(step); t.Sub(start) <= warmup; t = t.Add(step) {
+			if _, err := f.Step(t, step); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	return fleet
+}
+
+// recorder is a stepper that remembers the steps it was given.
+type recorder struct{ dts []time.Duration }
+
+func (r *recorder) Step(now time.Time, dt time.Duration) (domain.FlightRecord, error) {
+	r.dts = append(r.dts, dt)
+	return domain.FlightRecord{Timestamp: now.UnixMilli()}, nil
+}
+
+// TestTickStepsByElapsedTime checks that when ticks are dropped the next
+// one steps by all the simulated time since the last, so nothing is lost.
+func TestTickStepsByElapsedTime(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := sim.NewScheduler(sim.NewClock(start, 1), time.Second)
+	r := &recorder{}
+	s.Add(r)
+	for _, at := range []time.Duration{1, 2, 5, 6, 16} {
+		s.Tick(start.Add(at*time.Second), func(domain.FlightRecord) {}, nil)
+	}
+	want := []time.Duration{1, 1, 3, 1, 10}
+	for i := range want {
+		want[i] *= time.Second
+	}
+	if fmt.Sprint(r.dts) != fmt.Sprint(want) {
+		t.Errorf("stepped by %v, want %v", r.dts, want)
+	}
+}
+
+// TestRunCoversSimulatedTime runs a fast clock with a receiver too slow to
+// take every tick, and checks the steps still add up to the time simulated.
+func TestRunCoversSimulatedTime(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := sim.NewScheduler(sim.NewClock(start, 3600), time.Second)
+	r := &recorder{}
+	s.Add(r)
+	var first, last time.Time
+	slow := func(rec domain.FlightRecord) {
+		if first.IsZero() {
+			first = time.UnixMilli(rec.Timestamp)
+		}
+		last = time.UnixMilli(rec.Timestamp)
+		time.Sleep(time.Millisecond)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	s.Run(ctx, slow, nil)
+
+	if len(r.dts) < 2 {
+		t.Fatalf("only %d ticks", len(r.dts))
+	}
+	var total time.Duration
+	for _, dt := range r.dts[1:] {
+		total += dt
+	}
+	if want := last.Sub(first); total != want {
+		t.Errorf("stepped %v in all, but %v passed between the first and last ticks", total, want)
+	}
+}
+
+// TestPauseStepper checks that a stepper whose own clock is paused is
+// skipped while the others carry on, and that on resuming it steps on from
+// where it stopped, behind the fleet by the time it was paused.
+func TestPauseStepper(t *testing.T) {
+	fleet := sim.NewClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), 60)
+	s := sim.NewScheduler(fleet, time.Second)
+	paused, running := &recorder{}, &recorder{}
+	s.Add(paused)
+	s.Add(running)
+	clock, ok := s.Clock(paused)
+	if !ok {
+		t.Fatal("no clock for a registered stepper")
+	}
+
+	var times []time.Time
+	emit := func(r domain.FlightRecord) { times = append(times, time.UnixMilli(r.Timestamp)) }
+	s.Tick(fleet.Now(), emit, nil)
+	before := fleet.Now()
+	clock.Pause()
+	for i := 0; i < 4; i++ {
+		time.Sleep(20 * time.Millisecond)
+		s.Tick(fleet.Now(), emit, nil)
+	}
+	if len(paused.dts) != 1 || len(running.dts) != 5 {
+		t.Fatalf("stepped the paused stepper %d times and the other %d, want 1 and 5", len(paused.dts), len(running.dts))
+	}
+	clock.Resume()
+	pausedFor := fleet.Now().Sub(before)
+
+	times = nil
+	s.Tick(fleet.Now(), emit, nil)
+	if len(times) != 2 {
+		t.Fatalf("%d reports after resuming, want 2", len(times))
+	}
+	if lag := times[1].Sub(times[0]); (lag - pausedFor).Abs() > time.Second {
+		t.Errorf("resumed stepper is %v behind the fleet, want the %v it was paused", lag, pausedFor)
+	}
+	if dt := paused.dts[1]; dt > 2*time.Second {
+		t.Errorf("resumed stepper stepped by %v, want only the time it ran", dt)
+	}
+}
+
+// TestFleet flies a fleet big enough to be split across workers to the
+// end, and checks every aircraft reported on its own until it arrived and
+// was taken off the scheduler.
+func TestFleet(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	db := airports.Default()
+	jfk, _ := db.Lookup("JFK")
+	bos, _ := db.Lookup("BOS")
+
+	const n, step = 200, 10 * time.Second
+	s := sim.NewScheduler(sim.NewClock(start, 1), step)
+	s.SetWorkers(4)
+	for i := 0; i < n; i++ {
+		from, to := jfk, bos
+		if i%2 == 1 {
+			from, to = bos, jfk
+		}
+		tail := fmt.Sprintf("N%dUT", i+1)
+		plane, err := domain.NewPlaneDetails(tail, "UT"+tail[1:], from.IATA, to.IATA, domain.WithTimestamp(start))
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := flight.New(plane, from, to, ground.DefaultTaxiModel, rand.New(rand.NewSource(int64(i))), flight.WithPerformanceProfile(performance.Defaults["A320"]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.Add(f)
+	}
+	finished := make(map[string]bool)
+	s.OnFinish(func(st sim.Stepper) { finished[st.(*flight.Flight).Plane().FlightID()] = true })
+
+	last := make(map[string]domain.FlightRecord)
+	emit := func(r domain.FlightRecord) {
+		if prev, ok := last[r.FlightID]; ok && r.Timestamp <= prev.Timestamp {
+			t.Errorf("%s reported at %d after %d", r.FlightID, r.Timestamp, prev.Timestamp)
+		}
+		last[r.FlightID] = r
+	}
+	onError := func(_ sim.Stepper, err error) { t.Error(err) }
+	now := start
+	for s.Len() > 0 {
+		if now.Sub(start) > 6*time.Hour {
+			t.Fatalf("%d aircraft still flying after six hours", s.Len())
+		}
+		now = now.Add(step)
+		s.Tick(now, emit, onError)
+	}
+
+	if len(last) != n || len(finished) != n {
+		t.Fatalf("%d aircraft reported and %d finished, want %d", len(last), len(finished), n)
+	}
+	for id, r := range last {
+		if r.Status != domain.Idle {
+			t.Errorf("%s last reported %s", id, r.Status)
+		}
+	}
+}
+
+// TestRunCancelDrains cancels a running fleet and checks Run stops with
+// the context's error, and Drain then flushes a final report of every
+// aircraft where it had got to.
+func TestRunCancelDrains(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := sim.NewScheduler(sim.NewClock(start, 600), time.Second)
+	for _, f := range cruisingFleet(t, 3, start) {
+		s.Add(f)
+	}
+	last := make(map[string]domain.FlightRecord)
+	emit := func(r domain.FlightRecord) { last[r.FlightID] = r }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	if err := s.Run(ctx, emit, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run returned %v, want %v", err, context.Canceled)
+	}
+
+	var final []domain.FlightRecord
+	if n := s.Drain(func(r domain.FlightRecord) { final = append(final, r) }); n != 3 || len(final) != 3 {
+		t.Fatalf("drained %d reports, want 3", len(final))
+	}
+	if s.Len() != 0 {
+		t.Errorf("%d aircraft still registered after draining", s.Len())
+	}
+	for _, r := range final {
+		if r != last[r.FlightID] {
+			t.Errorf("%s: final report %+v, want its last one %+v", r.FlightID, r, last[r.FlightID])
+		}
+	}
+}
+
+// failing is a stepper whose every step fails.
+type failing struct{ steps int }
+
+func (f *failing) Step(time.Time, time.Duration) (domain.FlightRecord, error) {
+	f.steps++
+	return domain.FlightRecord{}, errors.New("cannot encode")
+}
+
+// TestTickError checks that an aircraft failing to step is handed to
+// onError, without a report and without stopping the rest of the fleet,
+// and is stepped again on the next tick.
+func TestTickError(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := sim.NewScheduler(sim.NewClock(start, 1), time.Second)
+	before, bad, after := &recorder{}, &failing{}, &recorder{}
+	s.Add(before)
+	s.Add(bad)
+	s.Add(after)
+
+	var reports int
+	var failed []sim.Stepper
+	emit := func(domain.FlightRecord) { reports++ }
+	onError := func(st sim.Stepper, _ error) { failed = append(failed, st) }
+	for i := 1; i <= 3; i++ {
+		s.Tick(start.Add(time.Duration(i)*time.Second), emit, onError)
+	}
+
+	if reports != 6 || len(before.dts) != 3 || len(after.dts) != 3 {
+		t.Errorf("%d reports, steps %d and %d, want 6, 3 and 3", reports, len(before.dts), len(after.dts))
+	}
+	if len(failed) != 3 || bad.steps != 3 {
+		t.Errorf("%d errors from %d steps, want 3 from 3", len(failed), bad.steps)
+	}
+	for _, st := range failed {
+		if st != bad {
+			t.Errorf("error passed with %T, want the failing stepper", st)
+		}
+	}
+	if s.Len() != 3 {
+		t.Errorf("%d steppers registered, want 3", s.Len())
+	}
+}
+
+func BenchmarkTick(b *testing.B) {
+	for _, n := range []int{100, 1000} {
+		for _, workers := range []int{1, 0} {
+			b.Run(fmt.Sprintf("fleet=%d/workers=%d", n, workers), func(b *testing.B) {
+				start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+				s := sim.NewScheduler(sim.NewClock(start, 1), time.Second)
+				s.SetWorkers(workers)
+				for _, f := range cruisingFleet(b, n, start) {
+					s.Add(f)
+				}
+				now := start.Add(time.Hour)
+				discard := func(domain.FlightRecord) {}
+
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					now = now.Add(time.Second)
+					s.Tick(now, discard, nil)
+				}
+				b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*n), "ns/aircraft")
+			})
+		}
+	}
+}
@@ -0,0 +1,772 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"plane-producer/src/admin"
+	"plane-producer/src/airports"
+	"plane-producer/src/api"
+	"plane-producer/src/capture"
+	"plane-producer/src/config"
+	"plane-producer/src/control"
+	"plane-producer/src/domain"
+	"plane-producer/src/fleet"
+	"plane-producer/src/flight"
+	"plane-producer/src/geo"
+	"plane-producer/src/geofence"
+	"plane-producer/src/ground"
+	"plane-producer/src/performance"
+	"plane-producer/src/reporting"
+	"plane-producer/src/schedule"
+	"plane-producer/src/shard"
+	"plane-producer/src/sim"
+	"plane-producer/src/weather"
+)
+
+// minRouteNm keeps generated fleets from flying pointlessly short hops.
+const minRouteNm = 150
+
+// maxRouteDraws is how many random destinations generateFleet tries before
+// looking through every airport for one far enough away.
+const maxRouteDraws = 100
+
+func runSimulate(args []string) error {
+	fs := flag.NewFlagSet("simulate", flag.ExitOnError)
+	configPath := fs.String("config", "", "YAML configuration file")
+	fleetSize := fs.Int("fleet", 10, "number of flights to generate when no schedule is configured")
+	legs := fs.Int("legs", 1, "number of legs each generated aircraft flies, turning around in between")
+	sinkType := fs.String("sink", "", "sink type, overriding the configuration")
+	speed := fs.Float64("speed", 0, "time acceleration factor, overriding the configuration")
+	seed := fs.Int64("seed", 0, "random seed, overriding the configuration")
+	duration := fs.Duration("duration", 0, "stop after this much simulated time (0 runs until every flight arrives)")
+	capturePath := fs.String("capture", "", "also write every record to this file for replay, overriding the configuration")
+	fs.Parse(args)
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return err
+	}
+	if *sinkType != "" {
+		cfg.Sink.Type = *sinkType
+	}
+	if *speed > 0 {
+		cfg.Simulation.Speed = *speed
+	}
+	if *seed != 0 {
+		cfg.Simulation.Seed = *seed
+	}
+	if *capturePath != "" {
+		cfg.Capture = *capturePath
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	world, err := loadWorld(cfg)
+	if err != nil {
+		return err
+	}
+
+	tracker, feed := api.NewTracker(), api.NewFeed()
+	var probes *admin.Server
+	if cfg.Admin.Addr != "" {
+		probes = admin.NewServer(cfg.Admin.Addr, "sink", "schedule")
+		flights := api.NewHandler(tracker, feed, world.airports)
+		probes.Handle("/flights", flights)
+		probes.Handle("/flights/", flights)
+		probes.Handle("/airports/", flights)
+		probes.Handle("/routes/", flights)
+		probes.Handle("/geofences", geofence.Handler(world.fences))
+		probes.Handle("/geofences/", geofence.Handler(world.fences))
+		go func() {
+			if err := probes.Run(ctx); err != nil {
+				log.Printf("admin server: %v", err)
+			}
+		}()
+	}
+	if cfg.GRPC.Addr != "" {
+		go func() {
+			if err := api.NewGRPCServer(tracker, feed, world.airports).Serve(ctx, cfg.GRPC.Addr); err != nil {
+				log.Printf("grpc server: %v", err)
+			}
+		}()
+	}
+	markReady := func(component string) {
+		if probes != nil {
+			probes.MarkReady(component)
+		}
+	}
+
+	random := sim.NewRandom(cfg.Simulation.Seed)
+	log.Printf("simulation seed %d", random.Seed())
+
+	epoch, err := cfg.Simulation.Start(time.Now())
+	if err != nil {
+		return err
+	}
+	clock := sim.NewClock(epoch, cfg.Simulation.Speed)
+	log.Printf("simulated time starts at %s", epoch.Format(time.RFC3339))
+	if *duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = clock.WithTimeout(ctx, *duration)
+		defer cancel()
+	}
+
+	var flights []schedule.Flight
+	if cfg.Schedule != "" {
+		flights, err = schedule.Load(cfg.Schedule)
+		if err != nil {
+			return err
+		}
+	} else {
+		flights, err = generateFleet(*fleetSize, *legs, world.airports, random.For("fleet"), clock.Now())
+		if err != nil {
+			return err
+		}
+	}
+	markReady("schedule")
+
+	// Writes get their own context so that a shutdown signal stops the
+	// simulation but still lets the final reports and flush through, until
+	// the grace period runs out.
+	writeCtx, cancelWrites := context.WithCancel(context.Background())
+	defer cancelWrites()
+
+	out, err := newOutput(ctx, cfg.Sink, epoch.Location())
+	if err != nil {
+		return err
+	}
+	markReady("sink")
+
+	var tee *capture.Writer
+	if cfg.Capture != "" {
+		hash, err := cfg.Hash()
+		if err != nil {
+			return err
+		}
+		tee, err = capture.Create(cfg.Capture, capture.Metadata{
+			Seed:       random.Seed(),
+			ConfigHash: hash,
+			Start:      clock.Now(),
+			Speed:      clock.Speed(),
+		})
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := tee.Close(); err != nil {
+				log.Print(err)
+			}
+		}()
+	}
+	// captureRecord writes to the capture, if there is one, the JSON form of
+	// whatever went to the sink.
+	captureRecord := func(flightID string, data []byte) {
+		if tee == nil {
+			return
+		}
+		if err := tee.Write(data); err != nil {
+			log.Printf("capturing %s: %v", flightID, err)
+		}
+	}
+
+	// Events such as geofence crossings are always JSON, whatever the report
+	// format, and are told apart from reports by their "event" field. They
+	// are partitioned like the report of the flight they concern.
+	emitEvent := func(record domain.FlightRecord, event any) {
+		data, err := json.Marshal(event)
+		if err != nil {
+			log.Printf("encoding event for %s: %v", record.FlightID, err)
+			return
+		}
+		out.write(writeCtx, out.partition.NewRecord(record, data))
+		captureRecord(record.FlightID, data)
+	}
+
+	// Reports are written to the sink, and captured, as often as the
+	// reporting policy says, but every one is tracked, published and
+	// checked against the geofences.
+	gate := reporting.NewGate(reporting.Policy{
+		Interval: cfg.Reporting.Interval,
+		Airlines: cfg.Reporting.Airlines,
+		Aircraft: cfg.Reporting.Aircraft,
+		Adaptive: cfg.Reporting.Adaptive,
+	})
+	sequences := reporting.NewSequences()
+	write := func(record domain.FlightRecord) {
+		record.Sequence = sequences.Next(record.TailNum)
+		data, err := out.encoder.Encode(record)
+		if err != nil {
+			log.Printf("encoding report for %s: %v", record.FlightID, err)
+			return
+		}
+		out.write(writeCtx, out.partition.NewRecord(record, data))
+		if tee != nil {
+			if data, err = json.Marshal(record); err == nil {
+				captureRecord(record.FlightID, data)
+			}
+		}
+	}
+	registry := fleet.NewRegistry()
+	parking := fleet.NewParking()
+	emit := func(record domain.FlightRecord) {
+		tracker.Update(record)
+		feed.Publish(record)
+		fl, ok := registry.ByFlightID(record.FlightID)
+		var due bool
+		if ok && fl.ReportInterval() > 0 {
+			due = gate.DueEvery(record, fl.ReportInterval())
+		} else {
+			due = gate.Due(record)
+		}
+		if due {
+			write(record)
+		}
+		if ok {
+			for _, m := range fl.Milestones() {
+				emitEvent(record, flight.MilestoneOf(m, record))
+				if m == flight.Arrived {
+					emitEvent(record, fl.Phases(record))
+				}
+			}
+			for _, c := range fl.Encounters() {
+				emitEvent(record, flight.TurbulenceOf(c, record))
+			}
+		}
+		for _, event := range world.fences.Check(record) {
+			emitEvent(record, event)
+		}
+	}
+
+	callsigns, err := schedule.NewCallsigns(cfg.Simulation.Airlines, random.For("callsigns"))
+	if err != nil {
+		return err
+	}
+	scheduler := sim.NewScheduler(clock, cfg.Simulation.ReportInterval)
+	scheduler.SetWorkers(cfg.Simulation.Workers)
+	scheduler.AfterTick(func() { out.flush(writeCtx) })
+
+	// launched holds the schedule entry behind each running flight, so the
+	// next leg of its rotation can follow once it is in, and how to take it
+	// out of the scheduler.
+	var launchedMu sync.Mutex
+	launched := make(map[*flight.Flight]running)
+
+	pending := schedule.NewPending(flights)
+
+	// cancelled reports whether a flight due to depart was cancelled while
+	// waiting, writing its cancellation record if so.
+	cancelled := func(f schedule.Flight) bool {
+		if pending.Launch(f) {
+			return false
+		}
+		log.Printf("%s cancelled before departure", f.FlightID)
+		record := domain.FlightRecord{
+			TailNum:     f.TailNum,
+			FlightID:    f.FlightID,
+			Timestamp:   clock.Now().UnixMilli(),
+			Origin:      f.Origin,
+			Destination: f.Destination,
+		}
+		emitEvent(record, flight.CancellationOf(record))
+		return true
+	}
+	start := func(f schedule.Flight) {
+		generated := f.FlightID == ""
+		if generated {
+			id, err := callsigns.Next()
+			if err != nil {
+				log.Printf("launching %s from %s: %v", f.TailNum, f.Origin, err)
+				return
+			}
+			f.FlightID = id
+		}
+		fl, err := world.newFlight(f, clock.Now(), random)
+		if err == nil {
+			err = registry.Add(fl)
+		}
+		if err != nil {
+			if generated {
+				callsigns.Release(f.FlightID)
+			}
+			log.Printf("launching %s: %v", f.FlightID, err)
+			return
+		}
+		if !generated {
+			callsigns.Reserve(f.FlightID)
+		}
+		if pending.Held(f) {
+			fl.SetTakeOffClearance(false)
+		}
+		parking.Leave(f.TailNum)
+		launchedMu.Lock()
+		launched[fl] = running{leg: f, remove: scheduler.Add(fl)}
+		launchedMu.Unlock()
+	}
+
+	// With sharding, only departures in shards this instance holds are
+	// flown, each claimed in the lease table first so that no other
+	// instance flies it too. Those due while their shard was held elsewhere
+	// or by nobody wait in overdue, to be flown if the shard comes here
+	// before another instance has claimed them.
+	var coordinator *shard.Coordinator
+	var overdueMu sync.Mutex
+	overdue := make(map[int][]schedule.Flight)
+	claimed := func(f schedule.Flight) bool {
+		if coordinator == nil {
+			return true
+		}
+		if coordinator.Owns(f.TailNum) {
+			ok, err := coordinator.Claim(ctx, f)
+			if err == nil {
+				return ok
+			}
+			log.Printf("claiming %s: %v", f.FlightID, err)
+		}
+		overdueMu.Lock()
+		defer overdueMu.Unlock()
+		s := coordinator.Shard(f.TailNum)
+		overdue[s] = append(overdue[s], f)
+		return false
+	}
+	if cfg.Shard.Table != "" {
+		table, err := shard.NewDynamoDB(ctx, shard.DynamoDBConfig{Table: cfg.Shard.Table, AWSOptions: awsOptions(cfg.Shard.AWS)})
+		if err != nil {
+			return err
+		}
+		instance := cfg.Shard.Instance
+		if instance == "" {
+			host, _ := os.Hostname()
+			instance = fmt.Sprintf("%s-%d", host, os.Getpid())
+		}
+		coordinator = shard.NewCoordinator(table, shard.Config{Instance: instance, Shards: cfg.Shard.Count, TTL: cfg.Shard.LeaseTTL})
+		coordinator.OnAcquire(func(s int) {
+			overdueMu.Lock()
+			due := overdue[s]
+			delete(overdue, s)
+			overdueMu.Unlock()
+			for _, f := range due {
+				if claimed(f) {
+					start(f)
+				}
+			}
+		})
+		log.Printf("sharding %d ways as %s", cfg.Shard.Count, instance)
+
+		// The leases are given back on the way out, before the process
+		// ends.
+		coordCtx, stopCoordinating := context.WithCancel(ctx)
+		coordinated := make(chan struct{})
+		go func() {
+			defer close(coordinated)
+			coordinator.Run(coordCtx)
+		}()
+		defer func() {
+			stopCoordinating()
+			<-coordinated
+		}()
+	}
+
+	launch := func(f schedule.Flight) {
+		if !cancelled(f) && claimed(f) {
+			start(f)
+		}
+	}
+
+	runCtx, finish := context.WithCancel(ctx)
+	defer finish()
+
+	// waiting counts flights due to depart outside the schedule, such as
+	// the next legs of rotations, which keep the simulation going although
+	// the scheduler may have nothing to fly.
+	var waiting atomic.Int64
+	// depart launches such a flight at its departure time. One whose flight
+	// ID is taken by then is given a callsign instead.
+	depart := func(f schedule.Flight) {
+		pending.Add(f)
+		waiting.Add(1)
+		go func() {
+			defer waiting.Add(-1)
+			if err := clock.SleepUntil(runCtx, f.Departure); err != nil {
+				return
+			}
+			if cancelled(f) {
+				return
+			}
+			if callsigns.InUse(f.FlightID) {
+				f.FlightID = ""
+			}
+			start(f)
+		}()
+	}
+
+	scheduler.OnFinish(func(s sim.Stepper) {
+		fl := s.(*flight.Flight)
+		registry.Remove(fl)
+		callsigns.Release(fl.Plane().FlightID())
+		gate.Forget(fl.Plane().FlightID())
+		tracker.Remove(fl.Plane().FlightID())
+
+		launchedMu.Lock()
+		leg := launched[fl].leg
+		delete(launched, fl)
+		launchedMu.Unlock()
+
+		// A cancelled flight never left, so the rest of its rotation is
+		// off too.
+		record := fl.Report()
+		if fl.Cancelled() {
+			emitEvent(record, flight.CancellationOf(record))
+			parking.Park(fleet.Parked{Record: record, Airport: record.Origin, Gate: record.Gate})
+			return
+		}
+		next, ok := leg.NextLeg(clock.Now().Add(cfg.Simulation.Turnaround))
+		parking.Park(fleet.Parked{Record: record, Airport: record.Destination, Gate: record.Gate, Next: next.Departure})
+		if ok {
+			depart(next)
+		}
+	})
+
+	// Parked aircraft send heartbeats until they next fly.
+	if cfg.Reporting.Heartbeat > 0 {
+		go func() {
+			for now := range clock.Ticker(runCtx, cfg.Reporting.Heartbeat) {
+				for _, p := range parking.All() {
+					emitEvent(p.Record, fleet.HeartbeatOf(p, now))
+				}
+			}
+		}()
+	}
+
+	if probes != nil {
+		if cfg.Admin.ControlToken == "" {
+			log.Print("control API disabled: admin.controlToken is not set")
+		} else {
+			ctl := simControl{
+				Pending: pending,
+				add: func(f schedule.Flight) error {
+					if f.Departure.IsZero() {
+						f.Departure = clock.Now()
+					}
+					if err := f.Validate(); err != nil {
+						return err
+					}
+					for _, code := range append([]string{f.Origin, f.Destination}, f.Rotation...) {
+						if _, ok := world.airports.Lookup(code); !ok {
+							return fmt.Errorf("unknown airport %q", code)
+						}
+					}
+					if _, ok := registry.ByFlightID(f.FlightID); ok && f.FlightID != "" {
+						return fleet.ErrDuplicateFlightID
+					}
+					depart(f)
+					return nil
+				},
+				remove: func(flightID string) bool {
+					fl, ok := registry.ByFlightID(flightID)
+					if !ok {
+						return pending.CancelScheduled(flightID)
+					}
+					launchedMu.Lock()
+					r := launched[fl]
+					delete(launched, fl)
+					launchedMu.Unlock()
+					if r.remove != nil {
+						r.remove()
+					}
+					fl.Release()
+					registry.Remove(fl)
+					callsigns.Release(flightID)
+					gate.Forget(flightID)
+					tracker.Remove(flightID)
+					log.Printf("%s removed", flightID)
+					return true
+				},
+				clock: clock,
+				clocks: func(flightID string) (*sim.Clock, bool) {
+					fl, ok := registry.ByFlightID(flightID)
+					if !ok {
+						return nil, false
+					}
+					return scheduler.Clock(fl)
+				},
+			}
+			probes.Handle("/control/", control.NewHandler(registry, ctl, world.airports, cfg.Admin.ControlToken))
+		}
+	}
+
+	go func() {
+		if err := schedule.Run(runCtx, clock, flights, launch); err != nil {
+			return
+		}
+		// A sharded instance keeps running, since it may yet be handed
+		// more shards.
+		if coordinator != nil {
+			return
+		}
+		for scheduler.Len() > 0 || waiting.Load() > 0 {
+			if err := clock.Sleep(runCtx, cfg.Simulation.ReportInterval); err != nil {
+				return
+			}
+		}
+		finish()
+	}()
+
+	log.Printf("simulating %d flights at %gx to %s sink", len(flights), clock.Speed(), cfg.Sink.Type)
+	err = scheduler.Run(runCtx, emit, func(_ sim.Stepper, err error) {
+		log.Print(err)
+	})
+	if err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	// Final reports are written whatever the reporting policy, and marked so
+	// consumers know no more are coming.
+	final := func(record domain.FlightRecord) {
+		gate.Forget(record.FlightID)
+		record.Final = true
+		emit(record)
+	}
+	return shutdown(scheduler, out, final, cfg.ShutdownGrace, cancelWrites)
+}
+
+// shutdown emits a final report for every aircraft still flying and flushes
+// the sink, giving up once grace has passed.
+func shutdown(scheduler *sim.Scheduler, out *output, emit func(domain.FlightRecord), grace time.Duration, cancelWrites context.CancelFunc) error {
+	deadline := time.AfterFunc(grace, cancelWrites)
+	defer deadline.Stop()
+
+	done := make(chan error, 1)
+	go func() {
+		if n := scheduler.Drain(emit); n > 0 {
+			log.Printf("sent final reports for %d aircraft", n)
+		}
+		done <- out.Close()
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("closing sink: %w", err)
+		}
+		return nil
+	case <-time.After(grace):
+		return fmt.Errorf("sink not flushed within the %v shutdown grace period", grace)
+	}
+}
+
+// running is what the simulation keeps about a flight in the air: its
+// schedule entry, for the next leg of its rotation, and how to take it out
+// of the scheduler.
+type running struct {
+	leg    schedule.Flight
+	remove func()
+}
+
+// simControl carries out control API requests on a running simulation.
+type simControl struct {
+	*schedule.Pending
+	add    func(schedule.Flight) error
+	remove func(flightID string) bool
+	clock  *sim.Clock
+	clocks func(flightID string) (*sim.Clock, bool)
+}
+
+func (c simControl) Add(f schedule.Flight) error {
+	return c.add(f)
+}
+
+func (c simControl) Remove(flightID string) bool {
+	return c.remove(flightID)
+}
+
+func (c simControl) SetPaused(paused bool) {
+	setPaused(c.clock, paused)
+}
+
+func (c simControl) SetFlightPaused(flightID string, paused bool) bool {
+	clock, ok := c.clocks(flightID)
+	if ok {
+		setPaused(clock, paused)
+	}
+	return ok
+}
+
+func setPaused(clock *sim.Clock, paused bool) {
+	if paused {
+		clock.Pause()
+	} else {
+		clock.Resume()
+	}
+}
+
+// world is the reference data flights are built from, and the airports'
+// runways and gates they share.
+type world struct {
+	airports *airports.Database
+	profiles performance.Profiles
+	types    []string
+	taxi     ground.TaxiModel
+	fences   *geofence.Monitor
+	nav      geo.Navigation
+	earth    geo.EarthModel
+	noise    flight.Noise
+	weather  weather.Weather
+	runways  *ground.Runways
+	gates    *ground.Gates
+	// goArounds is the chance of an approach ending in a go-around.
+	goArounds float64
+}
+
+func loadWorld(cfg config.Config) (*world, error) {
+	w := &world{
+		airports: airports.Default(),
+		profiles: performance.Defaults,
+		taxi:     ground.DefaultTaxiModel,
+		runways:  ground.NewRunways(),
+		gates:    ground.NewGates(nil, cfg.Simulation.Turnaround),
+	}
+
+	var err error
+	if cfg.Airports != "" {
+		if w.airports, err = airports.Load(cfg.Airports); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Performance != "" {
+		if w.profiles, err = performance.Load(cfg.Performance); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.TaxiTimes != "" {
+		if w.taxi, err = ground.LoadTaxiModel(cfg.TaxiTimes); err != nil {
+			return nil, err
+		}
+	}
+	var fences []geofence.Fence
+	if cfg.Geofences != "" {
+		if fences, err = geofence.Load(cfg.Geofences, w.airports); err != nil {
+			return nil, err
+		}
+	}
+	w.fences = geofence.NewMonitor(fences)
+	if cfg.Weather != "" {
+		if w.weather, err = weather.Load(cfg.Weather); err != nil {
+			return nil, err
+		}
+	}
+
+	if w.nav, err = geo.ParseNavigation(cfg.Simulation.Navigation); err != nil {
+		return nil, err
+	}
+	if w.earth, err = geo.ParseEarthModel(cfg.Simulation.EarthModel); err != nil {
+		return nil, err
+	}
+	w.noise = flight.Noise(cfg.Simulation.Noise)
+	w.goArounds = cfg.Simulation.GoAroundRate
+	w.types = w.profiles.Types()
+	return w, nil
+}
+
+// newFlight builds the flight model for a scheduled departure. The aircraft
+// type is drawn from the tail number's random stream, so a tail always flies
+// the same type for a given seed.
+func (w *world) newFlight(f schedule.Flight, now time.Time, random *sim.Random) (*flight.Flight, error) {
+	origin, ok := w.airports.Lookup(f.Origin)
+	if !ok {
+		return nil, fmt.Errorf("unknown origin airport %q", f.Origin)
+	}
+	destination, ok := w.airports.Lookup(f.Destination)
+	if !ok {
+		return nil, fmt.Errorf("unknown destination airport %q", f.Destination)
+	}
+
+	rng := random.For(f.TailNum)
+	profile := w.profiles[w.types[rng.Intn(len(w.types))]]
+
+	plane, err := domain.NewPlaneDetails(f.TailNum, f.FlightID, f.Origin, f.Destination, domain.WithTimestamp(now))
+	if err != nil {
+		return nil, err
+	}
+	nav := w.nav
+	if f.Navigation != "" {
+		nav = f.Navigation
+	}
+	opts := []flight.Option{flight.WithPerformanceProfile(profile), flight.WithNavigation(nav), flight.WithEarthModel(w.earth), flight.WithRunways(w.runways), flight.WithGates(w.gates)}
+	if w.goArounds > 0 {
+		opts = append(opts, flight.WithGoArounds(w.goArounds))
+	}
+	if w.noise != (flight.Noise{}) {
+		opts = append(opts, flight.WithNoise(w.noise, random.For(f.TailNum+"/noise")))
+	}
+	if w.weather.Wind.Knots > 0 || len(w.weather.Cells) > 0 {
+		opts = append(opts, flight.WithWeather(w.weather))
+	}
+	return flight.New(plane, origin, destination, w.taxi, rng, opts...)
+}
+
+// generateFleet makes up n aircraft flying legs legs each between random
+// airports, departing a minute apart from start. Their flight IDs are left
+// for Callsigns to fill in at launch. It fails if some airport drawn has no
+// other at least minRouteNm away.
+func generateFleet(n, legs int, db *airports.Database, rng *rand.Rand, start time.Time) ([]schedule.Flight, error) {
+	all := db.All()
+	if len(all) < 2 {
+		return nil, nil
+	}
+	far := func(from, to airports.Airport) bool {
+		return geo.Distance(from.Position(), to.Position()) >= minRouteNm
+	}
+	// next picks an airport worth flying to from the one given.
+	next := func(from airports.Airport) (airports.Airport, error) {
+		for i := 0; i < maxRouteDraws; i++ {
+			if to := all[rng.Intn(len(all))]; far(from, to) {
+				return to, nil
+			}
+		}
+		for _, to := range all {
+			if far(from, to) {
+				return to, nil
+			}
+		}
+		return airports.Airport{}, fmt.Errorf("generating fleet: no airport is at least %d nm from %s", minRouteNm, from.IATA)
+	}
+
+	flights := make([]schedule.Flight, 0, n)
+	for i := 0; i < n; i++ {
+		origin := all[rng.Intn(len(all))]
+		destination, err := next(origin)
+		if err != nil {
+			return nil, err
+		}
+		f := schedule.Flight{
+			TailNum:     fmt.Sprintf("N%dUT", 101+i),
+			Origin:      origin.IATA,
+			Destination: destination.IATA,
+			Departure:   start.Add(time.Duration(i) * time.Minute),
+		}
+		for at := destination; len(f.Rotation) < legs-1; {
+			if at, err = next(at); err != nil {
+				return nil, err
+			}
+			f.Rotation = append(f.Rotation, at.IATA)
+		}
+		flights = append(flights, f)
+	}
+	return flights, nil
+}
@@ -0,0 +1,82 @@
+package sink
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// AWSOptions are the settings shared by the AWS-backed sinks. Endpoint
+// overrides the service endpoint, e.g. "http://localhost:4566" to use
+// LocalStack, and InsecureSkipVerify accepts self-signed certificates on
+// such local endpoints.
+//
+// Credentials come from the standard chain (environment, shared config
+// Profile, web identity, container and instance roles). When RoleARN is set
+// those credentials are used to assume the role, e.g. to write to a stream
+// in another account, passing ExternalID if the role requires one.
+type AWSOptions struct {
+	Region             string
+	Endpoint           string
+	InsecureSkipVerify bool
+
+	Profile     string
+	RoleARN     string
+	ExternalID  string
+	SessionName string
+}
+
+// DefaultRoleSessionName is used when assuming a role without a session
+// name.
+const DefaultRoleSessionName = "plane-producer"
+
+// LoadAWSConfig loads the default AWS credential chain, applying any
+// overrides from opts.
+func LoadAWSConfig(ctx context.Context, opts AWSOptions) (aws.Config, error) {
+	var loadOpts []func(*config.LoadOptions) error
+	if opts.Region != "" {
+		loadOpts = append(loadOpts, config.WithRegion(opts.Region))
+	}
+	if opts.Profile != "" {
+		loadOpts = append(loadOpts, config.WithSharedConfigProfile(opts.Profile))
+	}
+	if opts.Endpoint != "" {
+		loadOpts = append(loadOpts, config.WithBaseEndpoint(opts.Endpoint))
+	}
+	if opts.InsecureSkipVerify {
+		client := awshttp.NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
+			if tr.TLSClientConfig == nil {
+				tr.TLSClientConfig = &tls.Config{}
+			}
+			tr.TLSClientConfig.InsecureSkipVerify = true
+		})
+		loadOpts = append(loadOpts, config.WithHTTPClient(client))
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("loading aws config: %w", err)
+	}
+
+	if opts.RoleARN != "" {
+		sessionName := opts.SessionName
+		if sessionName == "" {
+			sessionName = DefaultRoleSessionName
+		}
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(awsCfg), opts.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = sessionName
+			if opts.ExternalID != "" {
+				o.ExternalID = aws.String(opts.ExternalID)
+			}
+		})
+		awsCfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+	return awsCfg, nil
+}
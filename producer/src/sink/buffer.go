@@ -0,0 +1,78 @@
+package sink
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Buffer collects records and writes them to a BatchSink in batches of up to
+// BatchSize records, or every FlushInterval if that comes first.
+type Buffer struct {
+	next          BatchSink
+	batchSize     int
+	flushInterval time.Duration
+
+	mu      sync.Mutex
+	pending []Record
+	timer   *time.Timer
+}
+
+// NewBuffer wraps next with a buffer. A zero flushInterval only flushes on
+// full batches and on Close.
+func NewBuffer(next BatchSink, batchSize int, flushInterval time.Duration) *Buffer {
+	if batchSize <= 0 {
+		batchSize = maxPutRecords
+	}
+	return &Buffer{next: next, batchSize: batchSize, flushInterval: flushInterval}
+}
+
+// Put adds a record to the buffer, writing the batch out if it is full.
+func (b *Buffer) Put(ctx context.Context, record Record) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pending = append(b.pending, record)
+	if len(b.pending) >= b.batchSize {
+		return b.flushLocked(ctx)
+	}
+	if len(b.pending) == 1 && b.flushInterval > 0 {
+		b.timer = time.AfterFunc(b.flushInterval, func() {
+			if err := b.Flush(context.Background()); err != nil {
+				log.Printf("buffer: %v", err)
+			}
+		})
+	}
+	return nil
+}
+
+// Flush writes out any buffered records.
+func (b *Buffer) Flush(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.flushLocked(ctx)
+}
+
+func (b *Buffer) flushLocked(ctx context.Context) error {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.pending) == 0 {
+		return nil
+	}
+
+	batch := b.pending
+	b.pending = nil
+	return b.next.PutBatch(ctx, batch)
+}
+
+// Close flushes buffered records and closes the wrapped sink.
+func (b *Buffer) Close() error {
+	err := b.Flush(context.Background())
+	if cerr := b.next.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
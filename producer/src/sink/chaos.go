@@ -0,0 +1,100 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ChaosConfig sets the fraction of records, between 0 and 1, that Chaos
+// duplicates, delivers out of order and drops.
+type ChaosConfig struct {
+	Duplicate float64
+	Reorder   float64
+	Drop      float64
+}
+
+// ChaosStats counts the faults Chaos has injected.
+type ChaosStats struct {
+	Duplicated, Reordered, Dropped uint64
+}
+
+// Chaos spoils the stream written to the wrapped sink the way real feeds
+// are spoiled, to exercise consumers' deduplication and ordering: it drops
+// records, writes some twice and holds some back until after the next
+// record from the same aircraft, so that timestamps arrive out of order.
+type Chaos struct {
+	next Sink
+	cfg  ChaosConfig
+
+	mu    sync.Mutex
+	rng   *rand.Rand
+	held  map[string]Record
+	stats ChaosStats
+}
+
+// NewChaos wraps next with fault injection.
+func NewChaos(next Sink, cfg ChaosConfig) *Chaos {
+	return &Chaos{
+		next: next,
+		cfg:  cfg,
+		rng:  rand.New(rand.NewSource(time.Now().UnixNano())),
+		held: make(map[string]Record),
+	}
+}
+
+// Stats returns the number of faults injected so far.
+func (c *Chaos) Stats() ChaosStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+func (c *Chaos) Put(ctx context.Context, record Record) error {
+	c.mu.Lock()
+	drop := c.rng.Float64() < c.cfg.Drop
+	duplicate := !drop && c.rng.Float64() < c.cfg.Duplicate
+	held, late := c.held[record.TailNum]
+	delete(c.held, record.TailNum)
+	if !drop && !late && c.rng.Float64() < c.cfg.Reorder {
+		c.held[record.TailNum] = record
+		c.stats.Reordered++
+		c.mu.Unlock()
+		return nil
+	}
+	switch {
+	case drop:
+		c.stats.Dropped++
+	case duplicate:
+		c.stats.Duplicated++
+	}
+	c.mu.Unlock()
+
+	var err error
+	if !drop {
+		err = c.next.Put(ctx, record)
+		if duplicate {
+			err = errors.Join(err, c.next.Put(ctx, record))
+		}
+	}
+	if late {
+		err = errors.Join(err, c.next.Put(ctx, held))
+	}
+	return err
+}
+
+// Close writes the records still held back and closes the wrapped sink.
+func (c *Chaos) Close() error {
+	c.mu.Lock()
+	held := c.held
+	c.held = make(map[string]Record)
+	c.mu.Unlock()
+
+	var err error
+	for _, record := range held {
+		err = errors.Join(err, c.next.Put(context.Background(), record))
+	}
+	return errors.Join(err, c.next.Close())
+}
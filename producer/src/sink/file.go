@@ -0,0 +1,163 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileConfig holds the settings for a file sink. The active file is written
+// at Path; when it grows past MaxBytes or has been open longer than MaxAge it
+// is renamed with a timestamp suffix and a new file is started. A zero limit
+// disables that kind of rotation. When Header is set it is written as the
+// first line of every new file, e.g. a CSV header row.
+type FileConfig struct {
+	Path     string
+	MaxBytes int64
+	MaxAge   time.Duration
+	Header   []byte
+}
+
+// File appends records to a local file one per line, which with the JSON
+// encoder produces newline-delimited JSON.
+type File struct {
+	cfg FileConfig
+
+	mu      sync.Mutex
+	file    *os.File
+	w       *bufio.Writer
+	size    int64
+	opened  time.Time
+	rotated int
+}
+
+// NewFile creates a file sink, creating the parent directory if needed.
+func NewFile(cfg FileConfig) (*File, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("file: path is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("file: %w", err)
+	}
+
+	f := &File{cfg: cfg}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *File) open() error {
+	file, err := os.OpenFile(f.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("file: %w", err)
+	}
+
+	f.file = file
+	f.w = bufio.NewWriter(file)
+	f.size = info.Size()
+	f.opened = time.Now()
+
+	if f.size == 0 && len(f.cfg.Header) > 0 {
+		if err := f.writeLine(f.cfg.Header); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *File) writeLine(data []byte) error {
+	if _, err := f.w.Write(data); err != nil {
+		return fmt.Errorf("file: %w", err)
+	}
+	if err := f.w.WriteByte('\n'); err != nil {
+		return fmt.Errorf("file: %w", err)
+	}
+	f.size += int64(len(data) + 1)
+	return nil
+}
+
+func (f *File) closeFile() error {
+	if err := f.w.Flush(); err != nil {
+		f.file.Close()
+		return fmt.Errorf("file: %w", err)
+	}
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("file: %w", err)
+	}
+	return nil
+}
+
+func (f *File) needsRotation(next int) bool {
+	var headerSize int64
+	if len(f.cfg.Header) > 0 {
+		headerSize = int64(len(f.cfg.Header) + 1)
+	}
+	if f.size <= headerSize {
+		return false
+	}
+	if f.cfg.MaxBytes > 0 && f.size+int64(next) > f.cfg.MaxBytes {
+		return true
+	}
+	return f.cfg.MaxAge > 0 && time.Since(f.opened) >= f.cfg.MaxAge
+}
+
+func (f *File) rotate() error {
+	if err := f.closeFile(); err != nil {
+		return err
+	}
+
+	ext := filepath.Ext(f.cfg.Path)
+	base := strings.TrimSuffix(f.cfg.Path, ext)
+	f.rotated++
+	name := fmt.Sprintf("%s-%s-%d%s", base, time.Now().UTC().Format("20060102T150405"), f.rotated, ext)
+	if err := os.Rename(f.cfg.Path, name); err != nil {
+		return fmt.Errorf("file: rotating: %w", err)
+	}
+
+	return f.open()
+}
+
+// Put appends a record to the active file as a single line, rotating first
+// if the record would push the file over its limits.
+func (f *File) Put(_ context.Context, record Record) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return errors.New("file: sink is closed")
+	}
+
+	next := len(record.Data) + 1
+	if f.needsRotation(next) {
+		if err := f.rotate(); err != nil {
+			return err
+		}
+	}
+
+	return f.writeLine(record.Data)
+}
+
+// Close flushes and closes the active file.
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.closeFile()
+	f.file = nil
+	return err
+}
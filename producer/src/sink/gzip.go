@@ -0,0 +1,108 @@
+package sink
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// GzipBatchConfig holds the settings for a GzipBatcher. A batch is flushed
+// once it holds BatchSize records, once its uncompressed size reaches
+// MaxBytes, or FlushInterval after its first record arrived, whichever comes
+// first. Zero values disable the byte and interval limits.
+type GzipBatchConfig struct {
+	BatchSize     int
+	MaxBytes      int
+	FlushInterval time.Duration
+}
+
+// GzipBatcher aggregates records into newline-delimited batches, gzips each
+// batch and writes it to the wrapped sink as a single record. This trades
+// latency for far fewer, smaller writes when simulating large fleets.
+//
+// The batch is keyed by the partition key of its first record.
+type GzipBatcher struct {
+	next Sink
+	cfg  GzipBatchConfig
+
+	mu      sync.Mutex
+	pending []Record
+	size    int
+	timer   *time.Timer
+}
+
+// NewGzipBatcher wraps next with a gzip batching layer.
+func NewGzipBatcher(next Sink, cfg GzipBatchConfig) *GzipBatcher {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	return &GzipBatcher{next: next, cfg: cfg}
+}
+
+// Put adds a record to the current batch, writing the batch out if it is
+// full.
+func (g *GzipBatcher) Put(ctx context.Context, record Record) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.pending = append(g.pending, record)
+	g.size += len(record.Data) + 1
+
+	if len(g.pending) >= g.cfg.BatchSize || (g.cfg.MaxBytes > 0 && g.size >= g.cfg.MaxBytes) {
+		return g.flushLocked(ctx)
+	}
+	if len(g.pending) == 1 && g.cfg.FlushInterval > 0 {
+		g.timer = time.AfterFunc(g.cfg.FlushInterval, func() {
+			if err := g.Flush(context.Background()); err != nil {
+				log.Printf("gzip batcher: %v", err)
+			}
+		})
+	}
+	return nil
+}
+
+// Flush writes out the current batch, if any.
+func (g *GzipBatcher) Flush(ctx context.Context) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.flushLocked(ctx)
+}
+
+func (g *GzipBatcher) flushLocked(ctx context.Context) error {
+	if g.timer != nil {
+		g.timer.Stop()
+		g.timer = nil
+	}
+	if len(g.pending) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	for _, record := range g.pending {
+		zw.Write(record.Data)
+		zw.Write([]byte{'\n'})
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("gzip batcher: %w", err)
+	}
+
+	batch := Record{PartitionKey: g.pending[0].PartitionKey, Data: buf.Bytes()}
+	g.pending = nil
+	g.size = 0
+
+	return g.next.Put(ctx, batch)
+}
+
+// Close flushes the current batch and closes the wrapped sink.
+func (g *GzipBatcher) Close() error {
+	err := g.Flush(context.Background())
+	if cerr := g.next.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,100 @@
+//go:build kafka
+
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// KafkaConfig holds the settings for a Kafka sink.
+type KafkaConfig struct {
+	Brokers []string
+	Topic   string
+}
+
+// Kafka writes records to a Kafka topic. Records are keyed by their
+// partition key (the tail number) so every report for an aircraft lands on
+// the same partition, and writes are idempotent so broker retries never
+// duplicate or reorder a report.
+//
+// Kafka support pulls in a sizeable client library, so it is only compiled
+// into binaries built with the "kafka" tag.
+type Kafka struct {
+	client *kgo.Client
+}
+
+// NewKafka creates a Kafka sink connected to the given brokers.
+func NewKafka(cfg KafkaConfig) (*Kafka, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, errors.New("kafka: at least one broker is required")
+	}
+	if cfg.Topic == "" {
+		return nil, errors.New("kafka: topic is required")
+	}
+
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(cfg.Brokers...),
+		kgo.DefaultProduceTopic(cfg.Topic),
+		kgo.RequiredAcks(kgo.AllISRAcks()),
+		kgo.RecordPartitioner(kgo.StickyKeyPartitioner(nil)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("kafka: creating client: %w", err)
+	}
+
+	return &Kafka{client: client}, nil
+}
+
+func toKafkaRecord(record Record) *kgo.Record {
+	return &kgo.Record{Key: []byte(record.PartitionKey), Value: record.Data}
+}
+
+// Put writes a single record and waits for it to be acknowledged.
+func (k *Kafka) Put(ctx context.Context, record Record) error {
+	if err := k.client.ProduceSync(ctx, toKafkaRecord(record)).FirstErr(); err != nil {
+		return fmt.Errorf("kafka: produce: %w", err)
+	}
+	return nil
+}
+
+// PutBatch writes records and waits for all of them to be acknowledged. If
+// any were not, a *BatchError holds them.
+func (k *Kafka) PutBatch(ctx context.Context, records []Record) error {
+	rs := make([]*kgo.Record, 0, len(records))
+	index := make(map[*kgo.Record]int, len(records))
+	for i, record := range records {
+		r := toKafkaRecord(record)
+		rs = append(rs, r)
+		index[r] = i
+	}
+
+	results := k.client.ProduceSync(ctx, rs...)
+	err := results.FirstErr()
+	if err == nil {
+		return nil
+	}
+	failed := make([]bool, len(records))
+	for _, result := range results {
+		if result.Err != nil {
+			failed[index[result.Record]] = true
+		}
+	}
+	batchErr := &BatchError{Err: fmt.Errorf("kafka: produce: %w", err)}
+	for i, record := range records {
+		if failed[i] {
+			batchErr.Failed = append(batchErr.Failed, record)
+		}
+	}
+	return batchErr
+}
+
+// Close flushes any buffered records and closes the client.
+func (k *Kafka) Close() error {
+	err := k.client.Flush(context.Background())
+	k.client.Close()
+	return err
+}
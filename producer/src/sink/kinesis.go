@@ -0,0 +1,233 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+)
+
+// maxPutRecords and maxPutRecordsBytes are the most records, and bytes of
+// data and partition keys, Kinesis accepts in one PutRecords call.
+const (
+	maxPutRecords      = 500
+	maxPutRecordsBytes = 5 << 20
+)
+
+// shardMapTTL is how long a stream's shard map is used before it is
+// fetched again, to follow resharding.
+const shardMapTTL = time.Minute
+
+// KinesisConfig holds the settings for a Kinesis sink. When PartitionKey is
+// set it is used for every record, otherwise each record's own key is used.
+//
+// With Aggregate set, PutBatch packs records bound for the same shard into
+// KPL aggregated records so KCL consumers can deaggregate them; this needs
+// permission to list the stream's shards. Records rejected by PutRecords
+// are retried up to MaxRetries times.
+type KinesisConfig struct {
+	StreamName string
+	AWSOptions
+	PartitionKey string
+	Aggregate    bool
+	MaxRetries   int
+}
+
+type kinesisAPI interface {
+	PutRecord(ctx context.Context, in *kinesis.PutRecordInput, opts ...func(*kinesis.Options)) (*kinesis.PutRecordOutput, error)
+	PutRecords(ctx context.Context, in *kinesis.PutRecordsInput, opts ...func(*kinesis.Options)) (*kinesis.PutRecordsOutput, error)
+	ListShards(ctx context.Context, in *kinesis.ListShardsInput, opts ...func(*kinesis.Options)) (*kinesis.ListShardsOutput, error)
+}
+
+// Kinesis writes records to an AWS Kinesis data stream.
+type Kinesis struct {
+	client kinesisAPI
+	cfg    KinesisConfig
+
+	mu       sync.Mutex
+	shards   shardMap
+	shardsAt time.Time
+}
+
+// NewKinesis creates a Kinesis sink using the default AWS credential chain.
+func NewKinesis(ctx context.Context, cfg KinesisConfig) (*Kinesis, error) {
+	if cfg.StreamName == "" {
+		return nil, errors.New("kinesis: stream name is required")
+	}
+
+	awsCfg, err := LoadAWSConfig(ctx, cfg.AWSOptions)
+	if err != nil {
+		return nil, fmt.Errorf("kinesis: %w", err)
+	}
+
+	return &Kinesis{client: kinesis.NewFromConfig(awsCfg), cfg: cfg}, nil
+}
+
+// shardMap returns the stream's shard map, fetching it again once it is
+// older than shardMapTTL. If it cannot be fetched, records are aggregated
+// by partition key alone until it can.
+func (k *Kinesis) shardMap(ctx context.Context) shardMap {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.shardsAt.IsZero() || time.Since(k.shardsAt) > shardMapTTL {
+		shards, err := listShards(ctx, k.client, k.cfg.StreamName)
+		if err != nil {
+			log.Printf("%v; aggregating by partition key", err)
+		}
+		k.shards, k.shardsAt = shards, time.Now()
+	}
+	return k.shards
+}
+
+func (k *Kinesis) partitionKey(record Record) (string, error) {
+	if k.cfg.PartitionKey != "" {
+		return k.cfg.PartitionKey, nil
+	}
+	if record.PartitionKey == "" {
+		return "", errors.New("kinesis: record has no partition key")
+	}
+	return record.PartitionKey, nil
+}
+
+// Put writes a single record with PutRecord.
+func (k *Kinesis) Put(ctx context.Context, record Record) error {
+	key, err := k.partitionKey(record)
+	if err != nil {
+		return err
+	}
+
+	_, err = k.client.PutRecord(ctx, &kinesis.PutRecordInput{
+		StreamName:   aws.String(k.cfg.StreamName),
+		PartitionKey: aws.String(key),
+		Data:         record.Data,
+	})
+	if err != nil {
+		return fmt.Errorf("kinesis: put record: %w", err)
+	}
+	return nil
+}
+
+// kinesisEntry is a PutRecords entry and the records it carries, more
+// than one if aggregated.
+type kinesisEntry struct {
+	types.PutRecordsRequestEntry
+	records []Record
+}
+
+// PutBatch writes records with PutRecords, splitting them into requests of
+// at most 500 records and 5MiB and aggregating them first if configured. Only the
+// records that failed are retried; if any are still failing after the last
+// retry, or a request fails outright, a *BatchError holds every record not
+// written.
+func (k *Kinesis) PutBatch(ctx context.Context, records []Record) error {
+	entries := make([]kinesisEntry, 0, len(records))
+	for _, record := range records {
+		key, err := k.partitionKey(record)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, kinesisEntry{
+			PutRecordsRequestEntry: types.PutRecordsRequestEntry{PartitionKey: aws.String(key), Data: record.Data},
+			records:                []Record{record},
+		})
+	}
+	if k.cfg.Aggregate {
+		entries = aggregateEntries(entries, k.shardMap(ctx))
+	}
+
+	var failed []Record
+	var err error
+	for _, request := range putRequests(entries) {
+		unwritten, putErr := k.putRecords(ctx, request)
+		failed = appendRecords(failed, unwritten)
+		if putErr != nil {
+			err = putErr
+		}
+	}
+	if err != nil {
+		return &BatchError{Failed: failed, Err: err}
+	}
+	return nil
+}
+
+// putRequests splits entries into PutRecords requests within Kinesis's
+// limits.
+func putRequests(entries []kinesisEntry) [][]kinesisEntry {
+	var requests [][]kinesisEntry
+	for start := 0; start < len(entries); {
+		end, size := start, 0
+		for end < len(entries) && end-start < maxPutRecords {
+			n := len(entries[end].Data) + len(aws.ToString(entries[end].PartitionKey))
+			if end > start && size+n > maxPutRecordsBytes {
+				break
+			}
+			size += n
+			end++
+		}
+		requests = append(requests, entries[start:end])
+		start = end
+	}
+	return requests
+}
+
+func appendRecords(records []Record, entries []kinesisEntry) []Record {
+	for _, entry := range entries {
+		records = append(records, entry.records...)
+	}
+	return records
+}
+
+// putRecords writes entries in a single PutRecords request, retrying the
+// ones that fail, and returns the entries still not written.
+func (k *Kinesis) putRecords(ctx context.Context, entries []kinesisEntry) ([]kinesisEntry, error) {
+	backoff := 100 * time.Millisecond
+
+	for attempt := 0; ; attempt++ {
+		request := make([]types.PutRecordsRequestEntry, len(entries))
+		for i, entry := range entries {
+			request[i] = entry.PutRecordsRequestEntry
+		}
+		out, err := k.client.PutRecords(ctx, &kinesis.PutRecordsInput{
+			StreamName: aws.String(k.cfg.StreamName),
+			Records:    request,
+		})
+		if err != nil {
+			return entries, fmt.Errorf("kinesis: put records: %w", err)
+		}
+		if aws.ToInt32(out.FailedRecordCount) == 0 {
+			return nil, nil
+		}
+
+		var failed []kinesisEntry
+		var lastErr string
+		for i, result := range out.Records {
+			if result.ErrorCode != nil {
+				failed = append(failed, entries[i])
+				lastErr = aws.ToString(result.ErrorCode) + ": " + aws.ToString(result.ErrorMessage)
+			}
+		}
+		if attempt >= k.cfg.MaxRetries {
+			return failed, fmt.Errorf("kinesis: %d of %d records failed: %s", len(failed), len(entries), lastErr)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return failed, ctx.Err()
+		}
+		backoff *= 2
+		entries = failed
+	}
+}
+
+// Close is a no-op; the Kinesis client holds no resources that need releasing.
+func (k *Kinesis) Close() error {
+	return nil
+}
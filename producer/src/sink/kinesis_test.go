@@ -0,0 +1,90 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+)
+
+// fakeKinesis answers PutRecords by rejecting the entries reject picks, or
+// failing the request outright with err, and lists shards.
+type fakeKinesis struct {
+	reject   func(types.PutRecordsRequestEntry) bool
+	err      error
+	shards   []types.Shard
+	requests [][]types.PutRecordsRequestEntry
+}
+
+func (f *fakeKinesis) ListShards(context.Context, *kinesis.ListShardsInput, ...func(*kinesis.Options)) (*kinesis.ListShardsOutput, error) {
+	return &kinesis.ListShardsOutput{Shards: f.shards}, nil
+}
+
+func (f *fakeKinesis) PutRecord(context.Context, *kinesis.PutRecordInput, ...func(*kinesis.Options)) (*kinesis.PutRecordOutput, error) {
+	return &kinesis.PutRecordOutput{}, nil
+}
+
+func (f *fakeKinesis) PutRecords(_ context.Context, in *kinesis.PutRecordsInput, _ ...func(*kinesis.Options)) (*kinesis.PutRecordsOutput, error) {
+	f.requests = append(f.requests, in.Records)
+	if f.err != nil {
+		return nil, f.err
+	}
+	out := &kinesis.PutRecordsOutput{Records: make([]types.PutRecordsResultEntry, len(in.Records))}
+	var failed int32
+	for i, entry := range in.Records {
+		if f.reject != nil && f.reject(entry) {
+			out.Records[i].ErrorCode = aws.String("ProvisionedThroughputExceededException")
+			failed++
+		}
+	}
+	out.FailedRecordCount = aws.Int32(failed)
+	return out, nil
+}
+
+func keyed(pairs ...string) []Record {
+	var rs []Record
+	for i := 0; i < len(pairs); i += 2 {
+		rs = append(rs, Record{PartitionKey: pairs[i], FlightID: pairs[i+1], Data: []byte(pairs[i+1])})
+	}
+	return rs
+}
+
+func flightIDs(rs []Record) []string {
+	var ids []string
+	for _, r := range rs {
+		ids = append(ids, r.FlightID)
+	}
+	return ids
+}
+
+// TestKinesisPutBatchFailures checks the records behind rejected entries,
+// and every record of a request that fails outright, come back in a
+// BatchError.
+func TestKinesisPutBatchFailures(t *testing.T) {
+	client := &fakeKinesis{reject: func(e types.PutRecordsRequestEntry) bool {
+		return aws.ToString(e.PartitionKey) == "N2"
+	}}
+	k := &Kinesis{client: client, cfg: KinesisConfig{StreamName: "flights"}}
+	err := k.PutBatch(context.Background(), keyed("N1", "A", "N2", "B", "N3", "C"))
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("got %v, want a BatchError", err)
+	}
+	if got := flightIDs(batchErr.Failed); !reflect.DeepEqual(got, []string{"B"}) {
+		t.Errorf("failed %v, want [B]", got)
+	}
+
+	client = &fakeKinesis{err: errors.New("throttled")}
+	k = &Kinesis{client: client, cfg: KinesisConfig{StreamName: "flights"}}
+	err = k.PutBatch(context.Background(), keyed("N1", "A", "N2", "B"))
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("got %v, want a BatchError", err)
+	}
+	if got := flightIDs(batchErr.Failed); !reflect.DeepEqual(got, []string{"A", "B"}) {
+		t.Errorf("failed %v, want [A B]", got)
+	}
+}
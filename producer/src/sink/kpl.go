@@ -0,0 +1,207 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// kplMagic prefixes every KPL aggregated record.
+var kplMagic = []byte{0xF3, 0x89, 0x9A, 0xC2}
+
+// maxAggregatedBytes keeps aggregated records comfortably under the 1MiB
+// Kinesis record limit, which also counts the partition key.
+const maxAggregatedBytes = 1000 * 1024
+
+// hashKey is a 128-bit Kinesis hash key, big-endian.
+type hashKey [md5.Size]byte
+
+// hashKeyOf returns the hash key Kinesis derives from a partition key.
+func hashKeyOf(partitionKey string) hashKey {
+	return md5.Sum([]byte(partitionKey))
+}
+
+func (h hashKey) String() string {
+	return new(big.Int).SetBytes(h[:]).String()
+}
+
+func parseHashKey(s string) (hashKey, error) {
+	var h hashKey
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok || n.Sign() < 0 || n.BitLen() > 8*len(h) {
+		return h, fmt.Errorf("invalid hash key %q", s)
+	}
+	n.FillBytes(h[:])
+	return h, nil
+}
+
+// shardMap is the starting hash keys of a stream's open shards, in order.
+// Together they cover every hash key, each shard taking those from its own
+// start up to the next one's.
+type shardMap []hashKey
+
+// shard returns the index of the shard h falls in.
+func (m shardMap) shard(h hashKey) int {
+	i := sort.Search(len(m), func(i int) bool { return bytes.Compare(m[i][:], h[:]) > 0 })
+	return max(i-1, 0)
+}
+
+// listShards fetches the shard map of the stream.
+func listShards(ctx context.Context, client kinesisAPI, stream string) (shardMap, error) {
+	var m shardMap
+	in := &kinesis.ListShardsInput{StreamName: aws.String(stream)}
+	for {
+		out, err := client.ListShards(ctx, in)
+		if err != nil {
+			return nil, fmt.Errorf("kinesis: list shards: %w", err)
+		}
+		for _, s := range out.Shards {
+			if s.SequenceNumberRange != nil && s.SequenceNumberRange.EndingSequenceNumber != nil {
+				continue // closed by a reshard
+			}
+			start, err := parseHashKey(aws.ToString(s.HashKeyRange.StartingHashKey))
+			if err != nil {
+				return nil, fmt.Errorf("kinesis: shard %s: %w", aws.ToString(s.ShardId), err)
+			}
+			m = append(m, start)
+		}
+		if out.NextToken == nil {
+			break
+		}
+		in = &kinesis.ListShardsInput{NextToken: out.NextToken}
+	}
+	sort.Slice(m, func(i, j int) bool { return bytes.Compare(m[i][:], m[j][:]) < 0 })
+	return m, nil
+}
+
+// aggregateEntries packs entries into KPL aggregated records: the magic
+// bytes, an AggregatedRecord protobuf message and its MD5 digest.
+//
+// As the KPL does, entries bound for the same shard are aggregated
+// together, whatever their partition keys, and each aggregate is sent with
+// the explicit hash key of its first record so that it lands on that
+// shard. KCL checks every user record belongs to the shard it was read
+// from, which this guarantees. Without a shard map, only entries sharing a
+// partition key are aggregated together. Either way the order of records
+// for each key is kept.
+func aggregateEntries(entries []kinesisEntry, shards shardMap) []kinesisEntry {
+	var groups []string
+	grouped := make(map[string][]Record)
+	for _, entry := range entries {
+		group := aws.ToString(entry.PartitionKey)
+		if len(shards) > 0 {
+			group = strconv.Itoa(shards.shard(hashKeyOf(group)))
+		}
+		if _, ok := grouped[group]; !ok {
+			groups = append(groups, group)
+		}
+		for _, record := range entry.records {
+			record.PartitionKey = aws.ToString(entry.PartitionKey)
+			grouped[group] = append(grouped[group], record)
+		}
+	}
+
+	var out []kinesisEntry
+	for _, group := range groups {
+		var agg aggregate
+		for _, record := range grouped[group] {
+			if len(agg.records) > 0 && !agg.fits(record) {
+				out = append(out, agg.entry())
+				agg = aggregate{}
+			}
+			agg.add(record)
+		}
+		out = append(out, agg.entry())
+	}
+	return out
+}
+
+// aggregate collects the records of one aggregated record, keeping track
+// of the size of its AggregatedRecord message.
+type aggregate struct {
+	keys    []string
+	index   map[string]uint64
+	records []Record
+	size    int
+}
+
+// growth returns how much adding record would add to the message.
+//
+//	AggregatedRecord { repeated string partition_key_table = 1;
+//	                   repeated Record records = 3; }
+//	Record { uint64 partition_key_index = 1; bytes data = 3; }
+func (a *aggregate) growth(record Record) int {
+	n := 0
+	i, ok := a.index[record.PartitionKey]
+	if !ok {
+		i = uint64(len(a.keys))
+		n += protowire.SizeTag(1) + protowire.SizeBytes(len(record.PartitionKey))
+	}
+	rec := protowire.SizeTag(1) + protowire.SizeVarint(i) + protowire.SizeTag(3) + protowire.SizeBytes(len(record.Data))
+	return n + protowire.SizeTag(3) + protowire.SizeBytes(rec)
+}
+
+func (a *aggregate) fits(record Record) bool {
+	size := len(kplMagic) + a.size + a.growth(record) + md5.Size
+	return size+len(a.records[0].PartitionKey) <= maxAggregatedBytes
+}
+
+func (a *aggregate) add(record Record) {
+	a.size += a.growth(record)
+	if a.index == nil {
+		a.index = make(map[string]uint64)
+	}
+	if _, ok := a.index[record.PartitionKey]; !ok {
+		a.index[record.PartitionKey] = uint64(len(a.keys))
+		a.keys = append(a.keys, record.PartitionKey)
+	}
+	a.records = append(a.records, record)
+}
+
+// entry returns the aggregated record as a PutRecords entry.
+func (a *aggregate) entry() kinesisEntry {
+	first := a.records[0]
+	entry := kinesisEntry{
+		PutRecordsRequestEntry: types.PutRecordsRequestEntry{PartitionKey: aws.String(first.PartitionKey)},
+		records:                a.records,
+	}
+	if len(a.records) == 1 {
+		// A single record gains nothing from aggregation; KCL accepts plain
+		// records alongside aggregated ones.
+		entry.Data = first.Data
+		return entry
+	}
+
+	msg := make([]byte, 0, a.size)
+	for _, key := range a.keys {
+		msg = protowire.AppendTag(msg, 1, protowire.BytesType)
+		msg = protowire.AppendString(msg, key)
+	}
+	for _, record := range a.records {
+		var rec []byte
+		rec = protowire.AppendTag(rec, 1, protowire.VarintType)
+		rec = protowire.AppendVarint(rec, a.index[record.PartitionKey])
+		rec = protowire.AppendTag(rec, 3, protowire.BytesType)
+		rec = protowire.AppendBytes(rec, record.Data)
+
+		msg = protowire.AppendTag(msg, 3, protowire.BytesType)
+		msg = protowire.AppendBytes(msg, rec)
+	}
+
+	sum := md5.Sum(msg)
+	entry.Data = make([]byte, 0, len(kplMagic)+len(msg)+len(sum))
+	entry.Data = append(entry.Data, kplMagic...)
+	entry.Data = append(entry.Data, msg...)
+	entry.Data = append(entry.Data, sum[:]...)
+	entry.ExplicitHashKey = aws.String(hashKeyOf(first.PartitionKey).String())
+	return entry
+}
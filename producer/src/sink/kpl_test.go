@@ -0,0 +1,225 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// halves is a stream of two shards splitting the hash key space in half.
+var halves = []types.Shard{
+	{ShardId: aws.String("shardId-0"), HashKeyRange: &types.HashKeyRange{
+		StartingHashKey: aws.String("0"),
+		EndingHashKey:   aws.String(new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1)).String()),
+	}},
+	{ShardId: aws.String("shardId-1"), HashKeyRange: &types.HashKeyRange{
+		StartingHashKey: aws.String(new(big.Int).Lsh(big.NewInt(1), 127).String()),
+		EndingHashKey:   aws.String(new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1)).String()),
+	}},
+}
+
+// userRecord is a record read back out of an aggregated one.
+type userRecord struct {
+	key  string
+	data []byte
+}
+
+// deaggregate checks an aggregated record's magic bytes and MD5 trailer
+// and reads the user records from its AggregatedRecord message.
+func deaggregate(t *testing.T, data []byte) []userRecord {
+	t.Helper()
+	if !bytes.HasPrefix(data, kplMagic) {
+		t.Fatalf("no magic bytes: % x", data[:min(len(data), 8)])
+	}
+	msg := data[len(kplMagic) : len(data)-md5.Size]
+	if sum := md5.Sum(msg); !bytes.Equal(sum[:], data[len(data)-md5.Size:]) {
+		t.Fatal("MD5 trailer does not match the message")
+	}
+
+	var keys []string
+	var indexes []uint64
+	var datas [][]byte
+	for len(msg) > 0 {
+		num, typ, n := protowire.ConsumeTag(msg)
+		if n < 0 || typ != protowire.BytesType {
+			t.Fatalf("bad AggregatedRecord field %d of type %d", num, typ)
+		}
+		msg = msg[n:]
+		v, n := protowire.ConsumeBytes(msg)
+		if n < 0 {
+			t.Fatal(protowire.ParseError(n))
+		}
+		msg = msg[n:]
+		switch num {
+		case 1:
+			keys = append(keys, string(v))
+		case 3:
+			var index uint64
+			var data []byte
+			for len(v) > 0 {
+				num, typ, n := protowire.ConsumeTag(v)
+				if n < 0 {
+					t.Fatal(protowire.ParseError(n))
+				}
+				v = v[n:]
+				switch {
+				case num == 1 && typ == protowire.VarintType:
+					index, n = protowire.ConsumeVarint(v)
+				case num == 3 && typ == protowire.BytesType:
+					data, n = protowire.ConsumeBytes(v)
+				default:
+					t.Fatalf("bad Record field %d of type %d", num, typ)
+				}
+				if n < 0 {
+					t.Fatal(protowire.ParseError(n))
+				}
+				v = v[n:]
+			}
+			indexes = append(indexes, index)
+			datas = append(datas, data)
+		default:
+			t.Fatalf("unexpected AggregatedRecord field %d", num)
+		}
+	}
+
+	var records []userRecord
+	for i, index := range indexes {
+		if index >= uint64(len(keys)) {
+			t.Fatalf("partition key index %d of %d keys", index, len(keys))
+		}
+		records = append(records, userRecord{keys[index], datas[i]})
+	}
+	return records
+}
+
+func singles(records []Record) []kinesisEntry {
+	entries := make([]kinesisEntry, len(records))
+	for i, r := range records {
+		entries[i] = kinesisEntry{
+			PutRecordsRequestEntry: types.PutRecordsRequestEntry{PartitionKey: aws.String(r.PartitionKey), Data: r.Data},
+			records:                []Record{r},
+		}
+	}
+	return entries
+}
+
+// TestAggregateByShard checks records with different partition keys are
+// aggregated by the shard they hash to, each aggregate is routed to that
+// shard, and every record decodes back out in order.
+func TestAggregateByShard(t *testing.T) {
+	client := &fakeKinesis{shards: halves}
+	shards, err := listShards(context.Background(), client, "flights")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []Record
+	for i := 0; i < 40; i++ {
+		key := fmt.Sprintf("N%dUT", i%20)
+		records = append(records, Record{PartitionKey: key, Data: []byte(fmt.Sprintf("report %d", i))})
+	}
+
+	entries := aggregateEntries(singles(records), shards)
+	if len(entries) != 2 {
+		t.Fatalf("%d aggregated records, want one per shard", len(entries))
+	}
+	var read int
+	for _, entry := range entries {
+		ehk, err := parseHashKey(aws.ToString(entry.ExplicitHashKey))
+		if err != nil {
+			t.Fatal(err)
+		}
+		shard := shards.shard(ehk)
+		got := deaggregate(t, entry.Data)
+		if len(got) != len(entry.records) {
+			t.Fatalf("%d user records, want %d", len(got), len(entry.records))
+		}
+		for i, r := range got {
+			want := entry.records[i]
+			if r.key != want.PartitionKey || !bytes.Equal(r.data, want.Data) {
+				t.Errorf("record %d is %s %q, want %s %q", i, r.key, r.data, want.PartitionKey, want.Data)
+			}
+			if s := shards.shard(hashKeyOf(r.key)); s != shard {
+				t.Errorf("record for %s belongs on shard %d, sent to %d", r.key, s, shard)
+			}
+		}
+		read += len(got)
+	}
+	if read != len(records) {
+		t.Errorf("read back %d records, want %d", read, len(records))
+	}
+	// Reports for each aircraft stay in order.
+	last := make(map[string]int)
+	for _, entry := range entries {
+		for _, r := range entry.records {
+			var n int
+			fmt.Sscanf(string(r.Data), "report %d", &n)
+			if prev, ok := last[r.PartitionKey]; ok && n < prev {
+				t.Errorf("%s: report %d after %d", r.PartitionKey, n, prev)
+			}
+			last[r.PartitionKey] = n
+		}
+	}
+
+	if entries := aggregateEntries(singles(records), nil); len(entries) != 20 {
+		t.Errorf("without a shard map, %d aggregated records, want one per partition key", len(entries))
+	}
+}
+
+// TestAggregateLimits checks aggregated records stay under the record
+// limit and PutRecords requests under the request limits.
+func TestAggregateLimits(t *testing.T) {
+	var records []Record
+	for i := 0; i < 1200; i++ {
+		records = append(records, Record{PartitionKey: fmt.Sprintf("N%d", i), Data: bytes.Repeat([]byte{'x'}, 20<<10)})
+	}
+	entries := aggregateEntries(singles(records), shardMap{{}})
+	for _, entry := range entries {
+		if n := len(entry.Data) + len(aws.ToString(entry.PartitionKey)); n > maxAggregatedBytes {
+			t.Fatalf("aggregated record of %d bytes", n)
+		}
+	}
+
+	var sent int
+	for _, request := range putRequests(append(entries, singles(records)...)) {
+		size := 0
+		for _, entry := range request {
+			size += len(entry.Data) + len(aws.ToString(entry.PartitionKey))
+		}
+		if len(request) > maxPutRecords || size > maxPutRecordsBytes {
+			t.Errorf("request of %d records and %d bytes", len(request), size)
+		}
+		sent += len(request)
+	}
+	if sent != len(entries)+len(records) {
+		t.Errorf("sent %d entries, want %d", sent, len(entries)+len(records))
+	}
+}
+
+// TestKinesisAggregates checks PutBatch sends aggregated records with the
+// explicit hash keys of their shards.
+func TestKinesisAggregates(t *testing.T) {
+	client := &fakeKinesis{shards: halves}
+	k := &Kinesis{client: client, cfg: KinesisConfig{StreamName: "flights", Aggregate: true}}
+	var records []Record
+	for i := 0; i < 10; i++ {
+		records = append(records, Record{PartitionKey: fmt.Sprintf("N%d", i), Data: []byte{byte(i)}})
+	}
+	if err := k.PutBatch(context.Background(), records); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.requests) != 1 || len(client.requests[0]) != 2 {
+		t.Fatalf("sent %v, want one request of two aggregated records", client.requests)
+	}
+	for _, entry := range client.requests[0] {
+		if entry.ExplicitHashKey == nil {
+			t.Error("aggregated record has no explicit hash key")
+		}
+	}
+}
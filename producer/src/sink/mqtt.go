@@ -0,0 +1,91 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// DefaultMQTTTopic is the topic template used when none is configured.
+const DefaultMQTTTopic = "flights/{flightId}/position"
+
+// MQTTConfig holds the settings for an MQTT sink. Topic may contain the
+// {flightId} and {tailNum} placeholders, which are filled in per record.
+type MQTTConfig struct {
+	Broker   string
+	ClientID string
+	Username string
+	Password string
+	Topic    string
+	QoS      byte
+	Retained bool
+}
+
+// MQTT publishes each record to a per-flight MQTT topic.
+type MQTT struct {
+	client mqtt.Client
+	cfg    MQTTConfig
+}
+
+// NewMQTT creates an MQTT sink and connects it to the broker.
+func NewMQTT(ctx context.Context, cfg MQTTConfig) (*MQTT, error) {
+	if cfg.Broker == "" {
+		return nil, errors.New("mqtt: broker is required")
+	}
+	if cfg.QoS > 2 {
+		return nil, fmt.Errorf("mqtt: invalid qos %d", cfg.QoS)
+	}
+	if cfg.Topic == "" {
+		cfg.Topic = DefaultMQTTTopic
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(cfg.Broker).
+		SetClientID(cfg.ClientID).
+		SetUsername(cfg.Username).
+		SetPassword(cfg.Password).
+		SetAutoReconnect(true)
+
+	client := mqtt.NewClient(opts)
+	if err := waitToken(ctx, client.Connect()); err != nil {
+		return nil, fmt.Errorf("mqtt: connecting to %s: %w", cfg.Broker, err)
+	}
+
+	return &MQTT{client: client, cfg: cfg}, nil
+}
+
+func (m *MQTT) topic(record Record) string {
+	return strings.NewReplacer(
+		"{flightId}", record.FlightID,
+		"{tailNum}", record.TailNum,
+	).Replace(m.cfg.Topic)
+}
+
+// Put publishes a single record and waits for the broker to accept it at the
+// configured QoS.
+func (m *MQTT) Put(ctx context.Context, record Record) error {
+	token := m.client.Publish(m.topic(record), m.cfg.QoS, m.cfg.Retained, record.Data)
+	if err := waitToken(ctx, token); err != nil {
+		return fmt.Errorf("mqtt: publish: %w", err)
+	}
+	return nil
+}
+
+// Close disconnects from the broker, giving in-flight messages a moment to
+// complete.
+func (m *MQTT) Close() error {
+	m.client.Disconnect(250)
+	return nil
+}
+
+func waitToken(ctx context.Context, token mqtt.Token) error {
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,59 @@
+package sink
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+
+	"plane-producer/src/domain"
+)
+
+// PartitionStrategy decides which key a report is partitioned by. Keying by
+// tail number or flight ID keeps each aircraft's or flight's reports in
+// order on one shard; keying by origin groups departures from an airport;
+// random keys spread load evenly at the cost of ordering.
+type PartitionStrategy string
+
+const (
+	ByTailNum  PartitionStrategy = "tailNum"
+	ByFlightID PartitionStrategy = "flightId"
+	ByOrigin   PartitionStrategy = "origin"
+	ByRandom   PartitionStrategy = "random"
+)
+
+// ParsePartitionStrategy validates a configured strategy name, defaulting to
+// ByTailNum when it is empty.
+func ParsePartitionStrategy(name string) (PartitionStrategy, error) {
+	switch s := PartitionStrategy(name); s {
+	case "":
+		return ByTailNum, nil
+	case ByTailNum, ByFlightID, ByOrigin, ByRandom:
+		return s, nil
+	default:
+		return "", fmt.Errorf("unknown partition strategy %q", name)
+	}
+}
+
+// Key returns the partition key for a report.
+func (s PartitionStrategy) Key(record domain.FlightRecord) string {
+	switch s {
+	case ByFlightID:
+		return record.FlightID
+	case ByOrigin:
+		return record.Origin
+	case ByRandom:
+		return strconv.FormatUint(rand.Uint64(), 36)
+	default:
+		return record.TailNum
+	}
+}
+
+// NewRecord wraps an encoded report in a Record keyed by the strategy.
+func (s PartitionStrategy) NewRecord(record domain.FlightRecord, data []byte) Record {
+	return Record{
+		PartitionKey: s.Key(record),
+		TailNum:      record.TailNum,
+		FlightID:     record.FlightID,
+		Data:         data,
+	}
+}
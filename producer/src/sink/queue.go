@@ -0,0 +1,185 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+)
+
+// OverflowPolicy decides what a full Queue does with a new record.
+type OverflowPolicy string
+
+const (
+	// Block makes Put wait for room, stalling the simulation tick.
+	Block OverflowPolicy = "block"
+	// DropOldest discards the oldest queued record to make room.
+	DropOldest OverflowPolicy = "drop-oldest"
+	// DropNewest discards the new record.
+	DropNewest OverflowPolicy = "drop-newest"
+)
+
+// ParseOverflowPolicy returns the named policy, defaulting to Block.
+func ParseOverflowPolicy(name string) (OverflowPolicy, error) {
+	switch p := OverflowPolicy(name); p {
+	case "":
+		return Block, nil
+	case Block, DropOldest, DropNewest:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown overflow policy %q", name)
+	}
+}
+
+// Queue decouples the simulation from a slow sink with a bounded queue
+// drained by a single goroutine, so memory use stays bounded whatever the
+// sink does. Records are passed to the goroutine in batches, sent when they
+// are full or flushed, rather than one by one, so thousands of aircraft
+// reporting each second do not contend on the channel. Records dropped by
+// the overflow policy are counted.
+type Queue struct {
+	next   Sink
+	policy OverflowPolicy
+	batch  int
+	ch     chan []Record
+	done   chan struct{}
+
+	mu      sync.Mutex
+	closed  bool
+	pending []Record
+
+	queued  atomic.Int64
+	dropped atomic.Uint64
+}
+
+// NewQueue wraps next with a queue holding up to size records, in batches
+// of up to batch records.
+func NewQueue(next Sink, size, batch int, policy OverflowPolicy) *Queue {
+	if batch <= 0 {
+		batch = 1
+	}
+	q := &Queue{
+		next:    next,
+		policy:  policy,
+		batch:   batch,
+		ch:      make(chan []Record, max(size/batch, 1)),
+		done:    make(chan struct{}),
+		pending: make([]Record, 0, batch),
+	}
+	go q.drain()
+	return q
+}
+
+func (q *Queue) drain() {
+	defer close(q.done)
+	for batch := range q.ch {
+		for _, record := range batch {
+			q.queued.Add(-1)
+			if err := q.next.Put(context.Background(), record); err != nil {
+				log.Printf("queue: %v", err)
+			}
+		}
+	}
+}
+
+// Put adds a record to the batch being filled, sending the batch once it is
+// full.
+func (q *Queue) Put(ctx context.Context, record Record) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return errors.New("queue: sink is closed")
+	}
+	q.pending = append(q.pending, record)
+	q.queued.Add(1)
+	if len(q.pending) < q.batch {
+		return nil
+	}
+	return q.send(ctx)
+}
+
+// Flush sends the batch being filled, however few records it holds, e.g.
+// at the end of each simulation tick.
+func (q *Queue) Flush(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed || len(q.pending) == 0 {
+		return nil
+	}
+	return q.send(ctx)
+}
+
+// send queues the pending batch, applying the overflow policy if the queue
+// is full. q.mu must be held.
+func (q *Queue) send(ctx context.Context) error {
+	batch := q.pending
+	q.pending = make([]Record, 0, q.batch)
+
+	switch q.policy {
+	case DropNewest:
+		select {
+		case q.ch <- batch:
+		default:
+			q.drop(batch)
+		}
+	case DropOldest:
+		for {
+			select {
+			case q.ch <- batch:
+				return nil
+			default:
+			}
+			select {
+			case oldest := <-q.ch:
+				q.drop(oldest)
+			default:
+			}
+		}
+	default:
+		select {
+		case q.ch <- batch:
+		case <-ctx.Done():
+			q.queued.Add(-int64(len(batch)))
+			return fmt.Errorf("queue: %d reports not written: %w", len(batch), ctx.Err())
+		}
+	}
+	return nil
+}
+
+func (q *Queue) drop(batch []Record) {
+	q.queued.Add(-int64(len(batch)))
+	q.dropped.Add(uint64(len(batch)))
+}
+
+// Len returns the number of records waiting to be written.
+func (q *Queue) Len() int {
+	return int(q.queued.Load())
+}
+
+// Dropped returns how many records the overflow policy has discarded.
+func (q *Queue) Dropped() uint64 {
+	return q.dropped.Load()
+}
+
+// Close writes out the queued records and closes the wrapped sink.
+func (q *Queue) Close() error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil
+	}
+	var err error
+	if len(q.pending) > 0 {
+		err = q.send(context.Background())
+	}
+	q.closed = true
+	close(q.ch)
+	q.mu.Unlock()
+
+	<-q.done
+	return errors.Join(err, q.next.Close())
+}
@@ -0,0 +1,178 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+// RetryPolicy controls how failed writes are retried. Each retry waits
+// twice as long as the one before, starting at InitialBackoff and capped at
+// MaxBackoff. Jitter is the fraction of each wait, between 0 and 1, that is
+// randomised so many producers failing together do not retry in lockstep.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Jitter         float64
+}
+
+// DefaultRetryPolicy is used for any unset RetryPolicy fields.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Jitter:         0.5,
+}
+
+// RetryStats counts the work done by a Retry sink: the writes retried and
+// the records given up on.
+type RetryStats struct {
+	Retries           uint64
+	PermanentFailures uint64
+}
+
+// retryVars publishes the retries and permanent failures of every Retry
+// sink in the process, as the expvar map "sink".
+var retryVars = expvar.NewMap("sink")
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error as not worth retrying, such as a rejected
+// request.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var p permanentError
+	return errors.As(err, &p)
+}
+
+// Retry retries failed writes to the wrapped sink according to a policy.
+// Errors marked Permanent and context cancellation are not retried.
+type Retry struct {
+	next   Sink
+	policy RetryPolicy
+
+	retries  atomic.Uint64
+	failures atomic.Uint64
+}
+
+// retryBatch adds PutBatch to Retry for batch sinks. A batch that fails as
+// a whole is retried as a whole; one that partly fails has just the records
+// in its BatchError retried, since resending the rest would duplicate them.
+type retryBatch struct {
+	*Retry
+	batch BatchSink
+}
+
+func (r retryBatch) PutBatch(ctx context.Context, records []Record) error {
+	err := r.do(ctx, func() error {
+		err := r.batch.PutBatch(ctx, records)
+		var partial *BatchError
+		if errors.As(err, &partial) {
+			records = partial.Failed
+		}
+		return err
+	}, func() int { return len(records) })
+	if err != nil {
+		return fmt.Errorf("giving up on %d records: %w", len(records), err)
+	}
+	return nil
+}
+
+// NewRetry wraps next with retries. If next is a BatchSink the result is
+// too.
+func NewRetry(next Sink, policy RetryPolicy) Sink {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = DefaultRetryPolicy.MaxAttempts
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = DefaultRetryPolicy.InitialBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = DefaultRetryPolicy.MaxBackoff
+	}
+	if policy.Jitter < 0 || policy.Jitter > 1 {
+		policy.Jitter = DefaultRetryPolicy.Jitter
+	}
+
+	r := &Retry{next: next, policy: policy}
+	if batch, ok := next.(BatchSink); ok {
+		return retryBatch{Retry: r, batch: batch}
+	}
+	return r
+}
+
+// Stats returns the number of retries and permanent failures so far.
+func (r *Retry) Stats() RetryStats {
+	return RetryStats{Retries: r.retries.Load(), PermanentFailures: r.failures.Load()}
+}
+
+func (r *Retry) wait(backoff time.Duration) time.Duration {
+	jitter := time.Duration(r.policy.Jitter * rand.Float64() * float64(backoff))
+	return backoff - jitter
+}
+
+// Put writes a record, retrying on failure.
+func (r *Retry) Put(ctx context.Context, record Record) error {
+	err := r.do(ctx, func() error { return r.next.Put(ctx, record) }, func() int { return 1 })
+	if err != nil {
+		return fmt.Errorf("giving up on %s: %w", record.FlightID, err)
+	}
+	return nil
+}
+
+// do calls write until it succeeds or the policy gives up on it, returning
+// its last error. unwritten gives the number of records still to write,
+// which are counted as permanent failures when it gives up.
+func (r *Retry) do(ctx context.Context, write func() error, unwritten func() int) error {
+	backoff := r.policy.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := write()
+		if err == nil {
+			return nil
+		}
+		if IsPermanent(err) || ctx.Err() != nil || attempt >= r.policy.MaxAttempts {
+			r.fail(unwritten())
+			return err
+		}
+
+		r.retries.Add(1)
+		retryVars.Add("retries", 1)
+		timer := time.NewTimer(r.wait(backoff))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			r.fail(unwritten())
+			return ctx.Err()
+		}
+		backoff = min(backoff*2, r.policy.MaxBackoff)
+	}
+}
+
+func (r *Retry) fail(records int) {
+	r.failures.Add(uint64(records))
+	retryVars.Add("permanentFailures", int64(records))
+}
+
+// Close closes the wrapped sink.
+func (r *Retry) Close() error {
+	return r.next.Close()
+}
@@ -0,0 +1,112 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// scriptedBatch is a BatchSink whose PutBatch calls fail as scripted,
+// recording the batches it was given.
+type scriptedBatch struct {
+	results []func([]Record) error
+	batches [][]string
+}
+
+func (s *scriptedBatch) Put(ctx context.Context, record Record) error {
+	return s.PutBatch(ctx, []Record{record})
+}
+
+func (s *scriptedBatch) PutBatch(_ context.Context, records []Record) error {
+	var ids []string
+	for _, r := range records {
+		ids = append(ids, r.FlightID)
+	}
+	s.batches = append(s.batches, ids)
+	if len(s.results) == 0 {
+		return nil
+	}
+	result := s.results[0]
+	s.results = s.results[1:]
+	return result(records)
+}
+
+func (s *scriptedBatch) Close() error { return nil }
+
+var quickRetries = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+func records(ids ...string) []Record {
+	var rs []Record
+	for _, id := range ids {
+		rs = append(rs, Record{FlightID: id})
+	}
+	return rs
+}
+
+func counter(name string) int64 {
+	if v, ok := retryVars.Get(name).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+// TestRetryBatch checks a batch that fails outright is retried whole, and
+// that one that partly fails has only the failed records retried.
+func TestRetryBatch(t *testing.T) {
+	next := &scriptedBatch{results: []func([]Record) error{
+		func([]Record) error { return errors.New("throttled") },
+		func(rs []Record) error { return &BatchError{Failed: rs[1:2], Err: errors.New("1 of 3 failed")} },
+	}}
+	retries := counter("retries")
+	r := NewRetry(next, quickRetries).(BatchSink)
+
+	if err := r.PutBatch(context.Background(), records("A", "B", "C")); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"A", "B", "C"}, {"A", "B", "C"}, {"B"}}
+	if !reflect.DeepEqual(next.batches, want) {
+		t.Errorf("wrote %v, want %v", next.batches, want)
+	}
+	if got := r.(retryBatch).Stats(); got != (RetryStats{Retries: 2}) {
+		t.Errorf("stats %+v, want 2 retries", got)
+	}
+	if got := counter("retries") - retries; got != 2 {
+		t.Errorf("published %d retries, want 2", got)
+	}
+}
+
+// TestRetryBatchGivesUp checks the records still failing after the last
+// attempt are counted as permanent failures, and that a permanent error
+// is not retried.
+func TestRetryBatchGivesUp(t *testing.T) {
+	partial := func(rs []Record) error { return &BatchError{Failed: rs[:2], Err: errors.New("2 failed")} }
+	next := &scriptedBatch{results: []func([]Record) error{partial, partial, partial}}
+	failures := counter("permanentFailures")
+	r := NewRetry(next, quickRetries).(BatchSink)
+
+	err := r.PutBatch(context.Background(), records("A", "B", "C"))
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Failed) != 2 {
+		t.Fatalf("got %v, want the 2 records that failed", err)
+	}
+	if got := r.(retryBatch).Stats(); got != (RetryStats{Retries: 2, PermanentFailures: 2}) {
+		t.Errorf("stats %+v, want 2 retries and 2 failures", got)
+	}
+	if got := counter("permanentFailures") - failures; got != 2 {
+		t.Errorf("published %d permanent failures, want 2", got)
+	}
+
+	next = &scriptedBatch{results: []func([]Record) error{
+		func([]Record) error { return Permanent(errors.New("rejected")) },
+	}}
+	r = NewRetry(next, quickRetries).(BatchSink)
+	if err := r.PutBatch(context.Background(), records("A")); !IsPermanent(err) {
+		t.Errorf("got %v, want the permanent error", err)
+	}
+	if len(next.batches) != 1 {
+		t.Errorf("a permanent error was retried: %v", next.batches)
+	}
+}
@@ -0,0 +1,37 @@
+package sink
+
+import "context"
+
+// Record is a single encoded flight report along with the key used to
+// route it to a shard or partition and the aircraft and flight it belongs
+// to.
+type Record struct {
+	PartitionKey string
+	TailNum      string
+	FlightID     string
+	Data         []byte
+}
+
+// Sink is a destination that flight reports are written to.
+type Sink interface {
+	Put(ctx context.Context, record Record) error
+	Close() error
+}
+
+// BatchSink is implemented by sinks that can write several records in a
+// single request. When only part of a batch could not be written, PutBatch
+// returns a *BatchError holding that part.
+type BatchSink interface {
+	Sink
+	PutBatch(ctx context.Context, records []Record) error
+}
+
+// BatchError reports the records of a batch that were not written, in
+// their original order, so that they alone can be retried.
+type BatchError struct {
+	Failed []Record
+	Err    error
+}
+
+func (e *BatchError) Error() string { return e.Err.Error() }
+func (e *BatchError) Unwrap() error { return e.Err }
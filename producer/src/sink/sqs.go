@@ -0,0 +1,149 @@
+package sink
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// maxSendMessageBatch is the most messages SQS accepts in one batch call.
+const maxSendMessageBatch = 10
+
+// SQSConfig holds the settings for an SQS sink. Queues whose URL ends in
+// ".fifo" are treated as FIFO queues.
+type SQSConfig struct {
+	QueueURL string
+	AWSOptions
+}
+
+type sqsAPI interface {
+	SendMessage(ctx context.Context, in *sqs.SendMessageInput, opts ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+	SendMessageBatch(ctx context.Context, in *sqs.SendMessageBatchInput, opts ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error)
+}
+
+// SQS sends records as messages to an AWS SQS queue. On FIFO queues the
+// message group is the record's tail number, preserving per-aircraft
+// ordering, and the deduplication ID is a hash of the payload.
+//
+// SQS message bodies must be text, so this sink should be paired with a
+// text encoding.
+type SQS struct {
+	client sqsAPI
+	cfg    SQSConfig
+	fifo   bool
+}
+
+// NewSQS creates an SQS sink using the default AWS credential chain.
+func NewSQS(ctx context.Context, cfg SQSConfig) (*SQS, error) {
+	if cfg.QueueURL == "" {
+		return nil, errors.New("sqs: queue url is required")
+	}
+
+	awsCfg, err := LoadAWSConfig(ctx, cfg.AWSOptions)
+	if err != nil {
+		return nil, fmt.Errorf("sqs: %w", err)
+	}
+
+	return &SQS{
+		client: sqs.NewFromConfig(awsCfg),
+		cfg:    cfg,
+		fifo:   strings.HasSuffix(cfg.QueueURL, ".fifo"),
+	}, nil
+}
+
+func (s *SQS) fifoFields(record Record) (groupID, dedupID *string, err error) {
+	if !s.fifo {
+		return nil, nil, nil
+	}
+	if record.TailNum == "" {
+		return nil, nil, errors.New("sqs: record has no tail number for fifo message group")
+	}
+
+	sum := sha256.Sum256(record.Data)
+	return aws.String(record.TailNum), aws.String(hex.EncodeToString(sum[:])), nil
+}
+
+// Put sends a single record as a message.
+func (s *SQS) Put(ctx context.Context, record Record) error {
+	groupID, dedupID, err := s.fifoFields(record)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:               aws.String(s.cfg.QueueURL),
+		MessageBody:            aws.String(string(record.Data)),
+		MessageGroupId:         groupID,
+		MessageDeduplicationId: dedupID,
+	})
+	if err != nil {
+		return fmt.Errorf("sqs: send message: %w", err)
+	}
+	return nil
+}
+
+// PutBatch sends records with SendMessageBatch, ten at a time. If any
+// message was rejected, or a request failed, a *BatchError holds every
+// record not sent.
+func (s *SQS) PutBatch(ctx context.Context, records []Record) error {
+	var failed []Record
+	var err error
+	for start := 0; start < len(records); start += maxSendMessageBatch {
+		end := min(start+maxSendMessageBatch, len(records))
+		entries := make([]types.SendMessageBatchRequestEntry, 0, end-start)
+		for i, record := range records[start:end] {
+			groupID, dedupID, err := s.fifoFields(record)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, types.SendMessageBatchRequestEntry{
+				Id:                     aws.String(strconv.Itoa(i)),
+				MessageBody:            aws.String(string(record.Data)),
+				MessageGroupId:         groupID,
+				MessageDeduplicationId: dedupID,
+			})
+		}
+
+		out, sendErr := s.client.SendMessageBatch(ctx, &sqs.SendMessageBatchInput{
+			QueueUrl: aws.String(s.cfg.QueueURL),
+			Entries:  entries,
+		})
+		if sendErr != nil {
+			err = fmt.Errorf("sqs: send message batch: %w", sendErr)
+			failed = append(failed, records[start:end]...)
+			continue
+		}
+		if len(out.Failed) == 0 {
+			continue
+		}
+		rejected := make(map[int]bool, len(out.Failed))
+		for _, f := range out.Failed {
+			i, _ := strconv.Atoi(aws.ToString(f.Id))
+			rejected[i] = true
+		}
+		for i, record := range records[start:end] {
+			if rejected[i] {
+				failed = append(failed, record)
+			}
+		}
+		err = fmt.Errorf("sqs: %d of %d messages failed: %s",
+			len(out.Failed), len(entries), aws.ToString(out.Failed[0].Message))
+	}
+	if err != nil {
+		return &BatchError{Failed: failed, Err: err}
+	}
+	return nil
+}
+
+// Close is a no-op; the SQS client holds no resources that need releasing.
+func (s *SQS) Close() error {
+	return nil
+}
@@ -0,0 +1,107 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+)
+
+// TCPServer listens for TCP connections and streams every record to all
+// connected clients, the way ADS-B receivers serve their BaseStation feed.
+// Records are written as-is, so the encoder is responsible for line endings.
+// Clients that fall behind or disconnect are dropped.
+type TCPServer struct {
+	listener     net.Listener
+	writeTimeout time.Duration
+
+	mu      sync.Mutex
+	clients map[net.Conn]struct{}
+	closed  bool
+}
+
+// NewTCPServer starts listening on addr, e.g. ":30003".
+func NewTCPServer(addr string, writeTimeout time.Duration) (*TCPServer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("tcp: %w", err)
+	}
+	if writeTimeout <= 0 {
+		writeTimeout = 5 * time.Second
+	}
+
+	s := &TCPServer{
+		listener:     listener,
+		writeTimeout: writeTimeout,
+		clients:      make(map[net.Conn]struct{}),
+	}
+	go s.accept()
+	return s, nil
+}
+
+// Addr returns the address the server is listening on.
+func (s *TCPServer) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+func (s *TCPServer) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Printf("tcp: accept: %v", err)
+			}
+			return
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			conn.Close()
+		} else {
+			s.clients[conn] = struct{}{}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Put writes a record to every connected client. Having no clients is not
+// an error.
+func (s *TCPServer) Put(_ context.Context, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return errors.New("tcp: server is closed")
+	}
+
+	for conn := range s.clients {
+		conn.SetWriteDeadline(time.Now().Add(s.writeTimeout))
+		if _, err := conn.Write(record.Data); err != nil {
+			log.Printf("tcp: dropping client %s: %v", conn.RemoteAddr(), err)
+			conn.Close()
+			delete(s.clients, conn)
+		}
+	}
+	return nil
+}
+
+// Close stops listening and disconnects all clients.
+func (s *TCPServer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	err := s.listener.Close()
+	for conn := range s.clients {
+		conn.Close()
+		delete(s.clients, conn)
+	}
+	return err
+}
@@ -0,0 +1,172 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// WebhookConfig holds the settings for an HTTP webhook sink.
+//
+// Failed deliveries are retried up to MaxAttempts times in total, waiting
+// InitialBackoff before the first retry and doubling up to MaxBackoff after
+// that. Requests are authenticated with BearerToken when set, or signed with
+// AWS SigV4 for SigV4Service (e.g. "execute-api") when that is set.
+type WebhookConfig struct {
+	URL         string
+	ContentType string
+	Headers     map[string]string
+	Timeout     time.Duration
+
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	BearerToken  string
+	SigV4Service string
+	SigV4Region  string
+}
+
+// Webhook POSTs each record to an HTTP endpoint.
+type Webhook struct {
+	cfg    WebhookConfig
+	client *http.Client
+
+	signer      *v4.Signer
+	credentials aws.CredentialsProvider
+	region      string
+}
+
+// NewWebhook creates a webhook sink, filling in defaults for any unset
+// delivery settings.
+func NewWebhook(ctx context.Context, cfg WebhookConfig) (*Webhook, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("webhook: url is required")
+	}
+	if cfg.BearerToken != "" && cfg.SigV4Service != "" {
+		return nil, errors.New("webhook: bearer token and sigv4 signing are mutually exclusive")
+	}
+	if cfg.ContentType == "" {
+		cfg.ContentType = "application/json"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = 200 * time.Millisecond
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 10 * time.Second
+	}
+
+	w := &Webhook{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}
+
+	if cfg.SigV4Service != "" {
+		awsCfg, err := LoadAWSConfig(ctx, AWSOptions{Region: cfg.SigV4Region})
+		if err != nil {
+			return nil, fmt.Errorf("webhook: %w", err)
+		}
+		w.signer = v4.NewSigner()
+		w.credentials = awsCfg.Credentials
+		w.region = awsCfg.Region
+	}
+
+	return w, nil
+}
+
+// Put delivers a single record, retrying with exponential backoff on network
+// errors, 429 and 5xx responses. Other failures are returned as Permanent.
+func (w *Webhook) Put(ctx context.Context, record Record) error {
+	backoff := w.cfg.InitialBackoff
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		var retry bool
+		retry, err = w.deliver(ctx, record)
+		if err == nil {
+			return nil
+		}
+		if !retry {
+			return Permanent(fmt.Errorf("webhook: %w", err))
+		}
+		if attempt >= w.cfg.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+		if backoff > w.cfg.MaxBackoff {
+			backoff = w.cfg.MaxBackoff
+		}
+	}
+
+	return fmt.Errorf("webhook: %w", err)
+}
+
+func (w *Webhook) deliver(ctx context.Context, record Record) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.URL, bytes.NewReader(record.Data))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", w.cfg.ContentType)
+	for k, v := range w.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	if w.cfg.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+w.cfg.BearerToken)
+	}
+	if w.signer != nil {
+		if err := w.sign(ctx, req, record.Data); err != nil {
+			return false, err
+		}
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("unexpected status %s", resp.Status)
+	default:
+		return false, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+}
+
+func (w *Webhook) sign(ctx context.Context, req *http.Request, body []byte) error {
+	creds, err := w.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("retrieving aws credentials: %w", err)
+	}
+
+	sum := sha256.Sum256(body)
+	return w.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(sum[:]), w.cfg.SigV4Service, w.region, time.Now())
+}
+
+// Close releases idle connections.
+func (w *Webhook) Close() error {
+	w.client.CloseIdleConnections()
+	return nil
+}
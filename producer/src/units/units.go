@@ -0,0 +1,185 @@
+package units
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	metresPerFoot  = 0.3048
+	kmPerNm        = 1.852
+	statuteMiPerNm = 1852 / 1609.344
+)
+
+// Speed is a speed in knots, the unit the simulator works in.
+type Speed float64
+
+func Knots(v float64) Speed { return Speed(v) }
+func MPH(v float64) Speed   { return Speed(v / statuteMiPerNm) }
+func KMH(v float64) Speed   { return Speed(v / kmPerNm) }
+
+func (s Speed) Knots() float64 { return float64(s) }
+func (s Speed) MPH() float64   { return float64(s) * statuteMiPerNm }
+func (s Speed) KMH() float64   { return float64(s) * kmPerNm }
+
+// Length is a height or altitude in feet.
+type Length float64
+
+func Feet(v float64) Length   { return Length(v) }
+func Metres(v float64) Length { return Length(v / metresPerFoot) }
+
+func (l Length) Feet() float64   { return float64(l) }
+func (l Length) Metres() float64 { return float64(l) * metresPerFoot }
+
+// Distance is a distance over the ground in nautical miles.
+type Distance float64
+
+func NauticalMiles(v float64) Distance { return Distance(v) }
+func Kilometres(v float64) Distance    { return Distance(v / kmPerNm) }
+func StatuteMiles(v float64) Distance  { return Distance(v / statuteMiPerNm) }
+
+func (d Distance) NauticalMiles() float64 { return float64(d) }
+func (d Distance) Kilometres() float64    { return float64(d) * kmPerNm }
+func (d Distance) StatuteMiles() float64  { return float64(d) * statuteMiPerNm }
+
+// VerticalSpeed is a rate of climb or descent in feet per minute.
+type VerticalSpeed float64
+
+func FeetPerMinute(v float64) VerticalSpeed   { return VerticalSpeed(v) }
+func FeetPerSecond(v float64) VerticalSpeed   { return VerticalSpeed(v * 60) }
+func MetresPerSecond(v float64) VerticalSpeed { return VerticalSpeed(v * 60 / metresPerFoot) }
+
+func (v VerticalSpeed) FeetPerMinute() float64   { return float64(v) }
+func (v VerticalSpeed) FeetPerSecond() float64   { return float64(v) / 60 }
+func (v VerticalSpeed) MetresPerSecond() float64 { return float64(v) * metresPerFoot / 60 }
+
+// Over returns the height gained in d, negative when descending.
+func (v VerticalSpeed) Over(d time.Duration) Length {
+	return Length(float64(v) * d.Minutes())
+}
+
+// VerticalSpeedUnit is the unit vertical speeds are written in.
+type VerticalSpeedUnit string
+
+const (
+	FPM VerticalSpeedUnit = "fpm"
+	FPS VerticalSpeedUnit = "fps"
+	MPS VerticalSpeedUnit = "mps"
+)
+
+// ParseVerticalSpeedUnit parses a vertical speed unit name. An empty name
+// is FPM.
+func ParseVerticalSpeedUnit(s string) (VerticalSpeedUnit, error) {
+	switch VerticalSpeedUnit(s) {
+	case "", FPM:
+		return FPM, nil
+	case FPS, MPS:
+		return VerticalSpeedUnit(s), nil
+	}
+	return "", fmt.Errorf("unknown vertical speed unit %q", s)
+}
+
+// Express expresses v in the unit.
+func (u VerticalSpeedUnit) Express(v VerticalSpeed) float64 {
+	switch u {
+	case FPS:
+		return v.FeetPerSecond()
+	case MPS:
+		return v.MetresPerSecond()
+	}
+	return v.FeetPerMinute()
+}
+
+// Of reads v as a vertical speed in the unit.
+func (u VerticalSpeedUnit) Of(v float64) VerticalSpeed {
+	switch u {
+	case FPS:
+		return FeetPerSecond(v)
+	case MPS:
+		return MetresPerSecond(v)
+	}
+	return FeetPerMinute(v)
+}
+
+// System is the set of units reports are written in.
+type System string
+
+const (
+	// Imperial is the aviation convention: knots, feet and nautical miles.
+	Imperial System = "imperial"
+	// Metric uses kilometres per hour, metres and kilometres.
+	Metric System = "metric"
+	// Statute uses miles per hour, feet and statute miles.
+	Statute System = "statute"
+)
+
+// ParseSystem parses a unit system name. An empty name is Imperial.
+func ParseSystem(s string) (System, error) {
+	switch System(s) {
+	case "", Imperial:
+		return Imperial, nil
+	case Metric, Statute:
+		return System(s), nil
+	}
+	return "", fmt.Errorf("unknown unit system %q", s)
+}
+
+// Speed expresses s in the system's speed unit.
+func (u System) Speed(s Speed) float64 {
+	switch u {
+	case Metric:
+		return s.KMH()
+	case Statute:
+		return s.MPH()
+	}
+	return s.Knots()
+}
+
+// SpeedOf reads v as a speed in the system's speed unit.
+func (u System) SpeedOf(v float64) Speed {
+	switch u {
+	case Metric:
+		return KMH(v)
+	case Statute:
+		return MPH(v)
+	}
+	return Knots(v)
+}
+
+// Length expresses l in the system's altitude unit.
+func (u System) Length(l Length) float64 {
+	if u == Metric {
+		return l.Metres()
+	}
+	return l.Feet()
+}
+
+// LengthOf reads v as a height in the system's altitude unit.
+func (u System) LengthOf(v float64) Length {
+	if u == Metric {
+		return Metres(v)
+	}
+	return Feet(v)
+}
+
+// Distance expresses d in the system's distance unit.
+func (u System) Distance(d Distance) float64 {
+	switch u {
+	case Metric:
+		return d.Kilometres()
+	case Statute:
+		return d.StatuteMiles()
+	}
+	return d.NauticalMiles()
+}
+
+// DistanceOf reads v as a distance in the system's distance unit.
+func (u System) DistanceOf(v float64) Distance {
+	switch u {
+	case Metric:
+		return Kilometres(v)
+	case Statute:
+		return StatuteMiles(v)
+	}
+	return NauticalMiles(v)
+}
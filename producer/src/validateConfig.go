@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"plane-producer/src/config"
+)
+
+// redacted replaces secrets when printing the effective configuration.
+const redacted = "<redacted>"
+
+func runValidateConfig(args []string) error {
+	fs := flag.NewFlagSet("validate-config", flag.ExitOnError)
+	configPath := fs.String("config", "", "YAML configuration file")
+	fs.Parse(args)
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Sink.MQTT.Password != "" {
+		cfg.Sink.MQTT.Password = redacted
+	}
+	if cfg.Sink.Webhook.BearerToken != "" {
+		cfg.Sink.Webhook.BearerToken = redacted
+	}
+
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2)
+	defer enc.Close()
+	return enc.Encode(cfg)
+}
@@ -0,0 +1,175 @@
+package weather
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+
+	"plane-producer/src/geo"
+)
+
+// Severity grades the turbulence inside a weather cell.
+type Severity uint8
+
+const (
+	Light Severity = iota + 1
+	Moderate
+	Severe
+)
+
+var severityNames = map[Severity]string{
+	Light:    "light",
+	Moderate: "moderate",
+	Severe:   "severe",
+}
+
+func (s Severity) String() string {
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("Severity(%d)", uint8(s))
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	if _, ok := severityNames[s]; !ok {
+		return nil, fmt.Errorf("invalid severity %d", uint8(s))
+	}
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(text []byte) error {
+	for sev, name := range severityNames {
+		if name == string(text) {
+			*s = sev
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown severity %q", text)
+}
+
+// Response is how an aircraft should react to a cell of a given severity:
+// light cells are flown through with a turbulence report, moderate cells
+// also call for slowing to turbulence penetration speed, and severe cells
+// are deviated around.
+type Response struct {
+	ReportTurbulence bool
+	SpeedFactor      float64
+	Avoid            bool
+}
+
+func (s Severity) Response() Response {
+	switch s {
+	case Light:
+		return Response{ReportTurbulence: true, SpeedFactor: 1}
+	case Moderate:
+		return Response{ReportTurbulence: true, SpeedFactor: 0.9}
+	default:
+		return Response{Avoid: true, SpeedFactor: 1}
+	}
+}
+
+// Cell is an area of weather.
+type Cell struct {
+	ID       string      `json:"id"`
+	Severity Severity    `json:"severity"`
+	Area     geo.Polygon `json:"area"`
+}
+
+// Zones is the set of weather cells active in a simulation.
+type Zones []Cell
+
+// Wind is a wind blowing from a true direction, the same everywhere aloft.
+type Wind struct {
+	From  geo.Degrees `json:"from"`
+	Knots float64     `json:"knots"`
+}
+
+// Correct returns the heading to fly to make good a track at airspeed, and
+// the resulting ground speed. A crosswind too strong to hold the track
+// against is crabbed into as far as it can be.
+func (w Wind) Correct(track geo.Degrees, airspeed float64) (heading geo.Degrees, groundSpeed float64) {
+	if w.Knots == 0 || airspeed <= 0 {
+		return track, airspeed
+	}
+	off := (w.From - track).Radians()
+	cross, head := w.Knots*math.Sin(float64(off)), w.Knots*math.Cos(float64(off))
+	correction := math.Asin(math.Max(-1, math.Min(cross/airspeed, 1)))
+	heading = (track + geo.Radians(correction).Degrees()).Normalized()
+	return heading, math.Max(airspeed*math.Cos(correction)-head, 0)
+}
+
+// Weather is the wind and weather cells of a simulation.
+type Weather struct {
+	Wind  Wind  `json:"wind"`
+	Cells Zones `json:"cells"`
+}
+
+// Load reads the weather from a JSON file: an object with the wind and the
+// cells, or just an array of cells for still air.
+func Load(path string) (Weather, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Weather{}, err
+	}
+
+	var w Weather
+	if b = bytes.TrimSpace(b); len(b) > 0 && b[0] == '[' {
+		err = json.Unmarshal(b, &w.Cells)
+	} else {
+		err = json.Unmarshal(b, &w)
+	}
+	if err != nil {
+		return Weather{}, fmt.Errorf("weather: %w", err)
+	}
+	if w.Wind.Knots < 0 || math.IsNaN(w.Wind.Knots) {
+		return Weather{}, fmt.Errorf("weather: wind speed %v is negative", w.Wind.Knots)
+	}
+	for _, c := range w.Cells {
+		if len(c.Area) < 3 {
+			return Weather{}, fmt.Errorf("weather: cell %q needs at least 3 vertices", c.ID)
+		}
+		if _, ok := severityNames[c.Severity]; !ok {
+			return Weather{}, fmt.Errorf("weather: cell %q has no severity", c.ID)
+		}
+	}
+	return w, nil
+}
+
+// At returns the most severe cell containing p, if any.
+func (z Zones) At(p geo.Position) (Cell, bool) {
+	var worst Cell
+	found := false
+	for _, c := range z {
+		if c.Area.Contains(p) && (!found || c.Severity > worst.Severity) {
+			worst, found = c, true
+		}
+	}
+	return worst, found
+}
+
+// Crossed returns the cells a route passes through, sampled every stepNm
+// nautical miles, so it can be checked for cells to deviate around before
+// it is flown.
+func (z Zones) Crossed(track geo.Route, stepNm float64) Zones {
+	if stepNm <= 0 {
+		stepNm = 5
+	}
+
+	seen := make(map[string]bool)
+	var crossed Zones
+	for d := 0.0; ; d += stepNm {
+		p := track.PositionAt(d)
+		for _, c := range z {
+			if !seen[c.ID] && c.Area.Contains(p) {
+				seen[c.ID] = true
+				crossed = append(crossed, c)
+			}
+		}
+		if d >= track.Length() {
+			break
+		}
+	}
+	return crossed
+}
@@ -0,0 +1,36 @@
+package weather
+
+import (
+	"math"
+	"testing"
+
+	"plane-producer/src/geo"
+)
+
+func TestCorrect(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		wind        Wind
+		track       geo.Degrees
+		airspeed    float64
+		heading     geo.Degrees
+		groundSpeed float64
+	}{
+		{"still air", Wind{}, 90, 450, 90, 450},
+		{"headwind", Wind{From: 90, Knots: 50}, 90, 450, 90, 400},
+		{"tailwind", Wind{From: 270, Knots: 50}, 90, 450, 90, 500},
+		// sin 30° = 0.5 of 400 knots.
+		{"crosswind from the right", Wind{From: 180, Knots: 200}, 90, 400, 120, 400 * math.Sqrt(3) / 2},
+		{"crosswind from the left", Wind{From: 0, Knots: 200}, 90, 400, 60, 400 * math.Sqrt(3) / 2},
+		{"across north", Wind{From: 90, Knots: 200}, 0, 400, 30, 400 * math.Sqrt(3) / 2},
+		{"too strong to hold", Wind{From: 180, Knots: 500}, 90, 400, 180, 0},
+		{"on the ground", Wind{From: 180, Knots: 30}, 90, 0, 90, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			heading, gs := tc.wind.Correct(tc.track, tc.airspeed)
+			if math.Abs(float64((heading-tc.heading).Signed())) > 1e-9 || math.Abs(gs-tc.groundSpeed) > 1e-9 {
+				t.Errorf("heading %v at %v knots, want %v at %v", heading, gs, tc.heading, tc.groundSpeed)
+			}
+		})
+	}
+}